@@ -104,6 +104,9 @@ var depsRules = `
 	RUNTIME
 	< io;
 
+	io
+	< hash;
+
 	syscall !< io;
 	reflect !< sort;
 
@@ -164,7 +167,9 @@ var depsRules = `
 	# OS does not include reflection.
 	io/fs
 	< internal/testlog
-	< internal/poll
+	< internal/poll;
+
+	internal/poll, hash
 	< os
 	< os/signal;
 
@@ -233,8 +238,7 @@ var depsRules = `
 	  encoding/json, encoding/pem, encoding/xml, mime;
 
 	# hashes
-	io
-	< hash
+	hash
 	< hash/adler32, hash/crc32, hash/crc64, hash/fnv, hash/maphash;
 
 	# math/big
@@ -12,6 +12,7 @@ import (
 const (
 	AT_REMOVEDIR        = 0x800
 	AT_SYMLINK_NOFOLLOW = 0x200
+	AT_FDCWD            = -0x64
 )
 
 func Unlinkat(dirfd int, path string, flags int) error {
@@ -45,3 +46,5 @@ func Openat(dirfd int, path string, flags int, perm uint32) (int, error) {
 func Fstatat(dirfd int, path string, stat *syscall.Stat_t, flags int) error {
 	return syscall.Fstatat(dirfd, path, stat, flags)
 }
+
+const fchmodatTrap uintptr = syscall.SYS_FCHMODAT
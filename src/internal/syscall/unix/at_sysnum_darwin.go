@@ -6,3 +6,4 @@ package unix
 
 const AT_REMOVEDIR = 0x80
 const AT_SYMLINK_NOFOLLOW = 0x0020
+const AT_FDCWD = -0x2
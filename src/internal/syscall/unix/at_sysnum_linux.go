@@ -11,3 +11,6 @@ const openatTrap uintptr = syscall.SYS_OPENAT
 
 const AT_REMOVEDIR = 0x200
 const AT_SYMLINK_NOFOLLOW = 0x100
+const AT_FDCWD = -0x64
+
+const O_PATH = 0x200000
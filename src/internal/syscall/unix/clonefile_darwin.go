@@ -0,0 +1,34 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package unix
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+//go:cgo_import_dynamic libc_clonefile clonefile "/usr/lib/libSystem.B.dylib"
+
+func libc_clonefile_trampoline()
+
+// Clonefile calls the macOS clonefile system call.
+func Clonefile(src, dst string, flags int) error {
+	p0, err := syscall.BytePtrFromString(src)
+	if err != nil {
+		return err
+	}
+	p1, err := syscall.BytePtrFromString(dst)
+	if err != nil {
+		return err
+	}
+	_, _, errno := syscall_syscall(funcPC(libc_clonefile_trampoline),
+		uintptr(unsafe.Pointer(p0)),
+		uintptr(unsafe.Pointer(p1)),
+		uintptr(flags))
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
@@ -0,0 +1,29 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package unix
+
+// Values of FileDedupeRangeInfo.Status, other than negated errnos.
+const (
+	FILE_DEDUPE_RANGE_SAME    = 0
+	FILE_DEDUPE_RANGE_DIFFERS = 1
+)
+
+// FileDedupeRange is the header of the FIDEDUPERANGE argument, which is
+// followed in memory by DestCount FileDedupeRangeInfo structures.
+type FileDedupeRange struct {
+	SrcOffset uint64
+	SrcLength uint64
+	DestCount uint16
+	Reserved1 uint16
+	Reserved2 uint32
+}
+
+type FileDedupeRangeInfo struct {
+	DestFd       int64
+	DestOffset   uint64
+	BytesDeduped uint64
+	Status       int32
+	Reserved     uint32
+}
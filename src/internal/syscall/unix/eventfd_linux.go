@@ -0,0 +1,21 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package unix
+
+import "syscall"
+
+// EFD_SEMAPHORE makes reads from an eventfd decrement its counter by
+// one rather than reset it to zero.
+const EFD_SEMAPHORE = 0x1
+
+// Eventfd creates a file descriptor for event notification whose
+// counter starts at initval.
+func Eventfd(initval uint, flags int) (int, error) {
+	fd, _, errno := syscall.Syscall(syscall.SYS_EVENTFD2, uintptr(initval), uintptr(flags), 0)
+	if errno != 0 {
+		return -1, errno
+	}
+	return int(fd), nil
+}
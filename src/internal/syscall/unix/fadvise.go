@@ -0,0 +1,12 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build freebsd || linux
+// +build freebsd linux
+
+package unix
+
+// POSIX_FADV_SEQUENTIAL advises the kernel that a file will be read
+// sequentially, from lower offsets to higher ones.
+const POSIX_FADV_SEQUENTIAL = 2
@@ -0,0 +1,23 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package unix
+
+import "syscall"
+
+// Fadvise announces to the kernel the intended pattern of access to
+// the whole of the file open as fd.
+func Fadvise(fd int, advice int) error {
+	// The 64-bit offset and length are each passed as an aligned
+	// pair of words, after a padding word.
+	r1, _, errno := syscall.Syscall9(syscall.SYS_POSIX_FADVISE, uintptr(fd), 0, 0, 0, 0, 0, uintptr(advice), 0, 0)
+	if errno != 0 {
+		return errno
+	}
+	// posix_fadvise may report failure in its result rather than errno.
+	if r1 != 0 {
+		return syscall.Errno(r1)
+	}
+	return nil
+}
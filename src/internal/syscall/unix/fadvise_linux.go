@@ -0,0 +1,20 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build linux && !386 && !arm && !mips && !mipsle
+// +build linux,!386,!arm,!mips,!mipsle
+
+package unix
+
+import "syscall"
+
+// Fadvise announces to the kernel the intended pattern of access to
+// the whole of the file open as fd.
+func Fadvise(fd int, advice int) error {
+	_, _, errno := syscall.Syscall6(syscall.SYS_FADVISE64, uintptr(fd), 0, 0, uintptr(advice), 0, 0)
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
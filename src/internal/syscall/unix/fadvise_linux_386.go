@@ -0,0 +1,18 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package unix
+
+import "syscall"
+
+// Fadvise announces to the kernel the intended pattern of access to
+// the whole of the file open as fd.
+func Fadvise(fd int, advice int) error {
+	// The offset and length are each passed as two words.
+	_, _, errno := syscall.Syscall6(syscall.SYS_FADVISE64_64, uintptr(fd), 0, 0, 0, 0, uintptr(advice))
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
@@ -0,0 +1,19 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package unix
+
+import "syscall"
+
+// Fadvise announces to the kernel the intended pattern of access to
+// the whole of the file open as fd.
+func Fadvise(fd int, advice int) error {
+	// On ARM the advice comes second, so that the 64-bit offset
+	// and length are aligned to register pairs.
+	_, _, errno := syscall.Syscall6(syscall.SYS_ARM_FADVISE64_64, uintptr(fd), uintptr(advice), 0, 0, 0, 0)
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
@@ -0,0 +1,33 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package unix
+
+import "syscall"
+
+const (
+	FAN_CLOEXEC  = 0x1
+	FAN_NONBLOCK = 0x2
+
+	FAN_ALLOW = 0x1
+	FAN_DENY  = 0x2
+
+	// FAN_NOFD is the file descriptor of an event that has no file,
+	// such as a queue overflow.
+	FAN_NOFD = -1
+
+	// FANOTIFY_METADATA_VERSION is the version of struct
+	// fanotify_event_metadata that this package understands.
+	FANOTIFY_METADATA_VERSION = 3
+)
+
+// FanotifyInit creates a fanotify group and returns a file descriptor
+// for reading its events.
+func FanotifyInit(flags uint, eventFlags uint) (int, error) {
+	fd, _, errno := syscall.Syscall(syscall.SYS_FANOTIFY_INIT, uintptr(flags), uintptr(eventFlags), 0)
+	if errno != 0 {
+		return -1, errno
+	}
+	return int(fd), nil
+}
@@ -0,0 +1,21 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package unix
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+// FanotifyMark adds, removes or modifies the marks of the fanotify
+// group fd on the file or directory path relative to dirfd.
+func FanotifyMark(fd int, flags uint, mask uint64, dirfd int, path *byte) error {
+	// The 64-bit mask is passed as two words, high word first.
+	_, _, errno := syscall.Syscall6(syscall.SYS_FANOTIFY_MARK, uintptr(fd), uintptr(flags), uintptr(mask>>32), uintptr(mask), uintptr(dirfd), uintptr(unsafe.Pointer(path)))
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
@@ -0,0 +1,30 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package unix
+
+const (
+	FIEMAP_FLAG_SYNC   = 0x1
+	FIEMAP_EXTENT_LAST = 0x1
+)
+
+// Fiemap is the header of the FS_IOC_FIEMAP argument, which is followed
+// in memory by ExtentCount FiemapExtent structures.
+type Fiemap struct {
+	Start         uint64
+	Length        uint64
+	Flags         uint32
+	MappedExtents uint32
+	ExtentCount   uint32
+	Reserved      uint32
+}
+
+type FiemapExtent struct {
+	Logical    uint64
+	Physical   uint64
+	Length     uint64
+	Reserved64 [2]uint64
+	Flags      uint32
+	Reserved   [3]uint32
+}
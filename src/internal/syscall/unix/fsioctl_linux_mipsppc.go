@@ -0,0 +1,19 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build linux && (mips || mipsle || mips64 || mips64le || ppc64 || ppc64le)
+// +build linux
+// +build mips mipsle mips64 mips64le ppc64 ppc64le
+
+package unix
+
+// File system ioctl requests, from linux/fs.h. These architectures
+// encode the direction of the transfer in the top three bits.
+const (
+	FICLONE           = 0x80049409
+	FIDEDUPERANGE     = 0xc0189436
+	FS_IOC_FIEMAP     = 0xc020660b
+	FS_IOC_FSGETXATTR = 0x401c581f
+	FS_IOC_FSSETXATTR = 0x801c5820
+)
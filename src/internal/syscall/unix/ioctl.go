@@ -0,0 +1,31 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build dragonfly || freebsd || linux || netbsd || openbsd
+// +build dragonfly freebsd linux netbsd openbsd
+
+package unix
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+// Ioctl performs the ioctl system call.
+func Ioctl(fd int, cmd uint, args uintptr) error {
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, uintptr(fd), uintptr(cmd), args)
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+// IoctlPtr performs the ioctl system call with a pointer argument.
+func IoctlPtr(fd int, cmd uint, arg unsafe.Pointer) error {
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, uintptr(fd), uintptr(cmd), uintptr(arg))
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
@@ -0,0 +1,29 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package unix
+
+import "unsafe"
+
+//go:cgo_import_dynamic libc_ioctl ioctl "/usr/lib/libSystem.B.dylib"
+
+func libc_ioctl_trampoline()
+
+// Ioctl performs the ioctl system call.
+func Ioctl(fd int, cmd uint, args uintptr) error {
+	_, _, errno := syscall_syscall(funcPC(libc_ioctl_trampoline), uintptr(fd), uintptr(cmd), args)
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+// IoctlPtr performs the ioctl system call with a pointer argument.
+func IoctlPtr(fd int, cmd uint, arg unsafe.Pointer) error {
+	_, _, errno := syscall_syscall(funcPC(libc_ioctl_trampoline), uintptr(fd), uintptr(cmd), uintptr(arg))
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
@@ -0,0 +1,31 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package unix
+
+import "unsafe"
+
+//go:cgo_import_dynamic libc_ioctl ioctl "libc.so"
+
+//go:linkname procIoctl libc_ioctl
+
+var procIoctl uintptr
+
+// Ioctl performs the ioctl system call.
+func Ioctl(fd int, cmd uint, args uintptr) error {
+	_, _, errno := syscall6(uintptr(unsafe.Pointer(&procIoctl)), 3, uintptr(fd), uintptr(cmd), args, 0, 0, 0)
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+// IoctlPtr performs the ioctl system call with a pointer argument.
+func IoctlPtr(fd int, cmd uint, arg unsafe.Pointer) error {
+	_, _, errno := syscall6(uintptr(unsafe.Pointer(&procIoctl)), 3, uintptr(fd), uintptr(cmd), uintptr(arg), 0, 0, 0)
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
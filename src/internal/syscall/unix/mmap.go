@@ -0,0 +1,60 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build dragonfly || freebsd || linux || netbsd || openbsd
+// +build dragonfly freebsd linux netbsd openbsd
+
+package unix
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+// Msync flushes changes made to the mapped memory b back to the
+// underlying file.
+func Msync(b []byte, flags int) error {
+	if len(b) == 0 {
+		return nil
+	}
+	_, _, errno := syscall.Syscall(msyncTrap, uintptr(unsafe.Pointer(&b[0])), uintptr(len(b)), uintptr(flags))
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+// Madvise advises the kernel about the expected use of the mapped
+// memory b.
+func Madvise(b []byte, advice int) error {
+	if len(b) == 0 {
+		return nil
+	}
+	_, _, errno := syscall.Syscall(syscall.SYS_MADVISE, uintptr(unsafe.Pointer(&b[0])), uintptr(len(b)), uintptr(advice))
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+// Mlock locks the pages containing b into memory.
+func Mlock(b []byte) error {
+	return memlockSyscall(syscall.SYS_MLOCK, b)
+}
+
+// Munlock unlocks the pages containing b.
+func Munlock(b []byte) error {
+	return memlockSyscall(syscall.SYS_MUNLOCK, b)
+}
+
+func memlockSyscall(trap uintptr, b []byte) error {
+	if len(b) == 0 {
+		return nil
+	}
+	_, _, errno := syscall.Syscall(trap, uintptr(unsafe.Pointer(&b[0])), uintptr(len(b)), 0)
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package unix
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+const MS_SYNC = syscall.MS_SYNC
+
+//go:cgo_import_dynamic libc_msync msync "/usr/lib/libSystem.B.dylib"
+
+func libc_msync_trampoline()
+
+// Msync flushes changes made to the mapped memory b back to the
+// underlying file.
+func Msync(b []byte, flags int) error {
+	if len(b) == 0 {
+		return nil
+	}
+	_, _, errno := syscall_syscall(funcPC(libc_msync_trampoline),
+		uintptr(unsafe.Pointer(&b[0])),
+		uintptr(len(b)),
+		uintptr(flags))
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+//go:cgo_import_dynamic libc_madvise madvise "/usr/lib/libSystem.B.dylib"
+
+func libc_madvise_trampoline()
+
+// Madvise advises the kernel about the expected use of the mapped
+// memory b.
+func Madvise(b []byte, advice int) error {
+	if len(b) == 0 {
+		return nil
+	}
+	_, _, errno := syscall_syscall(funcPC(libc_madvise_trampoline),
+		uintptr(unsafe.Pointer(&b[0])),
+		uintptr(len(b)),
+		uintptr(advice))
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+// Mlock locks the pages containing b into memory.
+func Mlock(b []byte) error {
+	if len(b) == 0 {
+		return nil
+	}
+	return syscall.Mlock(b)
+}
+
+// Munlock unlocks the pages containing b.
+func Munlock(b []byte) error {
+	if len(b) == 0 {
+		return nil
+	}
+	return syscall.Munlock(b)
+}
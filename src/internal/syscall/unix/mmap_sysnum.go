@@ -0,0 +1,14 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build dragonfly || freebsd || linux || openbsd
+// +build dragonfly freebsd linux openbsd
+
+package unix
+
+import "syscall"
+
+const msyncTrap uintptr = syscall.SYS_MSYNC
+
+const MS_SYNC = syscall.MS_SYNC
@@ -0,0 +1,9 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package unix
+
+const msyncTrap uintptr = 277 // SYS___MSYNC13
+
+const MS_SYNC = 0x4 // not defined in package syscall on netbsd/arm
@@ -13,6 +13,15 @@ import "syscall"
 // usually SYS_FCNTL, but can be overridden to SYS_FCNTL64.
 var FcntlSyscall uintptr = syscall.SYS_FCNTL
 
+// Fcntl performs the fcntl system call.
+func Fcntl(fd int, cmd int, arg int) (int, error) {
+	val, _, e1 := syscall.Syscall(FcntlSyscall, uintptr(fd), uintptr(cmd), uintptr(arg))
+	if e1 != 0 {
+		return -1, e1
+	}
+	return int(val), nil
+}
+
 func IsNonblock(fd int) (nonblocking bool, err error) {
 	flag, _, e1 := syscall.Syscall(FcntlSyscall, uintptr(fd), uintptr(syscall.F_GETFL), 0)
 	if e1 != 0 {
@@ -20,6 +20,11 @@ func IsNonblock(fd int) (nonblocking bool, err error) {
 	return flag&syscall.O_NONBLOCK != 0, nil
 }
 
+// Fcntl performs the fcntl system call.
+func Fcntl(fd int, cmd int, arg int) (int, error) {
+	return fcntl(fd, cmd, arg)
+}
+
 // Implemented in the syscall package.
 //go:linkname fcntl syscall.fcntl
 func fcntl(fd int, cmd int, arg int) (int, error)
@@ -0,0 +1,59 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package unix
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+const (
+	Q_GETQUOTA = 0x800007
+	PRJQUOTA   = 2
+
+	// QIF_DQBLKSIZE is the size in bytes of the blocks in which
+	// Dqblk limits are expressed.
+	QIF_DQBLKSIZE = 1024
+
+	FS_XFLAG_PROJINHERIT = 0x200
+)
+
+// Dqblk is the kernel's struct if_dqblk.
+type Dqblk struct {
+	Bhardlimit uint64
+	Bsoftlimit uint64
+	Curspace   uint64
+	Ihardlimit uint64
+	Isoftlimit uint64
+	Curinodes  uint64
+	Btime      uint64
+	Itime      uint64
+	Valid      uint32
+	_          uint32
+}
+
+// Fsxattr is the argument of FS_IOC_FSGETXATTR and FS_IOC_FSSETXATTR.
+type Fsxattr struct {
+	Xflags     uint32
+	Extsize    uint32
+	Nextents   uint32
+	Projid     uint32
+	Cowextsize uint32
+	Pad        [8]byte
+}
+
+// Quotactl calls quotactl(2) with the command QCMD(cmd, typ).
+func Quotactl(cmd, typ int, special string, id int, addr unsafe.Pointer) error {
+	p, err := syscall.BytePtrFromString(special)
+	if err != nil {
+		return err
+	}
+	qcmd := uint32(cmd)<<8 | uint32(typ&0xff)
+	_, _, errno := syscall.Syscall6(syscall.SYS_QUOTACTL, uintptr(qcmd), uintptr(unsafe.Pointer(p)), uintptr(id), uintptr(addr), 0, 0)
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
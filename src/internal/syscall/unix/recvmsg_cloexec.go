@@ -0,0 +1,12 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build freebsd || linux || netbsd
+// +build freebsd linux netbsd
+
+package unix
+
+import "syscall"
+
+const MSG_CMSG_CLOEXEC = syscall.MSG_CMSG_CLOEXEC
@@ -0,0 +1,45 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package unix
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+// Attrlist is struct attrlist from <sys/attr.h>.
+type Attrlist struct {
+	Bitmapcount uint16
+	_           uint16
+	Commonattr  uint32
+	Volattr     uint32
+	Dirattr     uint32
+	Fileattr    uint32
+	Forkattr    uint32
+}
+
+//go:cgo_import_dynamic libc_setattrlist setattrlist "/usr/lib/libSystem.B.dylib"
+
+func libc_setattrlist_trampoline()
+
+// Setattrlist sets the attributes of path selected by attrList to the
+// values in the size bytes at attrBuf.
+func Setattrlist(path string, attrList *Attrlist, attrBuf unsafe.Pointer, size uintptr, options uint32) error {
+	p, err := syscall.BytePtrFromString(path)
+	if err != nil {
+		return err
+	}
+	_, _, errno := syscall_syscall6(funcPC(libc_setattrlist_trampoline),
+		uintptr(unsafe.Pointer(p)),
+		uintptr(unsafe.Pointer(attrList)),
+		uintptr(attrBuf),
+		size,
+		uintptr(options),
+		0)
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
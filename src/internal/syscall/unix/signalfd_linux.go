@@ -0,0 +1,32 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package unix
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+// Signalfd creates a file descriptor from which the signals in sigs
+// can be read, using the signalfd4 system call.
+func Signalfd(sigs []int, flags int) (int, error) {
+	// The kernel's sigset_t is an array of words holding up to 128
+	// signals, of which it reads sigsetSize bytes.
+	const wordBits = 8 * unsafe.Sizeof(uintptr(0))
+	var mask [128 / wordBits]uintptr
+	for _, sig := range sigs {
+		if sig <= 0 || uintptr(sig) > 8*sigsetSize {
+			return -1, syscall.EINVAL
+		}
+		n := uintptr(sig - 1)
+		mask[n/wordBits] |= 1 << (n % wordBits)
+	}
+	// A file descriptor of -1 asks for a new signalfd.
+	fd, _, errno := syscall.Syscall6(syscall.SYS_SIGNALFD4, ^uintptr(0), uintptr(unsafe.Pointer(&mask[0])), sigsetSize, uintptr(flags), 0, 0)
+	if errno != 0 {
+		return -1, errno
+	}
+	return int(fd), nil
+}
@@ -7,4 +7,7 @@ package unix
 const (
 	getrandomTrap     uintptr = 355
 	copyFileRangeTrap uintptr = 377
+	memfdCreateTrap   uintptr = 356
+	ioUringSetupTrap  uintptr = 425
+	ioUringEnterTrap  uintptr = 426
 )
@@ -7,4 +7,7 @@ package unix
 const (
 	getrandomTrap     uintptr = 318
 	copyFileRangeTrap uintptr = 326
+	memfdCreateTrap   uintptr = 319
+	ioUringSetupTrap  uintptr = 425
+	ioUringEnterTrap  uintptr = 426
 )
@@ -7,4 +7,7 @@ package unix
 const (
 	getrandomTrap     uintptr = 384
 	copyFileRangeTrap uintptr = 391
+	memfdCreateTrap   uintptr = 385
+	ioUringSetupTrap  uintptr = 425
+	ioUringEnterTrap  uintptr = 426
 )
@@ -15,4 +15,7 @@ package unix
 const (
 	getrandomTrap     uintptr = 278
 	copyFileRangeTrap uintptr = 285
+	memfdCreateTrap   uintptr = 279
+	ioUringSetupTrap  uintptr = 425
+	ioUringEnterTrap  uintptr = 426
 )
@@ -10,4 +10,7 @@ package unix
 const (
 	getrandomTrap     uintptr = 5313
 	copyFileRangeTrap uintptr = 5320
+	memfdCreateTrap   uintptr = 5314
+	ioUringSetupTrap  uintptr = 5425
+	ioUringEnterTrap  uintptr = 5426
 )
@@ -10,4 +10,7 @@ package unix
 const (
 	getrandomTrap     uintptr = 4353
 	copyFileRangeTrap uintptr = 4360
+	memfdCreateTrap   uintptr = 4354
+	ioUringSetupTrap  uintptr = 4425
+	ioUringEnterTrap  uintptr = 4426
 )
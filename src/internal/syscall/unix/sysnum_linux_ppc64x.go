@@ -10,4 +10,7 @@ package unix
 const (
 	getrandomTrap     uintptr = 359
 	copyFileRangeTrap uintptr = 379
+	memfdCreateTrap   uintptr = 360
+	ioUringSetupTrap  uintptr = 425
+	ioUringEnterTrap  uintptr = 426
 )
@@ -7,4 +7,7 @@ package unix
 const (
 	getrandomTrap     uintptr = 349
 	copyFileRangeTrap uintptr = 375
+	memfdCreateTrap   uintptr = 350
+	ioUringSetupTrap  uintptr = 425
+	ioUringEnterTrap  uintptr = 426
 )
@@ -0,0 +1,38 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package unix
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+// CLOCK_MONOTONIC is a clock that cannot be set and is not affected
+// by changes to the system time.
+const CLOCK_MONOTONIC = 1
+
+// Itimerspec is struct itimerspec, the setting of a timer.
+type Itimerspec struct {
+	Interval syscall.Timespec
+	Value    syscall.Timespec
+}
+
+// TimerfdCreate creates a timer that notifies by way of a file descriptor.
+func TimerfdCreate(clockid int, flags int) (int, error) {
+	fd, _, errno := syscall.Syscall(syscall.SYS_TIMERFD_CREATE, uintptr(clockid), uintptr(flags), 0)
+	if errno != 0 {
+		return -1, errno
+	}
+	return int(fd), nil
+}
+
+// TimerfdSettime arms or disarms the timer referred to by fd.
+func TimerfdSettime(fd int, flags int, new *Itimerspec) error {
+	_, _, errno := syscall.Syscall6(syscall.SYS_TIMERFD_SETTIME, uintptr(fd), uintptr(flags), uintptr(unsafe.Pointer(new)), 0, 0, 0)
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
@@ -301,6 +301,8 @@ const (
 	LOCKFILE_EXCLUSIVE_LOCK   = 0x00000002
 )
 
+const FILE_FLAG_SEQUENTIAL_SCAN = 0x08000000
+
 const MB_ERR_INVALID_CHARS = 8
 
 //sys	GetACP() (acp uint32) = kernel32.GetACP
@@ -344,3 +346,49 @@ func LoadGetFinalPathNameByHandle() error {
 //sys	DestroyEnvironmentBlock(block *uint16) (err error) = userenv.DestroyEnvironmentBlock
 
 //sys	RtlGenRandom(buf []byte) (err error) = advapi32.SystemFunction036
+
+const DRIVE_FIXED = 3
+
+//sys	GetDriveType(rootPathName *uint16) (driveType uint32) = kernel32.GetDriveTypeW
+
+const (
+	FO_DELETE = 0x3
+
+	FOF_SILENT         = 0x4
+	FOF_NOCONFIRMATION = 0x10
+	FOF_ALLOWUNDO      = 0x40
+	FOF_NOERRORUI      = 0x400
+
+	FOF_WANTNUKEWARNING = 0x4000
+)
+
+// SHFILEOPSTRUCT is the SHFILEOPSTRUCTW structure. On 32-bit systems
+// the C structure is packed, so the fields after Flags do not line up;
+// they must be left zero and not read.
+type SHFILEOPSTRUCT struct {
+	Hwnd                 syscall.Handle
+	Func                 uint32
+	From                 *uint16
+	To                   *uint16
+	Flags                uint16
+	AnyOperationsAborted int32
+	NameMappings         uintptr
+	ProgressTitle        *uint16
+}
+
+//sys	SHFileOperation(op *SHFILEOPSTRUCT) (ret int32) = shell32.SHFileOperationW
+
+// SW_SHOWNORMAL is the ShowWindow command that activates and displays
+// a window in its original size and position.
+const SW_SHOWNORMAL = 1
+
+//sys	ShellExecute(hwnd syscall.Handle, verb *uint16, file *uint16, args *uint16, cwd *uint16, showCmd int32) (err error) [failretval<=32] = shell32.ShellExecuteW
+
+// CCH_RM_SESSION_KEY is the length in characters of a Restart Manager
+// session key, not including the terminating NUL.
+const CCH_RM_SESSION_KEY = 32
+
+//sys	RmStartSession(session *uint32, flags uint32, sessionKey *uint16) (errcode error) = rstrtmgr.RmStartSession
+//sys	RmRegisterResources(session uint32, nFiles uint32, fileNames **uint16, nApplications uint32, applications uintptr, nServices uint32, serviceNames **uint16) (errcode error) = rstrtmgr.RmRegisterResources
+//sys	RmGetList(session uint32, procInfoNeeded *uint32, procInfo *uint32, affectedApps uintptr, rebootReasons *uint32) (errcode error) = rstrtmgr.RmGetList
+//sys	RmEndSession(session uint32) (errcode error) = rstrtmgr.RmEndSession
@@ -42,6 +42,8 @@ var (
 	modkernel32 = syscall.NewLazyDLL(sysdll.Add("kernel32.dll"))
 	modnetapi32 = syscall.NewLazyDLL(sysdll.Add("netapi32.dll"))
 	modpsapi    = syscall.NewLazyDLL(sysdll.Add("psapi.dll"))
+	modrstrtmgr = syscall.NewLazyDLL(sysdll.Add("rstrtmgr.dll"))
+	modshell32  = syscall.NewLazyDLL(sysdll.Add("shell32.dll"))
 	moduserenv  = syscall.NewLazyDLL(sysdll.Add("userenv.dll"))
 	modws2_32   = syscall.NewLazyDLL(sysdll.Add("ws2_32.dll"))
 
@@ -58,6 +60,7 @@ var (
 	procGetComputerNameExW           = modkernel32.NewProc("GetComputerNameExW")
 	procGetConsoleCP                 = modkernel32.NewProc("GetConsoleCP")
 	procGetCurrentThread             = modkernel32.NewProc("GetCurrentThread")
+	procGetDriveTypeW                = modkernel32.NewProc("GetDriveTypeW")
 	procGetFileInformationByHandleEx = modkernel32.NewProc("GetFileInformationByHandleEx")
 	procGetFinalPathNameByHandleW    = modkernel32.NewProc("GetFinalPathNameByHandleW")
 	procGetModuleFileNameW           = modkernel32.NewProc("GetModuleFileNameW")
@@ -70,6 +73,12 @@ var (
 	procNetShareDel                  = modnetapi32.NewProc("NetShareDel")
 	procNetUserGetLocalGroups        = modnetapi32.NewProc("NetUserGetLocalGroups")
 	procGetProcessMemoryInfo         = modpsapi.NewProc("GetProcessMemoryInfo")
+	procRmEndSession                 = modrstrtmgr.NewProc("RmEndSession")
+	procRmGetList                    = modrstrtmgr.NewProc("RmGetList")
+	procRmRegisterResources          = modrstrtmgr.NewProc("RmRegisterResources")
+	procRmStartSession               = modrstrtmgr.NewProc("RmStartSession")
+	procSHFileOperationW             = modshell32.NewProc("SHFileOperationW")
+	procShellExecuteW                = modshell32.NewProc("ShellExecuteW")
 	procCreateEnvironmentBlock       = moduserenv.NewProc("CreateEnvironmentBlock")
 	procDestroyEnvironmentBlock      = moduserenv.NewProc("DestroyEnvironmentBlock")
 	procGetProfilesDirectoryW        = moduserenv.NewProc("GetProfilesDirectoryW")
@@ -190,6 +199,12 @@ func GetCurrentThread() (pseudoHandle syscall.Handle, err error) {
 	return
 }
 
+func GetDriveType(rootPathName *uint16) (driveType uint32) {
+	r0, _, _ := syscall.Syscall(procGetDriveTypeW.Addr(), 1, uintptr(unsafe.Pointer(rootPathName)), 0, 0)
+	driveType = uint32(r0)
+	return
+}
+
 func GetFileInformationByHandleEx(handle syscall.Handle, class uint32, info *byte, bufsize uint32) (err error) {
 	r1, _, e1 := syscall.Syscall6(procGetFileInformationByHandleEx.Addr(), 4, uintptr(handle), uintptr(class), uintptr(unsafe.Pointer(info)), uintptr(bufsize), 0, 0)
 	if r1 == 0 {
@@ -289,6 +304,52 @@ func GetProcessMemoryInfo(handle syscall.Handle, memCounters *PROCESS_MEMORY_COU
 	return
 }
 
+func RmEndSession(session uint32) (errcode error) {
+	r0, _, _ := syscall.Syscall(procRmEndSession.Addr(), 1, uintptr(session), 0, 0)
+	if r0 != 0 {
+		errcode = syscall.Errno(r0)
+	}
+	return
+}
+
+func RmGetList(session uint32, procInfoNeeded *uint32, procInfo *uint32, affectedApps uintptr, rebootReasons *uint32) (errcode error) {
+	r0, _, _ := syscall.Syscall6(procRmGetList.Addr(), 5, uintptr(session), uintptr(unsafe.Pointer(procInfoNeeded)), uintptr(unsafe.Pointer(procInfo)), uintptr(affectedApps), uintptr(unsafe.Pointer(rebootReasons)), 0)
+	if r0 != 0 {
+		errcode = syscall.Errno(r0)
+	}
+	return
+}
+
+func RmRegisterResources(session uint32, nFiles uint32, fileNames **uint16, nApplications uint32, applications uintptr, nServices uint32, serviceNames **uint16) (errcode error) {
+	r0, _, _ := syscall.Syscall9(procRmRegisterResources.Addr(), 7, uintptr(session), uintptr(nFiles), uintptr(unsafe.Pointer(fileNames)), uintptr(nApplications), uintptr(applications), uintptr(nServices), uintptr(unsafe.Pointer(serviceNames)), 0, 0)
+	if r0 != 0 {
+		errcode = syscall.Errno(r0)
+	}
+	return
+}
+
+func RmStartSession(session *uint32, flags uint32, sessionKey *uint16) (errcode error) {
+	r0, _, _ := syscall.Syscall(procRmStartSession.Addr(), 3, uintptr(unsafe.Pointer(session)), uintptr(flags), uintptr(unsafe.Pointer(sessionKey)))
+	if r0 != 0 {
+		errcode = syscall.Errno(r0)
+	}
+	return
+}
+
+func SHFileOperation(op *SHFILEOPSTRUCT) (ret int32) {
+	r0, _, _ := syscall.Syscall(procSHFileOperationW.Addr(), 1, uintptr(unsafe.Pointer(op)), 0, 0)
+	ret = int32(r0)
+	return
+}
+
+func ShellExecute(hwnd syscall.Handle, verb *uint16, file *uint16, args *uint16, cwd *uint16, showCmd int32) (err error) {
+	r1, _, e1 := syscall.Syscall6(procShellExecuteW.Addr(), 6, uintptr(hwnd), uintptr(unsafe.Pointer(verb)), uintptr(unsafe.Pointer(file)), uintptr(unsafe.Pointer(args)), uintptr(unsafe.Pointer(cwd)), uintptr(showCmd))
+	if r1 <= 32 {
+		err = errnoErr(e1)
+	}
+	return
+}
+
 func CreateEnvironmentBlock(block **uint16, token syscall.Token, inheritExisting bool) (err error) {
 	var _p0 uint32
 	if inheritExisting {
@@ -0,0 +1,159 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+import "sync"
+
+// An AsyncFile is a file whose writes are performed in the background.
+// Write copies the data into a buffer of bounded size, from which a
+// background goroutine writes it to the file in order, so the caller
+// waits only when the buffer is full. The bound applies backpressure to
+// writers when the file is slower than they are, instead of letting
+// memory grow without limit.
+//
+// If a background write fails, the error is returned by the next call
+// to Write, Flush or Close, and by every call after that; data not yet
+// written when the error occurred, and data written afterwards, is
+// discarded.
+//
+// The methods of an AsyncFile can be called concurrently. Each Write is
+// appended to the buffer as a whole, unless it is larger than the
+// buffer.
+type AsyncFile struct {
+	f   *File
+	max int
+
+	mu      sync.Mutex
+	cond    sync.Cond // signaled when any of the fields below change
+	buf     []byte    // data waiting to be written
+	writing int       // length of the data being written
+	err     error     // the first write error
+	closed  bool
+	done    chan struct{} // closed when the background goroutine exits
+}
+
+// OpenAsync opens the named file with the given flag and permissions, as
+// OpenFile does, and returns it as an AsyncFile whose buffer holds up to
+// bufBytes bytes, including data being written. If bufBytes is not
+// positive, a default size of 64 KiB is used. The file should be opened
+// for writing; an AsyncFile cannot be read.
+func OpenAsync(name string, flag int, perm FileMode, bufBytes int) (*AsyncFile, error) {
+	f, err := OpenFile(name, flag, perm)
+	if err != nil {
+		return nil, err
+	}
+	if bufBytes <= 0 {
+		bufBytes = 64 << 10
+	}
+	a := &AsyncFile{f: f, max: bufBytes, done: make(chan struct{})}
+	a.cond.L = &a.mu
+	go a.writeLoop()
+	return a, nil
+}
+
+// Name returns the name of the file as presented to OpenAsync.
+func (a *AsyncFile) Name() string {
+	return a.f.Name()
+}
+
+// Write copies b into the buffer, waiting while the buffer is full, and
+// returns len(b), unless an earlier background write failed or the
+// file is closed. Write does not wait for the data to be written to the
+// file.
+func (a *AsyncFile) Write(b []byte) (n int, err error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for len(b) > 0 {
+		// Wait for all of b to fit, or for the buffer to be empty
+		// if b will not fit at all.
+		for a.err == nil && !a.closed && len(a.buf)+a.writing+len(b) > a.max && len(a.buf)+a.writing > 0 {
+			a.cond.Wait()
+		}
+		if a.err != nil {
+			return n, a.err
+		}
+		if a.closed {
+			return n, &PathError{Op: "write", Path: a.f.name, Err: ErrClosed}
+		}
+		m := a.max - len(a.buf) - a.writing
+		if m > len(b) {
+			m = len(b)
+		}
+		a.buf = append(a.buf, b[:m]...)
+		b = b[m:]
+		n += m
+		a.cond.Broadcast()
+	}
+	if a.err != nil {
+		return n, a.err
+	}
+	return n, nil
+}
+
+// Flush waits until all the data written so far has been written to
+// the file, and returns the first error from the background writes.
+// It does not sync the file to stable storage.
+func (a *AsyncFile) Flush() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for a.err == nil && len(a.buf)+a.writing > 0 {
+		a.cond.Wait()
+	}
+	return a.err
+}
+
+// Close waits until all the data written so far has been written to
+// the file, and closes it. It returns the first error from the
+// background writes, or else from closing the file.
+func (a *AsyncFile) Close() error {
+	a.mu.Lock()
+	if a.closed {
+		a.mu.Unlock()
+		return &PathError{Op: "close", Path: a.f.name, Err: ErrClosed}
+	}
+	a.closed = true
+	a.cond.Broadcast()
+	a.mu.Unlock()
+
+	<-a.done
+	err := a.f.Close()
+	if a.err != nil {
+		return a.err
+	}
+	return err
+}
+
+// writeLoop writes the buffered data to the file until the AsyncFile is
+// closed and the buffer is empty, or a write fails.
+func (a *AsyncFile) writeLoop() {
+	defer close(a.done)
+	var spare []byte
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for {
+		for len(a.buf) == 0 && !a.closed {
+			a.cond.Wait()
+		}
+		if len(a.buf) == 0 {
+			return
+		}
+		// Swap buffers, so that Write can fill one while the other is
+		// being written.
+		b := a.buf
+		a.buf, spare = spare[:0], nil
+		a.writing = len(b)
+		a.mu.Unlock()
+		_, err := a.f.Write(b)
+		a.mu.Lock()
+		spare = b
+		a.writing = 0
+		a.cond.Broadcast()
+		if err != nil {
+			a.err = err
+			a.buf = nil
+			return
+		}
+	}
+}
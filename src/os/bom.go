@@ -0,0 +1,66 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+import (
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// ReadFileNoBOM is like ReadFile, but removes a byte order mark (BOM)
+// from the start of the file, reporting its encoding as "UTF-8",
+// "UTF-16LE" or "UTF-16BE", or "" if the file has no BOM. The contents
+// of a file with a UTF-16 BOM are converted to UTF-8, with invalid
+// sequences replaced by U+FFFD, so that the data returned is always
+// UTF-8 if the file is text in any of these encodings.
+func ReadFileNoBOM(name string) (data []byte, encoding string, err error) {
+	data, err = ReadFile(name)
+	if err != nil {
+		return nil, "", err
+	}
+	switch {
+	case len(data) >= 3 && data[0] == 0xEF && data[1] == 0xBB && data[2] == 0xBF:
+		return data[3:], "UTF-8", nil
+	case len(data) >= 2 && data[0] == 0xFF && data[1] == 0xFE:
+		return utf16ToUTF8(data[2:], false), "UTF-16LE", nil
+	case len(data) >= 2 && data[0] == 0xFE && data[1] == 0xFF:
+		return utf16ToUTF8(data[2:], true), "UTF-16BE", nil
+	}
+	return data, "", nil
+}
+
+// utf16ToUTF8 converts the UTF-16 text b, with the given byte order,
+// to UTF-8.
+func utf16ToUTF8(b []byte, bigEndian bool) []byte {
+	unit := func(i int) rune {
+		if bigEndian {
+			return rune(b[i])<<8 | rune(b[i+1])
+		}
+		return rune(b[i+1])<<8 | rune(b[i])
+	}
+	out := make([]byte, 0, len(b)/2*3)
+	var buf [utf8.UTFMax]byte
+	for i := 0; i < len(b); i += 2 {
+		r := utf8.RuneError
+		if i+1 < len(b) {
+			r = unit(i)
+			if utf16.IsSurrogate(r) {
+				r2 := utf8.RuneError
+				if i+3 < len(b) {
+					r2 = unit(i + 2)
+				}
+				if dec := utf16.DecodeRune(r, r2); dec != utf8.RuneError {
+					r = dec
+					i += 2
+				} else {
+					r = utf8.RuneError
+				}
+			}
+		}
+		n := utf8.EncodeRune(buf[:], r)
+		out = append(out, buf[:n]...)
+	}
+	return out
+}
@@ -0,0 +1,96 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+import "sync"
+
+var caseSensitivity struct {
+	sync.Mutex
+	m map[uint64]bool // by volumeID
+}
+
+// IsCaseSensitive reports whether the file system holding path
+// distinguishes file names that differ only in letter case, so that
+// "Foo" and "foo" name different files. If path is not a directory, the
+// directory containing it is examined.
+//
+// The answer is found empirically, by creating a temporary file with a
+// lower-case name in the directory, checking whether the upper-case form
+// of the name refers to the same file, and removing it again, so the
+// directory must be writable. This is more reliable than guessing from
+// the operating system: on macOS, for instance, APFS volumes may be
+// formatted either way. The result is cached for each volume, so later
+// calls for paths on the same volume do not touch the file system again
+// beyond a Stat. On Windows, where case sensitivity can also be enabled
+// for individual directories, the cached result reflects the first
+// directory examined on the volume.
+func IsCaseSensitive(path string) (bool, error) {
+	fi, err := Stat(path)
+	if err != nil {
+		return false, err
+	}
+	dir := path
+	if !fi.IsDir() {
+		dir = dirname(path)
+		if fi, err = Stat(dir); err != nil {
+			return false, err
+		}
+	}
+
+	vol, cacheable := volumeID(fi)
+	if cacheable {
+		caseSensitivity.Lock()
+		sensitive, ok := caseSensitivity.m[vol]
+		caseSensitivity.Unlock()
+		if ok {
+			return sensitive, nil
+		}
+	}
+
+	sensitive, err := probeCaseSensitive(dir)
+	if err != nil {
+		return false, err
+	}
+	if cacheable {
+		caseSensitivity.Lock()
+		if caseSensitivity.m == nil {
+			caseSensitivity.m = make(map[uint64]bool)
+		}
+		caseSensitivity.m[vol] = sensitive
+		caseSensitivity.Unlock()
+	}
+	return sensitive, nil
+}
+
+// probeCaseSensitive creates a file in dir and looks it up by
+// the upper-case form of its name.
+func probeCaseSensitive(dir string) (bool, error) {
+	f, err := CreateTemp(dir, ".casecheck")
+	if err != nil {
+		return false, err
+	}
+	name := f.Name()
+	defer Remove(name)
+	f.Close()
+	fi, err := Lstat(name)
+	if err != nil {
+		return false, err
+	}
+
+	base := []byte(basename(name))
+	for i, c := range base {
+		if 'a' <= c && c <= 'z' {
+			base[i] = c - ('a' - 'A')
+		}
+	}
+	fi2, err := Lstat(joinPath(dirname(name), string(base)))
+	if IsNotExist(err) {
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return !SameFile(fi, fi2), nil
+}
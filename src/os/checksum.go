@@ -0,0 +1,91 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+import (
+	"hash"
+	"io"
+)
+
+// TeeReader returns a Reader that reads from f and writes everything
+// it reads to h, so that after reading to EOF h holds the hash of the
+// data read. Reading starts at the current offset of f; the caller is
+// responsible for calling h.Reset if h has already been used.
+func (f *File) TeeReader(h hash.Hash) io.Reader {
+	return io.TeeReader(f, h)
+}
+
+// VerifyFile reads the named file, computing its hash with h, and
+// reports whether the resulting sum equals want. VerifyFile resets h
+// before use and streams the file, so it works for files larger than
+// memory. After a successful call h holds the hash of the file.
+// A successful call returns err == nil, not err == EOF; a mismatch
+// is reported as false with a nil error.
+func VerifyFile(name string, h hash.Hash, want []byte) (bool, error) {
+	f, err := Open(name)
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+
+	h.Reset()
+	if _, err := io.Copy(io.Discard, f.TeeReader(h)); err != nil {
+		return false, err
+	}
+	return string(h.Sum(nil)) == string(want), nil
+}
+
+// WriteFileChecksum writes data to the named file atomically and
+// returns the hash, computed with h, of the bytes written.
+//
+// The data is written to a temporary file in the same directory,
+// which is synced to stable storage and then renamed to name, so a
+// reader of name sees either its old contents or all of data, never
+// a partial write. If the file does not exist, it is created with
+// permissions perm; otherwise it is replaced by a new file with
+// permissions perm. Unlike WriteFile, perm is not modified by the
+// umask.
+//
+// WriteFileChecksum resets h and hashes each chunk of data at the
+// moment it is written, so the returned sum describes exactly the
+// bytes that reached the file even if data is modified concurrently.
+// This makes it suitable for content-addressed stores.
+func WriteFileChecksum(name string, data []byte, perm FileMode, h hash.Hash) ([]byte, error) {
+	f, err := CreateTemp(TempDirFor(name), "."+basename(name)+".tmp*")
+	if err != nil {
+		return nil, err
+	}
+	tmpName := f.Name()
+	defer func() {
+		if err != nil {
+			f.Close()
+			Remove(tmpName)
+		}
+	}()
+
+	h.Reset()
+	buf := make([]byte, 32*1024)
+	for len(data) > 0 {
+		n := copy(buf, data)
+		data = data[n:]
+		h.Write(buf[:n])
+		if _, err = f.Write(buf[:n]); err != nil {
+			return nil, err
+		}
+	}
+	if err = f.Chmod(perm); err != nil {
+		return nil, err
+	}
+	if err = f.Sync(); err != nil {
+		return nil, err
+	}
+	if err = f.Close(); err != nil {
+		return nil, err
+	}
+	if err = Rename(tmpName, name); err != nil {
+		return nil, err
+	}
+	return h.Sum(nil), nil
+}
@@ -0,0 +1,108 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os_test
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"io"
+	. "os"
+	"path/filepath"
+	"testing"
+)
+
+func TestTeeReader(t *testing.T) {
+	data := bytes.Repeat([]byte("checksum "), 10000)
+	name := filepath.Join(t.TempDir(), "data")
+	if err := WriteFile(name, data, 0666); err != nil {
+		t.Fatal(err)
+	}
+	f, err := Open(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	got, err := io.ReadAll(f.TeeReader(h))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, data) {
+		t.Errorf("read %d bytes, want %d", len(got), len(data))
+	}
+	want := sha256.Sum256(data)
+	if sum := h.Sum(nil); !bytes.Equal(sum, want[:]) {
+		t.Errorf("hash = %x, want %x", sum, want)
+	}
+}
+
+func TestVerifyFile(t *testing.T) {
+	data := []byte("hello, world\n")
+	name := filepath.Join(t.TempDir(), "data")
+	if err := WriteFile(name, data, 0666); err != nil {
+		t.Fatal(err)
+	}
+	want := sha256.Sum256(data)
+
+	h := sha256.New()
+	h.Write([]byte("stale state"))
+	ok, err := VerifyFile(name, h, want[:])
+	if err != nil || !ok {
+		t.Errorf("VerifyFile with matching sum = %v, %v; want true, nil", ok, err)
+	}
+
+	bad := sha256.Sum256([]byte("goodbye"))
+	ok, err = VerifyFile(name, h, bad[:])
+	if err != nil || ok {
+		t.Errorf("VerifyFile with wrong sum = %v, %v; want false, nil", ok, err)
+	}
+
+	if _, err := VerifyFile(name+".missing", h, want[:]); !IsNotExist(err) {
+		t.Errorf("VerifyFile of missing file: err = %v, want not-exist error", err)
+	}
+}
+
+func TestWriteFileChecksum(t *testing.T) {
+	dir := t.TempDir()
+	name := filepath.Join(dir, "blob")
+	if err := WriteFile(name, []byte("old contents"), 0666); err != nil {
+		t.Fatal(err)
+	}
+
+	data := bytes.Repeat([]byte("content-addressed "), 5000)
+	sum, err := WriteFileChecksum(name, data, 0644, sha256.New())
+	if err != nil {
+		t.Fatalf("WriteFileChecksum: %v", err)
+	}
+	want := sha256.Sum256(data)
+	if !bytes.Equal(sum, want[:]) {
+		t.Errorf("WriteFileChecksum returned %x, want %x", sum, want)
+	}
+	got, err := ReadFile(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, data) {
+		t.Errorf("file has %d bytes, want %d", len(got), len(data))
+	}
+
+	// No temporary files are left behind.
+	entries, err := ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		var names []string
+		for _, e := range entries {
+			names = append(names, e.Name())
+		}
+		t.Errorf("directory contains %q, want only %q", names, "blob")
+	}
+
+	if _, err := WriteFileChecksum(filepath.Join(dir, "missing", "blob"), data, 0644, sha256.New()); err == nil {
+		t.Error("WriteFileChecksum in missing directory succeeded")
+	}
+}
@@ -0,0 +1,36 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build darwin || dragonfly || freebsd || netbsd || openbsd
+// +build darwin dragonfly freebsd netbsd openbsd
+
+package os
+
+import (
+	"runtime"
+	"syscall"
+	"time"
+)
+
+// chtimes sets the access and modification times of the open file,
+// leaving zero times unchanged.
+func (f *File) chtimes(atime, mtime time.Time) error {
+	atime, mtime, err := f.fillTimes(atime, mtime)
+	if err != nil {
+		return err
+	}
+	tv := []syscall.Timeval{
+		syscall.NsecToTimeval(atime.UnixNano()),
+		syscall.NsecToTimeval(mtime.UnixNano()),
+	}
+	var e error
+	err = f.pfd.RawControl(func(fd uintptr) {
+		e = syscall.Futimes(int(fd), tv)
+	})
+	runtime.KeepAlive(f)
+	if err != nil {
+		return err
+	}
+	return e
+}
@@ -0,0 +1,48 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+import (
+	"runtime"
+	"syscall"
+	"time"
+	"unsafe"
+)
+
+// _UTIME_OMIT is the tv_nsec value that tells utimensat
+// to leave the corresponding time unchanged.
+const _UTIME_OMIT = (1 << 30) - 2
+
+// utimensatTimes converts atime and mtime to the argument of
+// utimensat, using _UTIME_OMIT for zero times.
+func utimensatTimes(atime, mtime time.Time) (ts [2]syscall.Timespec) {
+	for i, t := range [2]time.Time{atime, mtime} {
+		if t.IsZero() {
+			ts[i].Nsec = _UTIME_OMIT
+		} else {
+			ts[i] = syscall.NsecToTimespec(t.UnixNano())
+		}
+	}
+	return ts
+}
+
+// chtimes sets the access and modification times of the open file,
+// leaving zero times unchanged.
+func (f *File) chtimes(atime, mtime time.Time) error {
+	ts := utimensatTimes(atime, mtime)
+	var errno syscall.Errno
+	err := f.pfd.RawControl(func(fd uintptr) {
+		// utimensat with a NULL path operates on fd itself.
+		_, _, errno = syscall.Syscall6(syscall.SYS_UTIMENSAT, fd, 0, uintptr(unsafe.Pointer(&ts[0])), 0, 0, 0)
+	})
+	runtime.KeepAlive(f)
+	if err != nil {
+		return err
+	}
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
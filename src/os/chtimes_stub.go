@@ -0,0 +1,21 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build aix || (js && wasm) || plan9 || solaris
+// +build aix js,wasm plan9 solaris
+
+package os
+
+import "time"
+
+// chtimes sets the access and modification times of the file,
+// leaving zero times unchanged. These systems provide no way to
+// set the times of an open file, so chtimes uses its name.
+func (f *File) chtimes(atime, mtime time.Time) error {
+	atime, mtime, err := f.fillTimes(atime, mtime)
+	if err != nil {
+		return err
+	}
+	return underlyingError(Chtimes(f.name, atime, mtime))
+}
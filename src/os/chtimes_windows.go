@@ -0,0 +1,34 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+import (
+	"runtime"
+	"syscall"
+	"time"
+)
+
+// chtimes sets the access and modification times of the open file,
+// leaving zero times unchanged.
+func (f *File) chtimes(atime, mtime time.Time) error {
+	var a, w *syscall.Filetime
+	if !atime.IsZero() {
+		ft := syscall.NsecToFiletime(atime.UnixNano())
+		a = &ft
+	}
+	if !mtime.IsZero() {
+		ft := syscall.NsecToFiletime(mtime.UnixNano())
+		w = &ft
+	}
+	var e error
+	err := f.pfd.RawControl(func(fd uintptr) {
+		e = syscall.SetFileTime(syscall.Handle(fd), nil, a, w)
+	})
+	runtime.KeepAlive(f)
+	if err != nil {
+		return err
+	}
+	return e
+}
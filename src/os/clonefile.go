@@ -0,0 +1,21 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+// CloneFile creates the file named dst as a copy-on-write clone of the
+// regular file named src. The clone shares the data blocks of src until
+// either file is written, so it takes almost no time or space however
+// large src is. CloneFile fails if dst already exists.
+//
+// CloneFile uses the FICLONE ioctl on Linux, which is supported by file
+// systems such as Btrfs and XFS, and clonefile(2) on macOS, which is
+// supported by APFS. If the file system does not support clones, or the
+// files would be on different file systems, or on other systems,
+// CloneFile returns an error wrapping ErrUnsupported, and the caller can
+// fall back to copying the data with CopyFileProgress. When CloneFile
+// fails, it does not leave dst behind.
+func CloneFile(dst, src string) error {
+	return cloneFile(dst, src)
+}
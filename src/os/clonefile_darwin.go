@@ -0,0 +1,30 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+import (
+	"internal/syscall/unix"
+	"syscall"
+)
+
+func cloneFile(dst, src string) error {
+	fi, err := Stat(src)
+	if err != nil {
+		return err
+	}
+	if fi.IsDir() {
+		return &PathError{Op: "clonefile", Path: src, Err: errIsDirectory}
+	}
+	err = ignoringEINTR(func() error {
+		return unix.Clonefile(src, dst, 0)
+	})
+	if err != nil {
+		if err == syscall.EXDEV {
+			err = ErrUnsupported
+		}
+		return &LinkError{"clonefile", src, dst, err}
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+import (
+	"internal/syscall/unix"
+	"runtime"
+	"syscall"
+)
+
+func cloneFile(dst, src string) error {
+	in, err := Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	fi, err := in.Stat()
+	if err != nil {
+		return err
+	}
+	if fi.IsDir() {
+		return &PathError{Op: "clonefile", Path: src, Err: errIsDirectory}
+	}
+
+	out, err := OpenFile(dst, O_WRONLY|O_CREATE|O_EXCL, fi.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	var e error
+	err = out.pfd.RawControl(func(ofd uintptr) {
+		err := in.pfd.RawControl(func(ifd uintptr) {
+			e = unix.Ioctl(int(ofd), unix.FICLONE, ifd)
+		})
+		if err != nil {
+			e = err
+		}
+	})
+	runtime.KeepAlive(in)
+	if err == nil {
+		err = e
+	}
+	if cerr := out.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		Remove(dst)
+		switch err {
+		case syscall.EXDEV, syscall.ENOTTY:
+			// The files are on different file systems, or the
+			// kernel predates FICLONE (Linux 4.5).
+			err = ErrUnsupported
+		}
+		return &LinkError{"clonefile", src, dst, err}
+	}
+	return nil
+}
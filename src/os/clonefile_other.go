@@ -0,0 +1,12 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !darwin && !linux
+// +build !darwin,!linux
+
+package os
+
+func cloneFile(dst, src string) error {
+	return &LinkError{"clonefile", src, dst, ErrUnsupported}
+}
@@ -0,0 +1,39 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+// CloseRead shuts down the reading side of f, leaving the writing side
+// open. For a connected socket, such as one end of a Socketpair, the
+// peer's writes fail and f's reads return io.EOF, while f can still
+// write. A file that is not a socket, such as the read end of a pipe,
+// can only be shut down for reading if it is open only for reading;
+// in that case CloseRead closes f. Otherwise CloseRead returns an error
+// wrapping ErrInvalid.
+//
+// On Windows, Plan 9 and js/wasm, CloseRead returns an error wrapping
+// ErrUnsupported.
+func (f *File) CloseRead() error {
+	if err := f.checkValid("closeread"); err != nil {
+		return err
+	}
+	return f.closeRead()
+}
+
+// CloseWrite shuts down the writing side of f, leaving the reading side
+// open. For a connected socket the peer's reads return io.EOF once any
+// buffered data has been consumed, while f can still read what the peer
+// writes. A file that is not a socket, such as the write end of a pipe,
+// can only be shut down for writing if it is open only for writing;
+// in that case CloseWrite closes f, so that the reader sees io.EOF.
+// Otherwise CloseWrite returns an error wrapping ErrInvalid.
+//
+// On Windows, Plan 9 and js/wasm, CloseWrite returns an error wrapping
+// ErrUnsupported.
+func (f *File) CloseWrite() error {
+	if err := f.checkValid("closewrite"); err != nil {
+		return err
+	}
+	return f.closeWrite()
+}
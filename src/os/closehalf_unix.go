@@ -0,0 +1,51 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build aix || darwin || dragonfly || freebsd || linux || netbsd || openbsd || solaris
+// +build aix darwin dragonfly freebsd linux netbsd openbsd solaris
+
+package os
+
+import (
+	"internal/syscall/unix"
+	"runtime"
+	"syscall"
+)
+
+// closeHalf implements CloseRead and CloseWrite. how is the shutdown
+// direction and accmode is the access mode that a non-socket file must
+// have for closing it to end only that direction.
+func (f *File) closeHalf(op string, how, accmode int) error {
+	err := f.pfd.Shutdown(how)
+	if err != syscall.ENOTSOCK {
+		return f.wrapErr(op, err)
+	}
+
+	// Not a socket. The only way to end one direction of a pipe or
+	// other file is to close it, which is correct only if the file
+	// is open solely in that direction.
+	var flags int
+	var e error
+	if err := f.pfd.RawControl(func(fd uintptr) {
+		flags, e = unix.Fcntl(int(fd), syscall.F_GETFL, 0)
+	}); err != nil {
+		return f.wrapErr(op, err)
+	}
+	runtime.KeepAlive(f)
+	if e != nil {
+		return f.wrapErr(op, e)
+	}
+	if flags&syscall.O_ACCMODE != accmode {
+		return f.wrapErr(op, ErrInvalid)
+	}
+	return f.Close()
+}
+
+func (f *File) closeRead() error {
+	return f.closeHalf("closeread", syscall.SHUT_RD, syscall.O_RDONLY)
+}
+
+func (f *File) closeWrite() error {
+	return f.closeHalf("closewrite", syscall.SHUT_WR, syscall.O_WRONLY)
+}
@@ -0,0 +1,148 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+import (
+	"errors"
+	"io"
+	"time"
+)
+
+var errSameFile = errors.New("source and destination are the same file")
+
+// CopyFileProgress copies the contents of the file named src to the
+// file named dst, creating dst with the permission bits of src if it
+// does not exist and truncating it otherwise. It is an error for dst
+// to be the same file as src.
+//
+// If onProgress is not nil, it is called after each chunk is written
+// with the number of bytes copied so far and the size of src, or -1 if
+// src is not a regular file and its size is unknown.
+//
+// If rateBytesPerSec is positive, the copy is throttled so that its
+// average rate does not exceed rateBytesPerSec. Bursts of up to a
+// quarter of a second's worth of data are copied without delay.
+//
+// On error, dst may hold part of the data.
+func CopyFileProgress(dst, src string, onProgress func(copied, total int64), rateBytesPerSec int64) (err error) {
+	in, err := Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	fi, err := in.Stat()
+	if err != nil {
+		return err
+	}
+	if fi.IsDir() {
+		return &PathError{Op: "copyfile", Path: src, Err: errIsDirectory}
+	}
+	total := int64(-1)
+	if fi.Mode().IsRegular() {
+		total = fi.Size()
+	}
+
+	// Truncate only once dst is known not to be src.
+	out, err := OpenFile(dst, O_WRONLY|O_CREATE, fi.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := out.Close(); err == nil {
+			err = cerr
+		}
+	}()
+	dfi, err := out.Stat()
+	if err != nil {
+		return err
+	}
+	if SameFile(fi, dfi) {
+		return &PathError{Op: "copyfile", Path: dst, Err: errSameFile}
+	}
+	if dfi.Mode().IsRegular() {
+		if err := out.Truncate(0); err != nil {
+			return err
+		}
+	}
+
+	chunk := int64(32 * 1024)
+	var tb *tokenBucket
+	if rateBytesPerSec > 0 {
+		// Keep chunks small relative to the rate, so that the
+		// throttling is smooth.
+		if c := rateBytesPerSec / 8; c < chunk {
+			chunk = c
+			if chunk < 1 {
+				chunk = 1
+			}
+		}
+		burst := rateBytesPerSec / 4
+		if burst < chunk {
+			burst = chunk
+		}
+		tb = newTokenBucket(rateBytesPerSec, burst)
+	}
+
+	buf := make([]byte, chunk)
+	var copied int64
+	for {
+		n, rerr := in.Read(buf)
+		if n > 0 {
+			if tb != nil {
+				tb.wait(int64(n))
+			}
+			if _, err := out.Write(buf[:n]); err != nil {
+				return err
+			}
+			copied += int64(n)
+			if onProgress != nil {
+				onProgress(copied, total)
+			}
+		}
+		if rerr == io.EOF {
+			break
+		}
+		if rerr != nil {
+			return rerr
+		}
+	}
+	if onProgress != nil && copied == 0 {
+		onProgress(0, total)
+	}
+	return nil
+}
+
+// A tokenBucket limits a flow of bytes to an average rate, while
+// allowing bursts of up to the bucket's capacity.
+type tokenBucket struct {
+	rate   float64 // tokens added per second
+	burst  float64 // capacity of the bucket
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(rate, burst int64) *tokenBucket {
+	return &tokenBucket{
+		rate:   float64(rate),
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// wait takes n tokens from the bucket, sleeping until the bucket's
+// debt is repaid if it holds too few.
+func (tb *tokenBucket) wait(n int64) {
+	now := time.Now()
+	tb.tokens += now.Sub(tb.last).Seconds() * tb.rate
+	if tb.tokens > tb.burst {
+		tb.tokens = tb.burst
+	}
+	tb.last = now
+	tb.tokens -= float64(n)
+	if tb.tokens < 0 {
+		time.Sleep(time.Duration(-tb.tokens / tb.rate * float64(time.Second)))
+	}
+}
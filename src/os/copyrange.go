@@ -0,0 +1,31 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+// CopyFileRange copies n bytes from src, starting at offset srcOff, to
+// dst, starting at offset dstOff, and returns the number of bytes
+// copied. It neither uses nor changes the file offset of either file.
+// Fewer than n bytes are copied only if the end of src is reached or
+// an error occurs.
+//
+// CopyFileRange uses copy_file_range(2), which lets the kernel copy the
+// data without passing it through user space, and lets file systems
+// that support it share the data blocks between the files or copy them
+// on the server. If the files' file systems cannot copy the range
+// between them, or on systems other than Linux, CopyFileRange returns
+// an error wrapping ErrUnsupported, and the caller can fall back to
+// ReadAt and WriteAt. Any other error is of type *LinkError.
+func CopyFileRange(dst *File, dstOff int64, src *File, srcOff int64, n int64) (int64, error) {
+	if err := dst.checkValid("copy_file_range"); err != nil {
+		return 0, err
+	}
+	if err := src.checkValid("copy_file_range"); err != nil {
+		return 0, err
+	}
+	if n < 0 {
+		return 0, &LinkError{"copy_file_range", src.name, dst.name, ErrInvalid}
+	}
+	return copyFileRange(dst, dstOff, src, srcOff, n)
+}
@@ -0,0 +1,58 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+import (
+	"internal/syscall/unix"
+	"runtime"
+	"syscall"
+)
+
+// maxCopyFileRangeRound is the maximum number of bytes passed to a
+// single copy_file_range call, as in internal/poll.
+const maxCopyFileRangeRound = 1 << 30
+
+func copyFileRange(dst *File, dstOff int64, src *File, srcOff int64, n int64) (int64, error) {
+	var written int64
+	for written < n {
+		max := n - written
+		if max > maxCopyFileRangeRound {
+			max = maxCopyFileRangeRound
+		}
+		var m int
+		var e error
+		err := dst.pfd.RawControl(func(wfd uintptr) {
+			err := src.pfd.RawControl(func(rfd uintptr) {
+				e = ignoringEINTR(func() error {
+					var err error
+					m, err = unix.CopyFileRange(int(rfd), &srcOff, int(wfd), &dstOff, int(max), 0)
+					return err
+				})
+			})
+			if err != nil {
+				e = err
+			}
+		})
+		runtime.KeepAlive(src)
+		runtime.KeepAlive(dst)
+		if err == nil {
+			err = e
+		}
+		if err != nil {
+			if err == syscall.EXDEV {
+				// Before Linux 5.3, and again since 5.19 for most
+				// file systems, the files must be on the same
+				// file system.
+				err = ErrUnsupported
+			}
+			return written, &LinkError{"copy_file_range", src.name, dst.name, err}
+		}
+		if m == 0 {
+			break
+		}
+		written += int64(m)
+	}
+	return written, nil
+}
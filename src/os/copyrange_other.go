@@ -0,0 +1,12 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !linux
+// +build !linux
+
+package os
+
+func copyFileRange(dst *File, dstOff int64, src *File, srcOff int64, n int64) (int64, error) {
+	return 0, &LinkError{"copy_file_range", src.name, dst.name, ErrUnsupported}
+}
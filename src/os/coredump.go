@@ -0,0 +1,60 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+import (
+	"internal/unsafeheader"
+	"unsafe"
+)
+
+// ExcludeFromCoreDump asks the system to leave the memory holding b out
+// of any core dump written for the process, so that secrets such as key
+// material do not end up in crash dumps. The setting applies to whole
+// pages, so it also affects any other data sharing a page with b; for
+// that reason b is best allocated as a MappedFile or other page-aligned
+// buffer that holds nothing else.
+//
+// ExcludeFromCoreDump does not stop the memory being written to swap;
+// use MlockRegion for that. Together the two keep a secret in RAM only.
+//
+// ExcludeFromCoreDump uses madvise with MADV_DONTDUMP on Linux and
+// MADV_NOCORE on FreeBSD and DragonFly. On other systems it returns an
+// error wrapping ErrUnsupported.
+func ExcludeFromCoreDump(b []byte) error {
+	if len(b) == 0 {
+		return nil
+	}
+	if err := setCoreDump(pageRegion(b), false); err != nil {
+		return NewSyscallError("madvise", err)
+	}
+	return nil
+}
+
+// IncludeInCoreDump undoes the effect of ExcludeFromCoreDump.
+func IncludeInCoreDump(b []byte) error {
+	if len(b) == 0 {
+		return nil
+	}
+	if err := setCoreDump(pageRegion(b), true); err != nil {
+		return NewSyscallError("madvise", err)
+	}
+	return nil
+}
+
+// pageRegion returns the smallest page-aligned region containing b,
+// which must not be empty.
+func pageRegion(b []byte) []byte {
+	pageSize := uintptr(Getpagesize())
+	start := uintptr(unsafe.Pointer(&b[0]))
+	off := start & (pageSize - 1)
+	n := (off + uintptr(len(b)) + pageSize - 1) &^ (pageSize - 1)
+
+	var r []byte
+	hdr := (*unsafeheader.Slice)(unsafe.Pointer(&r))
+	hdr.Data = unsafe.Pointer(uintptr(unsafe.Pointer(&b[0])) - off)
+	hdr.Len = int(n)
+	hdr.Cap = int(n)
+	return r
+}
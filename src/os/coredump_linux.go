@@ -0,0 +1,11 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+// Not defined in package syscall on all architectures.
+const (
+	_MADV_DONTDUMP = 0x10
+	_MADV_DODUMP   = 0x11
+)
@@ -0,0 +1,18 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build dragonfly || freebsd || linux
+// +build dragonfly freebsd linux
+
+package os
+
+import "internal/syscall/unix"
+
+func setCoreDump(b []byte, dump bool) error {
+	advice := _MADV_DONTDUMP
+	if dump {
+		advice = _MADV_DODUMP
+	}
+	return unix.Madvise(b, advice)
+}
@@ -0,0 +1,15 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build dragonfly || freebsd
+// +build dragonfly freebsd
+
+package os
+
+import "syscall"
+
+const (
+	_MADV_DONTDUMP = syscall.MADV_NOCORE
+	_MADV_DODUMP   = syscall.MADV_CORE
+)
@@ -0,0 +1,137 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+import (
+	"errors"
+	"io"
+)
+
+// IncrementFile adds delta to the decimal integer stored in the named
+// file and returns the new value. The file is created holding 0 if it
+// does not exist. The read, update and write happen under an exclusive
+// lock on the file, so concurrent increments by cooperating processes
+// are not lost. The new value is synced to disk before IncrementFile
+// returns.
+//
+// If the file does not hold a decimal integer, optionally surrounded
+// by white space, IncrementFile returns an error of type *PathError and
+// leaves the file unchanged.
+func IncrementFile(name string, delta int64) (n int64, err error) {
+	f, err := OpenFile(name, O_RDWR|O_CREATE, 0666)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		if cerr := f.Close(); err == nil && cerr != nil {
+			err = cerr
+		}
+	}()
+	if err := f.lock(true, true); err != nil {
+		return 0, err
+	}
+	defer f.unlock()
+
+	b, err := io.ReadAll(io.NewSectionReader(f, 0, 1<<62))
+	if err != nil {
+		return 0, err
+	}
+	v, ok := parseCounter(b)
+	if !ok {
+		return 0, &PathError{Op: "incrementfile", Path: name, Err: errMalformedCounter}
+	}
+	n = v + delta
+	if (delta > 0 && n < v) || (delta < 0 && n > v) {
+		return 0, &PathError{Op: "incrementfile", Path: name, Err: errCounterOverflow}
+	}
+	s := formatCounter(n) + "\n"
+	if _, err := f.WriteAt([]byte(s), 0); err != nil {
+		return 0, err
+	}
+	if err := f.Truncate(int64(len(s))); err != nil {
+		return 0, err
+	}
+	if err := f.Sync(); err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
+var (
+	errMalformedCounter = errors.New("file does not contain an integer")
+	errCounterOverflow  = errors.New("counter overflows int64")
+)
+
+// parseCounter parses b as a signed decimal integer surrounded by
+// optional white space. An empty or all-space b is zero.
+func parseCounter(b []byte) (int64, bool) {
+	isSpace := func(c byte) bool {
+		return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+	}
+	for len(b) > 0 && isSpace(b[0]) {
+		b = b[1:]
+	}
+	for len(b) > 0 && isSpace(b[len(b)-1]) {
+		b = b[:len(b)-1]
+	}
+	if len(b) == 0 {
+		return 0, true
+	}
+	neg := false
+	switch b[0] {
+	case '-':
+		neg = true
+		fallthrough
+	case '+':
+		b = b[1:]
+	}
+	if len(b) == 0 {
+		return 0, false
+	}
+	// Accumulate as a negative number so that the most negative
+	// int64 can be represented.
+	const min = -1 << 63
+	var v int64
+	for _, c := range b {
+		if c < '0' || c > '9' {
+			return 0, false
+		}
+		d := int64(c - '0')
+		if v < min/10 || v*10 < min+d {
+			return 0, false
+		}
+		v = v*10 - d
+	}
+	if !neg {
+		if v == min {
+			return 0, false
+		}
+		v = -v
+	}
+	return v, true
+}
+
+// formatCounter formats v in decimal.
+func formatCounter(v int64) string {
+	var buf [20]byte
+	i := len(buf)
+	u := uint64(v)
+	if v < 0 {
+		u = -u
+	}
+	for {
+		i--
+		buf[i] = byte('0' + u%10)
+		u /= 10
+		if u == 0 {
+			break
+		}
+	}
+	if v < 0 {
+		i--
+		buf[i] = '-'
+	}
+	return string(buf[i:])
+}
@@ -0,0 +1,36 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+import (
+	"internal/bytealg"
+	"io"
+)
+
+// CountLines returns the number of newline bytes ('\n') in the named
+// file, as wc -l does. A final line that does not end in a newline is
+// therefore not counted, so a non-empty file without newlines has no
+// lines. The file is read in large chunks and not retained, so the
+// memory used does not depend on the size of the file.
+func CountLines(name string) (int64, error) {
+	f, err := OpenSequential(name)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	buf := make([]byte, 256<<10)
+	var lines int64
+	for {
+		n, err := f.Read(buf)
+		lines += int64(bytealg.Count(buf[:n], '\n'))
+		if err != nil {
+			if err == io.EOF {
+				err = nil
+			}
+			return lines, err
+		}
+	}
+}
@@ -0,0 +1,39 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+// CreateExact creates or truncates the named file like Create, but
+// ensures that it ends up with exactly the mode perm, which may include
+// ModeSetuid, ModeSetgid and ModeSticky as well as permission bits.
+// Unlike OpenFile, the result does not depend on the umask, and an
+// existing file gets the new mode rather than keeping its old one.
+//
+// The mode is set with Chmod on the open file, so there is no race with
+// another process replacing name. An existing file's mode is changed
+// before the file is truncated, so the data written to the returned
+// File is never more accessible than requested, except to a process
+// that already had the file open. There is a brief window after a file
+// is created in which its permissions are perm reduced by the umask;
+// that only removes permissions.
+func CreateExact(name string, perm FileMode) (*File, error) {
+	f, err := OpenFile(name, O_RDWR|O_CREATE, perm.Perm())
+	if err != nil {
+		return nil, err
+	}
+	err = f.Chmod(perm.Perm())
+	if err == nil {
+		err = f.Truncate(0)
+	}
+	// Truncating a file may clear its setuid and setgid bits,
+	// so they are set afterward.
+	if err == nil && perm != perm.Perm() {
+		err = f.Chmod(perm)
+	}
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return f, nil
+}
@@ -0,0 +1,36 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+// SetControllingTerminal makes the terminal f refers to the controlling
+// terminal of the calling process's session. The calling process must
+// be a session leader without a controlling terminal, as after a call
+// to Setsid, and the terminal must not already be the controlling
+// terminal of another session. This is how a login program or terminal
+// emulator gives a child its own terminal, typically the slave side of
+// a pseudo-terminal; the child then starts the program to run on it.
+//
+// On Windows, Plan 9, AIX and js/wasm, SetControllingTerminal returns
+// an error wrapping ErrUnsupported. Any other error is of type
+// *PathError.
+func (f *File) SetControllingTerminal() error {
+	if err := f.checkValid("ioctl"); err != nil {
+		return err
+	}
+	return f.setControllingTerminal()
+}
+
+// DetachControllingTerminal gives up the controlling terminal of the
+// calling process. If the process is the session leader, the terminal
+// is released and the foreground process group of the session receives
+// SIGHUP and SIGCONT; otherwise only the calling process loses access
+// to it. It fails if the process has no controlling terminal.
+//
+// On Windows, Plan 9, AIX and js/wasm, DetachControllingTerminal
+// returns an error wrapping ErrUnsupported. Any other error is of type
+// *PathError.
+func DetachControllingTerminal() error {
+	return detachControllingTerminal()
+}
@@ -0,0 +1,50 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd || solaris
+// +build darwin dragonfly freebsd linux netbsd openbsd solaris
+
+package os
+
+import (
+	"internal/syscall/unix"
+	"runtime"
+	"syscall"
+)
+
+func (f *File) setControllingTerminal() error {
+	var e error
+	if err := f.pfd.RawControl(func(fd uintptr) {
+		// An argument of 0 never steals the terminal from another
+		// session, even when running as root.
+		e = unix.Ioctl(int(fd), syscall.TIOCSCTTY, 0)
+	}); err != nil {
+		return f.wrapErr("ioctl", err)
+	}
+	runtime.KeepAlive(f)
+	if e != nil {
+		return f.wrapErr("ioctl", e)
+	}
+	return nil
+}
+
+func detachControllingTerminal() error {
+	// /dev/tty always refers to the controlling terminal of the
+	// calling process, and cannot be opened if there is none.
+	f, err := OpenFile("/dev/tty", O_RDWR|syscall.O_NOCTTY, 0)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	var e error
+	if err := f.pfd.RawControl(func(fd uintptr) {
+		e = unix.Ioctl(int(fd), syscall.TIOCNOTTY, 0)
+	}); err != nil {
+		return f.wrapErr("ioctl", err)
+	}
+	if e != nil {
+		return f.wrapErr("ioctl", e)
+	}
+	return nil
+}
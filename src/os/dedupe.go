@@ -0,0 +1,58 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+// A DedupeTarget is a range to be deduplicated against a source range by
+// DedupeRange. The range starts at Offset in File and has the same
+// length as the source range.
+type DedupeTarget struct {
+	File   *File
+	Offset int64
+}
+
+// A DedupeResult is the outcome of DedupeRange for one target.
+type DedupeResult struct {
+	// Bytes is the number of bytes that now share storage with the
+	// source. It can be less than the requested length, in which case
+	// the caller can continue with the rest of the range.
+	Bytes int64
+
+	// Differs reports that the contents of the target range differ
+	// from the source, so nothing was deduplicated.
+	Differs bool
+
+	// Err is the error, if any, that prevented the target range from
+	// being deduplicated.
+	Err error
+}
+
+// DedupeRange deduplicates the length bytes of src starting at srcOff
+// against each of the targets, which must be open for writing. For each
+// target, the kernel locks both files, checks that the ranges hold
+// identical data, and if so makes the target share the storage of the
+// source. Because the comparison and the sharing are done together, no
+// data is lost if a file changes concurrently; the target is then
+// reported as differing. The results correspond to the targets.
+//
+// DedupeRange uses the FIDEDUPERANGE ioctl, which is supported by file
+// systems such as Btrfs and XFS. If the file system of src does not
+// support deduplication, or on systems other than Linux, DedupeRange
+// returns an error wrapping ErrUnsupported. A target on a file system
+// that cannot share storage with src has an Err wrapping
+// ErrUnsupported.
+func DedupeRange(src *File, srcOff, length int64, targets []DedupeTarget) ([]DedupeResult, error) {
+	if err := src.checkValid("fideduperange"); err != nil {
+		return nil, err
+	}
+	for _, t := range targets {
+		if err := t.File.checkValid("fideduperange"); err != nil {
+			return nil, err
+		}
+	}
+	if srcOff < 0 || length < 0 {
+		return nil, src.wrapErr("fideduperange", ErrInvalid)
+	}
+	return dedupeRange(src, srcOff, length, targets)
+}
@@ -0,0 +1,101 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+import (
+	"internal/syscall/unix"
+	"runtime"
+	"syscall"
+	"unsafe"
+)
+
+// maxDedupeTargets is the number of targets passed to a single
+// FIDEDUPERANGE ioctl, chosen so that the argument fits in a 4 KiB
+// page, as the kernel requires.
+const maxDedupeTargets = int((4096 - unsafe.Sizeof(unix.FileDedupeRange{})) / unsafe.Sizeof(unix.FileDedupeRangeInfo{}))
+
+func dedupeRange(src *File, srcOff, length int64, targets []DedupeTarget) ([]DedupeResult, error) {
+	results := make([]DedupeResult, 0, len(targets))
+	for len(targets) > 0 {
+		batch := targets
+		if len(batch) > maxDedupeTargets {
+			batch = batch[:maxDedupeTargets]
+		}
+		targets = targets[len(batch):]
+
+		// The argument is a FileDedupeRange followed by an array of
+		// FileDedupeRangeInfo; a []uint64 keeps both aligned.
+		const hdrSize = unsafe.Sizeof(unix.FileDedupeRange{})
+		const infoSize = unsafe.Sizeof(unix.FileDedupeRangeInfo{})
+		buf := make([]uint64, (hdrSize+uintptr(len(batch))*infoSize)/8)
+		hdr := (*unix.FileDedupeRange)(unsafe.Pointer(&buf[0]))
+		infos := (*[maxDedupeTargets]unix.FileDedupeRangeInfo)(unsafe.Pointer(&buf[hdrSize/8]))[:len(batch):len(batch)]
+		hdr.SrcOffset = uint64(srcOff)
+		hdr.SrcLength = uint64(length)
+		hdr.DestCount = uint16(len(batch))
+		for i, t := range batch {
+			infos[i].DestOffset = uint64(t.Offset)
+		}
+
+		files := make([]*File, 0, len(batch)+1)
+		files = append(files, src)
+		for _, t := range batch {
+			files = append(files, t.File)
+		}
+		var e error
+		err := rawControlAll(files, nil, func(fds []uintptr) {
+			for i := range infos {
+				infos[i].DestFd = int64(fds[i+1])
+			}
+			e = unix.IoctlPtr(int(fds[0]), unix.FIDEDUPERANGE, unsafe.Pointer(&buf[0]))
+		})
+		runtime.KeepAlive(files)
+		if err == nil {
+			err = e
+		}
+		if err != nil {
+			if err == syscall.ENOTTY {
+				// The kernel predates FIDEDUPERANGE (Linux 4.5).
+				err = ErrUnsupported
+			}
+			return nil, src.wrapErr("fideduperange", err)
+		}
+
+		for i, info := range infos {
+			r := DedupeResult{Bytes: int64(info.BytesDeduped)}
+			switch {
+			case info.Status == unix.FILE_DEDUPE_RANGE_SAME:
+			case info.Status == unix.FILE_DEDUPE_RANGE_DIFFERS:
+				r.Differs = true
+			case info.Status < 0:
+				var err error = syscall.Errno(-info.Status)
+				if err == syscall.EXDEV {
+					err = ErrUnsupported
+				}
+				r.Err = batch[i].File.wrapErr("fideduperange", err)
+			}
+			results = append(results, r)
+		}
+	}
+	return results, nil
+}
+
+// rawControlAll calls fn with the descriptors of files, which are kept
+// from being closed until fn returns. The descriptors are appended to
+// fds.
+func rawControlAll(files []*File, fds []uintptr, fn func(fds []uintptr)) error {
+	if len(files) == 0 {
+		fn(fds)
+		return nil
+	}
+	var e error
+	err := files[0].pfd.RawControl(func(fd uintptr) {
+		e = rawControlAll(files[1:], append(fds, fd), fn)
+	})
+	if err != nil {
+		return err
+	}
+	return e
+}
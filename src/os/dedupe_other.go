@@ -0,0 +1,12 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !linux
+// +build !linux
+
+package os
+
+func dedupeRange(src *File, srcOff, length int64, targets []DedupeTarget) ([]DedupeResult, error) {
+	return nil, src.wrapErr("fideduperange", ErrUnsupported)
+}
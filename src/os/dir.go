@@ -5,6 +5,7 @@
 package os
 
 import (
+	"io"
 	"io/fs"
 	"sort"
 )
@@ -103,6 +104,36 @@ func (f *File) ReadDir(n int) ([]DirEntry, error) {
 	return dirents, err
 }
 
+// DirEntries returns an iterator over the remaining DirEntry records in
+// the directory associated with the file f, in directory order. The
+// iterator calls yield with each entry in turn, stopping early if yield
+// returns false. It reads the directory in batches, so memory use does
+// not grow with the size of the directory. If reading fails with an
+// error other than io.EOF, the iterator calls yield once more with a nil
+// DirEntry and that error, and then stops.
+//
+// The iterator has the form func(yield func(DirEntry, error) bool),
+// so it is called directly with the loop body as yield.
+func (f *File) DirEntries() func(yield func(DirEntry, error) bool) {
+	return func(yield func(DirEntry, error) bool) {
+		for {
+			entries, err := f.ReadDir(readDirBatch)
+			for _, e := range entries {
+				if !yield(e, nil) {
+					return
+				}
+			}
+			if err == io.EOF {
+				return
+			}
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+		}
+	}
+}
+
 // testingForceReadDirLstat forces ReadDir to call Lstat, for testing that code path.
 // This can be difficult to provoke on some Unix systems otherwise.
 var testingForceReadDirLstat bool
@@ -123,3 +154,186 @@ func ReadDir(name string) ([]DirEntry, error) {
 	sort.Slice(dirs, func(i, j int) bool { return dirs[i].Name() < dirs[j].Name() })
 	return dirs, err
 }
+
+// readDirBatch is the number of entries that ReadDirFiltered and
+// the DirEntries iterators read from the directory at a time.
+const readDirBatch = 256
+
+// ReadDirFiltered reads the named directory, returning the directory
+// entries for which keep returns true, sorted by filename.
+// The directory is read in batches and each entry is passed to keep
+// as it is read, so rejected entries are not retained. keep sees the
+// entry's Type without an extra system call on most platforms, making
+// it cheap to filter by type; calling the entry's Info method does
+// require a call to Lstat.
+// If an error occurs reading the directory, ReadDirFiltered returns the
+// entries it was able to read before the error, along with the error.
+func ReadDirFiltered(name string, keep func(DirEntry) bool) ([]DirEntry, error) {
+	f, err := Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var dirs []DirEntry
+	for {
+		batch, err := f.ReadDir(readDirBatch)
+		for _, d := range batch {
+			if keep(d) {
+				dirs = append(dirs, d)
+			}
+		}
+		if err != nil {
+			if err == io.EOF {
+				err = nil
+			}
+			sort.Slice(dirs, func(i, j int) bool { return dirs[i].Name() < dirs[j].Name() })
+			return dirs, err
+		}
+	}
+}
+
+// ReadDirs reads the named directory, returning the entries for its
+// subdirectories sorted by filename. Symbolic links to directories
+// are not included.
+// If an error occurs reading the directory, ReadDirs returns the
+// entries it was able to read before the error, along with the error.
+func ReadDirs(name string) ([]DirEntry, error) {
+	return ReadDirFiltered(name, DirEntry.IsDir)
+}
+
+// DirEntries returns an iterator over the entries of the named
+// directory, in directory order. Unlike ReadDir, it reads the directory
+// lazily, as File.DirEntries does, and does not sort the entries.
+// The directory is opened when the iterator is called and closed when
+// it returns, whether iteration finished or yield returned false.
+// If the directory cannot be opened or read, the iterator calls yield
+// with a nil DirEntry and the error, and then stops.
+func DirEntries(name string) func(yield func(DirEntry, error) bool) {
+	return func(yield func(DirEntry, error) bool) {
+		f, err := Open(name)
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		defer f.Close()
+		f.DirEntries()(yield)
+	}
+}
+
+// CountDirEntries returns the number of entries in the named directory.
+// It reads the names in batches and does not retain them, so the memory
+// it uses does not depend on the size of the directory. If an error
+// occurs reading the directory, CountDirEntries returns the number of
+// entries it read before the error, along with the error.
+func CountDirEntries(name string) (int, error) {
+	f, err := Open(name)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	n := 0
+	for {
+		names, err := f.Readdirnames(readDirBatch)
+		n += len(names)
+		if err != nil {
+			if err == io.EOF {
+				err = nil
+			}
+			return n, err
+		}
+	}
+}
+
+// Files returns an iterator over the paths of the regular files in the
+// tree rooted at root, including root itself if it is a regular file.
+// The paths are formed by joining root and the names of the entries,
+// and are visited depth first in lexical order, as by filepath.WalkDir.
+// Directories and other non-regular files are not yielded, and symbolic
+// links are not followed. Each directory is read only when the iterator
+// reaches it.
+//
+// If root or a directory beneath it cannot be read, the iterator calls
+// yield with that path and the error, and then continues with the rest
+// of the tree unless yield returns false.
+func Files(root string) func(yield func(path string, err error) bool) {
+	return func(yield func(string, error) bool) {
+		fi, err := Lstat(root)
+		if err != nil {
+			yield(root, err)
+			return
+		}
+		switch {
+		case fi.Mode().IsRegular():
+			yield(root, nil)
+		case fi.IsDir():
+			walkFiles(root, yield)
+		}
+	}
+}
+
+// walkFiles implements Files for the directory dir, reporting
+// whether iteration should continue.
+func walkFiles(dir string, yield func(string, error) bool) bool {
+	entries, err := ReadDir(dir)
+	if err != nil && !yield(dir, err) {
+		return false
+	}
+	for _, e := range entries {
+		path := joinPath(dir, e.Name())
+		switch {
+		case e.Type().IsRegular():
+			if !yield(path, nil) {
+				return false
+			}
+		case e.IsDir():
+			if !walkFiles(path, yield) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// ReadDirDepth returns the paths, relative to root, of the entries in
+// the tree rooted at root, descending at most maxDepth levels below
+// root. A maxDepth of 0 lists only root's direct children; 1 also lists
+// the children of those that are directories, and so on. Directories at
+// the depth limit are listed but not read. A negative maxDepth means
+// no limit.
+//
+// The paths are returned in lexical order, with each directory
+// preceding its contents, as by filepath.WalkDir. Symbolic links are
+// listed but never followed, so cycles cannot cause infinite recursion.
+// If an error occurs reading a directory, ReadDirDepth returns the
+// paths it listed before the error, along with the error.
+func ReadDirDepth(root string, maxDepth int) ([]string, error) {
+	var paths []string
+	err := readDirDepth(root, "", maxDepth, &paths)
+	return paths, err
+}
+
+// readDirDepth appends to *paths the entries of the directory
+// root/rel, prefixed by rel, recursing into subdirectories until
+// depth reaches zero.
+func readDirDepth(root, rel string, depth int, paths *[]string) error {
+	dir := root
+	if rel != "" {
+		dir = joinPath(root, rel)
+	}
+	entries, err := ReadDir(dir)
+	for _, e := range entries {
+		path := e.Name()
+		if rel != "" {
+			path = rel + string(PathSeparator) + path
+		}
+		*paths = append(*paths, path)
+		if e.IsDir() && depth != 0 {
+			if err := readDirDepth(root, path, depth-1, paths); err != nil {
+				return err
+			}
+		}
+	}
+	return err
+}
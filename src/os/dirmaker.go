@@ -0,0 +1,88 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+import (
+	"sync"
+	"syscall"
+)
+
+// A DirMaker creates directories like MkdirAll, but remembers which
+// directories it has created or found to exist, so that programs that
+// write many files into the same tree, such as archive extractors, do
+// not check the same parent directories again for every file.
+//
+// The DirMaker does not observe changes to the file system: a
+// directory removed after the DirMaker has seen it is not created
+// again. A DirMaker is safe for concurrent use.
+type DirMaker struct {
+	perm FileMode
+	mu   sync.RWMutex
+	dirs map[string]bool
+}
+
+// NewDirMaker returns a DirMaker that creates directories with
+// permission bits perm (before umask).
+func NewDirMaker(perm FileMode) *DirMaker {
+	return &DirMaker{perm: perm, dirs: make(map[string]bool)}
+}
+
+// EnsureDir creates the directory named path, along with any necessary
+// parents, unless the DirMaker knows it already exists. Like MkdirAll,
+// it does nothing if path is already a directory, and otherwise
+// returns the first error it encounters.
+func (m *DirMaker) EnsureDir(path string) error {
+	m.mu.RLock()
+	known := m.dirs[path]
+	m.mu.RUnlock()
+	if known {
+		return nil
+	}
+
+	// Try to create path first: usually its parent exists,
+	// and then this is the only system call needed.
+	err := Mkdir(path, m.perm)
+	if IsNotExist(err) {
+		if parent := parentDir(path); parent != "" {
+			if err := m.EnsureDir(parent); err != nil {
+				return err
+			}
+			err = Mkdir(path, m.perm)
+		}
+	}
+	if err != nil {
+		// Handle a directory that already exists, including
+		// arguments like "foo/." and one created concurrently.
+		dir, err1 := Stat(path)
+		if err1 != nil {
+			return err
+		}
+		if !dir.IsDir() {
+			return &PathError{Op: "mkdir", Path: path, Err: syscall.ENOTDIR}
+		}
+	}
+
+	m.mu.Lock()
+	m.dirs[path] = true
+	m.mu.Unlock()
+	return nil
+}
+
+// parentDir returns the directory containing path, as MkdirAll
+// determines it, or "" if path has no parent to create.
+func parentDir(path string) string {
+	i := len(path)
+	for i > 0 && IsPathSeparator(path[i-1]) { // Skip trailing path separator.
+		i--
+	}
+	j := i
+	for j > 0 && !IsPathSeparator(path[j-1]) { // Scan backward over element.
+		j--
+	}
+	if j <= 1 {
+		return ""
+	}
+	return fixRootDirectory(path[:j-1])
+}
@@ -139,3 +139,45 @@ func Clearenv() {
 func Environ() []string {
 	return syscall.Environ()
 }
+
+// WithEnv sets the environment variables named by the keys of vars
+// to the corresponding values, calls fn, and then restores each of
+// those variables to its previous state. Variables that were not set
+// before the call are unset again. The variables are restored even
+// if fn panics.
+//
+// If setting a variable fails, WithEnv restores the variables it has
+// already changed and returns the error without calling fn.
+//
+// The environment is shared by the whole process: other goroutines
+// observe the new values while fn runs, and changes they make to the
+// same variables are overwritten when WithEnv restores them.
+// WithEnv must not be used concurrently with code that reads or
+// modifies the affected variables.
+func WithEnv(vars map[string]string, fn func()) error {
+	type saved struct {
+		value string
+		ok    bool
+	}
+	old := make(map[string]saved, len(vars))
+	restore := func() {
+		for key, s := range old {
+			if s.ok {
+				syscall.Setenv(key, s.value)
+			} else {
+				syscall.Unsetenv(key)
+			}
+		}
+	}
+	for key, value := range vars {
+		v, ok := LookupEnv(key)
+		if err := Setenv(key, value); err != nil {
+			restore()
+			return err
+		}
+		old[key] = saved{v, ok}
+	}
+	defer restore()
+	fn()
+	return nil
+}
@@ -166,3 +166,51 @@ func TestLookupEnv(t *testing.T) {
 		t.Errorf("smallpox release failed; world remains safe but LookupEnv is broken")
 	}
 }
+
+func TestWithEnv(t *testing.T) {
+	const setKey = "GO_TEST_WITHENV_SET"
+	const unsetKey = "GO_TEST_WITHENV_UNSET"
+	if err := Setenv(setKey, "old"); err != nil {
+		t.Fatal(err)
+	}
+	defer Unsetenv(setKey)
+	Unsetenv(unsetKey)
+
+	vars := map[string]string{setKey: "new", unsetKey: "1"}
+	called := false
+	err := WithEnv(vars, func() {
+		called = true
+		for key, want := range vars {
+			if got := Getenv(key); got != want {
+				t.Errorf("inside WithEnv: $%s = %q, want %q", key, got, want)
+			}
+		}
+	})
+	if err != nil {
+		t.Fatalf("WithEnv: %v", err)
+	}
+	if !called {
+		t.Fatal("WithEnv did not call fn")
+	}
+	if got := Getenv(setKey); got != "old" {
+		t.Errorf("after WithEnv: $%s = %q, want %q", setKey, got, "old")
+	}
+	if v, ok := LookupEnv(unsetKey); ok {
+		t.Errorf("after WithEnv: $%s = %q, want unset", unsetKey, v)
+	}
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("panic in fn was not propagated")
+			}
+		}()
+		WithEnv(vars, func() { panic("fn") })
+	}()
+	if got := Getenv(setKey); got != "old" {
+		t.Errorf("after panic: $%s = %q, want %q", setKey, got, "old")
+	}
+	if v, ok := LookupEnv(unsetKey); ok {
+		t.Errorf("after panic: $%s = %q, want unset", unsetKey, v)
+	}
+}
@@ -24,12 +24,19 @@ var (
 	ErrNotExist   = fs.ErrNotExist   // "file does not exist"
 	ErrClosed     = fs.ErrClosed     // "file already closed"
 
+	// ErrUnsupported indicates that a requested operation cannot be
+	// performed, because it is unsupported on this platform, file or
+	// file system. System call errors such as ENOSYS, ENOTSUP and
+	// EOPNOTSUPP match it when tested with errors.Is.
+	ErrUnsupported = errUnsupported() // "operation not supported"
+
 	ErrNoDeadline       = errNoDeadline()       // "file type does not support deadline"
 	ErrDeadlineExceeded = errDeadlineExceeded() // "i/o timeout"
 )
 
-func errClosed() error     { return oserror.ErrClosed }
-func errNoDeadline() error { return poll.ErrNoDeadline }
+func errClosed() error      { return oserror.ErrClosed }
+func errUnsupported() error { return oserror.ErrUnsupported }
+func errNoDeadline() error  { return poll.ErrNoDeadline }
 
 // errDeadlineExceeded returns the value for os.ErrDeadlineExceeded.
 // This error comes from the internal/poll package, which is also
@@ -0,0 +1,40 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+// Eventfd returns a file holding a 64-bit counter that starts at
+// initval, for waking up goroutines or processes that wait on file
+// descriptors. WriteEventfd adds to the counter, and the file is
+// readable whenever the counter is nonzero. ReadEventfd returns the
+// counter and resets it to zero or, if semaphore is true, returns 1
+// and decrements it. The file works with the runtime poller, so reads
+// honor SetReadDeadline, and can be passed to a child process to
+// signal across processes.
+//
+// Eventfd is only supported on Linux. On other systems it returns an
+// error wrapping ErrUnsupported.
+func Eventfd(initval uint, semaphore bool) (*File, error) {
+	return eventfd(initval, semaphore)
+}
+
+// ReadEventfd waits for the counter of f, which must have been
+// returned by Eventfd, to be nonzero, and then reads it as described
+// for Eventfd.
+func (f *File) ReadEventfd() (uint64, error) {
+	if err := f.checkValid("read"); err != nil {
+		return 0, err
+	}
+	return f.readCounter()
+}
+
+// WriteEventfd adds n to the counter of f, which must have been
+// returned by Eventfd. If that would make the counter exceed the
+// maximum of 1<<64-2, WriteEventfd waits for a read to lower it.
+func (f *File) WriteEventfd(n uint64) error {
+	if err := f.checkValid("write"); err != nil {
+		return err
+	}
+	return f.writeCounter(n)
+}
@@ -0,0 +1,29 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+import (
+	"internal/syscall/unix"
+	"syscall"
+	"unsafe"
+)
+
+func eventfd(initval uint, semaphore bool) (*File, error) {
+	flags := syscall.O_NONBLOCK | syscall.O_CLOEXEC
+	if semaphore {
+		flags |= unix.EFD_SEMAPHORE
+	}
+	fd, err := unix.Eventfd(initval, flags)
+	if err != nil {
+		return nil, NewSyscallError("eventfd", err)
+	}
+	return newFile(uintptr(fd), "eventfd", kindNonBlock), nil
+}
+
+func (f *File) writeCounter(n uint64) error {
+	b := (*[8]byte)(unsafe.Pointer(&n))[:]
+	_, err := f.write(b)
+	return f.wrapErr("write", err)
+}
@@ -0,0 +1,16 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !linux
+// +build !linux
+
+package os
+
+func eventfd(initval uint, semaphore bool) (*File, error) {
+	return nil, NewSyscallError("eventfd", ErrUnsupported)
+}
+
+func (f *File) writeCounter(n uint64) error {
+	return f.wrapErr("write", ErrUnsupported)
+}
@@ -96,6 +96,12 @@ func findProcess(pid int) (p *Process, err error) {
 	return newProcess(pid, 0), nil
 }
 
+// processAlive reports whether a process with the given pid exists.
+func processAlive(pid int) bool {
+	_, err := Stat("/proc/" + itoa.Itoa(pid))
+	return err == nil
+}
+
 // ProcessState stores information about a process, as reported by Wait.
 type ProcessState struct {
 	pid    int              // The process's id.
@@ -98,6 +98,20 @@ func findProcess(pid int) (p *Process, err error) {
 	return newProcess(pid, 0), nil
 }
 
+// processAlive reports whether a process with the given pid exists.
+func processAlive(pid int) bool {
+	if pid == Getpid() {
+		return true
+	}
+	switch err := syscall.Kill(pid, 0); err {
+	case nil, syscall.EPERM:
+		// EPERM means the process exists but belongs to someone else.
+		return true
+	default:
+		return false
+	}
+}
+
 func (p *ProcessState) userTime() time.Duration {
 	return time.Duration(p.rusage.Utime.Nano()) * time.Nanosecond
 }
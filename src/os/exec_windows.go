@@ -97,6 +97,27 @@ func findProcess(pid int) (p *Process, err error) {
 	return newProcess(pid, uintptr(h)), nil
 }
 
+// processAlive reports whether a process with the given pid exists
+// and has not exited.
+func processAlive(pid int) bool {
+	if pid == Getpid() {
+		return true
+	}
+	const _PROCESS_QUERY_LIMITED_INFORMATION = 0x1000
+	h, e := syscall.OpenProcess(_PROCESS_QUERY_LIMITED_INFORMATION, false, uint32(pid))
+	if e != nil {
+		// A process we may not query still exists.
+		return e == syscall.ERROR_ACCESS_DENIED
+	}
+	defer syscall.CloseHandle(h)
+	const _STILL_ACTIVE = 259
+	var code uint32
+	if err := syscall.GetExitCodeProcess(h, &code); err != nil {
+		return true
+	}
+	return code == _STILL_ACTIVE
+}
+
 func init() {
 	cmd := windows.UTF16PtrToString(syscall.GetCommandLine())
 	if len(cmd) == 0 {
@@ -11,3 +11,10 @@ var LstatP = &lstat
 var ErrWriteAtInAppendMode = errWriteAtInAppendMode
 var TestingForceReadDirLstat = &testingForceReadDirLstat
 var ErrPatternHasSeparator = errPatternHasSeparator
+var TailPollInterval = &tailPollInterval
+
+func StatCacheLen(c *StatCache) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.m)
+}
@@ -0,0 +1,58 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+// An Extent is a range of a file that is stored contiguously on disk, or
+// a hole in the file, as returned by FileExtents.
+type Extent struct {
+	Logical  int64 // offset of the range in the file
+	Physical int64 // offset of the range on the device; 0 for holes
+	Length   int64
+	Flags    ExtentFlags
+}
+
+// ExtentFlags describe an Extent. Apart from ExtentHole, the values are
+// those of the FIEMAP_EXTENT_* flags, so file systems can set flags
+// that have no name here.
+type ExtentFlags uint32
+
+const (
+	ExtentUnknown    ExtentFlags = 0x2    // the location of the data is not known
+	ExtentDelalloc   ExtentFlags = 0x4    // the data has not been allocated yet
+	ExtentEncoded    ExtentFlags = 0x8    // the data is compressed or otherwise encoded
+	ExtentEncrypted  ExtentFlags = 0x80   // the data is encrypted
+	ExtentNotAligned ExtentFlags = 0x100  // the extent is not block aligned
+	ExtentInline     ExtentFlags = 0x200  // the data is stored with the metadata
+	ExtentTail       ExtentFlags = 0x400  // the data is packed with other files' tails
+	ExtentUnwritten  ExtentFlags = 0x800  // the space is allocated but reads as zeros
+	ExtentMerged     ExtentFlags = 0x1000 // the file system merged several extents
+	ExtentShared     ExtentFlags = 0x2000 // the space is shared with other files
+
+	// ExtentHole marks a range of the file that has no space allocated
+	// and reads as zeros.
+	ExtentHole ExtentFlags = 1 << 31
+)
+
+// FileExtents returns the layout of the file f on disk, as a list of
+// extents in order of their offset in the file. The extents cover the
+// whole file: each gap between the allocated ranges reported by the
+// file system, and any gap before the end of the file, is returned as
+// an extent with the ExtentHole flag. The last extent can extend past
+// the end of the file, if space has been allocated there.
+//
+// FileExtents uses the FIEMAP ioctl, and flushes the data of f to disk
+// first so that its location is known. Comparing the physical offsets
+// of two files shows, for example, whether they share storage after
+// CloneFile. Unlike the data and holes found with lseek(2), the extents
+// are those of the underlying storage, and a file system may report as
+// data a range that reads as zeros. If the file system does not support
+// FIEMAP, or on systems other than Linux, FileExtents returns an error
+// wrapping ErrUnsupported.
+func FileExtents(f *File) ([]Extent, error) {
+	if err := f.checkValid("fiemap"); err != nil {
+		return nil, err
+	}
+	return fileExtents(f)
+}
@@ -0,0 +1,110 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+import (
+	"internal/syscall/unix"
+	"runtime"
+	"syscall"
+	"unsafe"
+)
+
+// maxFiemapExtents is the most extents fetched by one FS_IOC_FIEMAP
+// ioctl.
+const maxFiemapExtents = 512
+
+func fileExtents(f *File) ([]Extent, error) {
+	fi, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	size := fi.Size()
+
+	var extents []Extent
+	var next int64 // offset in the file up to which extents are known
+	addHole := func(end int64) {
+		if end > next {
+			extents = append(extents, Extent{Logical: next, Length: end - next, Flags: ExtentHole})
+			next = end
+		}
+	}
+	flags := uint32(unix.FIEMAP_FLAG_SYNC)
+	for {
+		// Ask how many extents there are from next onwards, then
+		// fetch them. The file can change between the calls, so
+		// repeat until the last extent is seen.
+		n, err := f.fiemap(next, flags, nil)
+		if err != nil {
+			return nil, err
+		}
+		flags = 0
+		if n == 0 {
+			break
+		}
+		if n > maxFiemapExtents {
+			n = maxFiemapExtents
+		}
+		buf := make([]unix.FiemapExtent, n)
+		n, err = f.fiemap(next, 0, buf)
+		if err != nil {
+			return nil, err
+		}
+		if n == 0 {
+			break
+		}
+		last := false
+		for _, fe := range buf[:n] {
+			addHole(int64(fe.Logical))
+			extents = append(extents, Extent{
+				Logical:  int64(fe.Logical),
+				Physical: int64(fe.Physical),
+				Length:   int64(fe.Length),
+				Flags:    ExtentFlags(fe.Flags &^ unix.FIEMAP_EXTENT_LAST),
+			})
+			next = int64(fe.Logical + fe.Length)
+			last = fe.Flags&unix.FIEMAP_EXTENT_LAST != 0
+		}
+		if last {
+			break
+		}
+	}
+	addHole(size)
+	return extents, nil
+}
+
+// fiemap fetches into buf the extents of f from offset start onwards,
+// and returns how many it fetched. If buf is empty, it returns how many
+// extents there are.
+func (f *File) fiemap(start int64, flags uint32, buf []unix.FiemapExtent) (int, error) {
+	// The argument is a Fiemap followed by an array of FiemapExtent;
+	// a []uint64 keeps both aligned.
+	const hdrSize = unsafe.Sizeof(unix.Fiemap{})
+	const extentSize = unsafe.Sizeof(unix.FiemapExtent{})
+	arg := make([]uint64, (hdrSize+uintptr(len(buf))*extentSize)/8)
+	hdr := (*unix.Fiemap)(unsafe.Pointer(&arg[0]))
+	hdr.Start = uint64(start)
+	hdr.Length = ^uint64(0) - uint64(start)
+	hdr.Flags = flags
+	hdr.ExtentCount = uint32(len(buf))
+
+	var e error
+	if err := f.pfd.RawControl(func(fd uintptr) {
+		e = unix.IoctlPtr(int(fd), unix.FS_IOC_FIEMAP, unsafe.Pointer(&arg[0]))
+	}); err != nil {
+		return 0, f.wrapErr("fiemap", err)
+	}
+	runtime.KeepAlive(f)
+	if e != nil {
+		if e == syscall.ENOTTY {
+			e = ErrUnsupported
+		}
+		return 0, f.wrapErr("fiemap", e)
+	}
+	n := int(hdr.MappedExtents)
+	if len(buf) > 0 {
+		n = copy(buf, (*[maxFiemapExtents]unix.FiemapExtent)(unsafe.Pointer(&arg[hdrSize/8]))[:n:n])
+	}
+	return n, nil
+}
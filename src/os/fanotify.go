@@ -0,0 +1,71 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+// A FanotifyEvent is an event read from a fanotify file.
+type FanotifyEvent struct {
+	Mask uint64 // the events that occurred, such as FAN_OPEN_PERM
+	Pid  int    // the process that caused the event
+
+	// File is the file the event is about, opened for the listener
+	// with the event flags passed to FanotifyInit, or nil for a queue
+	// overflow event. The caller must close it, after responding to
+	// a permission event.
+	File *File
+}
+
+// FanotifyInit creates a fanotify group and returns the file from which
+// its events are read with ReadFanotifyEvents. Unlike inotify, fanotify
+// can watch entire mounts and file systems, and a group created with
+// a content class can decide whether each access to a marked file is
+// allowed, with FanotifyRespond. The flags and the event flags, which
+// are the open flags used for the files passed in events, are those of
+// fanotify_init(2); the FAN_* constants are defined in
+// golang.org/x/sys/unix. The returned file, and the files in events,
+// are always close-on-exec, and the returned file works with the
+// runtime poller, so reads honor SetReadDeadline.
+//
+// Creating a fanotify group usually requires the CAP_SYS_ADMIN
+// capability; without it FanotifyInit returns an error wrapping EPERM.
+// FanotifyInit is only supported on Linux. On other systems it returns
+// an error wrapping ErrUnsupported.
+func FanotifyInit(flags, eventFlags uint) (*File, error) {
+	return fanotifyInit(flags, eventFlags)
+}
+
+// FanotifyMark adds, removes or changes the marks of the fanotify group
+// f, as selected by flags, on the file, directory, mount or file system
+// at path, which is interpreted relative to the open directory dirfd.
+// If path is empty, the mark is on dirfd itself; a dirfd of -100
+// (AT_FDCWD) refers to the current directory. The mask selects the
+// events of interest. See fanotify_mark(2).
+func (f *File) FanotifyMark(flags uint, mask uint64, dirfd int, path string) error {
+	if err := f.checkValid("fanotify_mark"); err != nil {
+		return err
+	}
+	return f.fanotifyMark(flags, mask, dirfd, path)
+}
+
+// ReadFanotifyEvents waits for events on the fanotify file f and
+// returns those that are available.
+func (f *File) ReadFanotifyEvents() ([]FanotifyEvent, error) {
+	if err := f.checkValid("read"); err != nil {
+		return nil, err
+	}
+	return f.readFanotifyEvents()
+}
+
+// FanotifyRespond allows or denies the access that caused the
+// permission event ev, which was read from the fanotify file f. The
+// process making the access is blocked until the response is written.
+func (f *File) FanotifyRespond(ev FanotifyEvent, allow bool) error {
+	if err := f.checkValid("write"); err != nil {
+		return err
+	}
+	if ev.File == nil {
+		return f.wrapErr("write", ErrInvalid)
+	}
+	return f.fanotifyRespond(ev.File, allow)
+}
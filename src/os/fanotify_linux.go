@@ -0,0 +1,112 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+import (
+	"internal/itoa"
+	"internal/syscall/unix"
+	"runtime"
+	"syscall"
+	"unsafe"
+)
+
+// fanotifyEventMetadata is struct fanotify_event_metadata, which
+// starts each event read from a fanotify file.
+type fanotifyEventMetadata struct {
+	eventLen    uint32
+	vers        uint8
+	reserved    uint8
+	metadataLen uint16
+	mask        uint64
+	fd          int32
+	pid         int32
+}
+
+// fanotifyResponse is struct fanotify_response.
+type fanotifyResponse struct {
+	fd       int32
+	response uint32
+}
+
+func fanotifyInit(flags, eventFlags uint) (*File, error) {
+	flags |= unix.FAN_CLOEXEC | unix.FAN_NONBLOCK
+	eventFlags |= syscall.O_CLOEXEC
+	fd, err := unix.FanotifyInit(flags, eventFlags)
+	if err != nil {
+		return nil, NewSyscallError("fanotify_init", err)
+	}
+	return newFile(uintptr(fd), "fanotify", kindNonBlock), nil
+}
+
+func (f *File) fanotifyMark(flags uint, mask uint64, dirfd int, path string) error {
+	var p *byte
+	if path != "" {
+		var err error
+		if p, err = syscall.BytePtrFromString(path); err != nil {
+			return &PathError{Op: "fanotify_mark", Path: path, Err: err}
+		}
+	}
+	var e error
+	if err := f.pfd.RawControl(func(fd uintptr) {
+		e = unix.FanotifyMark(int(fd), flags, mask, dirfd, p)
+	}); err != nil {
+		return f.wrapErr("fanotify_mark", err)
+	}
+	runtime.KeepAlive(f)
+	if e != nil {
+		return &PathError{Op: "fanotify_mark", Path: path, Err: e}
+	}
+	return nil
+}
+
+func (f *File) readFanotifyEvents() ([]FanotifyEvent, error) {
+	buf := make([]byte, 4096)
+	n, err := f.read(buf)
+	if err != nil {
+		return nil, f.wrapErr("read", err)
+	}
+	buf = buf[:n]
+	var events []FanotifyEvent
+	for len(buf) > 0 {
+		var m fanotifyEventMetadata
+		const size = int(unsafe.Sizeof(m))
+		if len(buf) < size {
+			// The kernel returns only whole events.
+			return events, f.wrapErr("read", ErrInvalid)
+		}
+		copy((*[size]byte)(unsafe.Pointer(&m))[:], buf)
+		if m.vers != unix.FANOTIFY_METADATA_VERSION || int(m.eventLen) < size || int(m.eventLen) > len(buf) {
+			return events, f.wrapErr("read", ErrInvalid)
+		}
+		buf = buf[m.eventLen:]
+		ev := FanotifyEvent{Mask: m.mask, Pid: int(m.pid)}
+		if m.fd != unix.FAN_NOFD {
+			// Name the file after its path, if it still has one.
+			name, err := Readlink("/proc/self/fd/" + itoa.Itoa(int(m.fd)))
+			if err != nil {
+				name = "fanotify event"
+			}
+			ev.File = NewFile(uintptr(m.fd), name)
+		}
+		events = append(events, ev)
+	}
+	return events, nil
+}
+
+func (f *File) fanotifyRespond(file *File, allow bool) error {
+	resp := fanotifyResponse{response: unix.FAN_DENY}
+	if allow {
+		resp.response = unix.FAN_ALLOW
+	}
+	if err := file.pfd.RawControl(func(fd uintptr) {
+		resp.fd = int32(fd)
+	}); err != nil {
+		return file.wrapErr("write", err)
+	}
+	b := (*[unsafe.Sizeof(resp)]byte)(unsafe.Pointer(&resp))[:]
+	_, err := f.write(b)
+	runtime.KeepAlive(file)
+	return f.wrapErr("write", err)
+}
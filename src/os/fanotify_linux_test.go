@@ -0,0 +1,76 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os_test
+
+import (
+	"errors"
+	. "os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestFanotifyPermission(t *testing.T) {
+	const (
+		FAN_CLASS_CONTENT  = 0x4
+		FAN_MARK_ADD       = 0x1
+		FAN_OPEN_PERM      = 0x10000
+		FAN_EVENT_ON_CHILD = 0x8000000
+	)
+	f, err := FanotifyInit(FAN_CLASS_CONTENT, uint(O_RDONLY))
+	if err != nil {
+		if errors.Is(err, syscall.EPERM) || errors.Is(err, syscall.ENOSYS) || errors.Is(err, syscall.EINVAL) {
+			t.Skipf("FanotifyInit: %v", err)
+		}
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	dir := t.TempDir()
+	name := filepath.Join(dir, "file")
+	if err := WriteFile(name, []byte("data"), 0666); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.FanotifyMark(FAN_MARK_ADD, FAN_OPEN_PERM|FAN_EVENT_ON_CHILD, -100, dir); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, allow := range []bool{true, false} {
+		done := make(chan error, 1)
+		go func() {
+			g, err := Open(name)
+			if err == nil {
+				g.Close()
+			}
+			done <- err
+		}()
+
+		f.SetReadDeadline(time.Now().Add(10 * time.Second))
+		events, err := f.ReadFanotifyEvents()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(events) != 1 {
+			t.Fatalf("got %d events, want 1", len(events))
+		}
+		ev := events[0]
+		if ev.Mask&FAN_OPEN_PERM == 0 || ev.Pid != Getpid() || ev.File == nil || ev.File.Name() != name {
+			t.Errorf("event = %+v, want FAN_OPEN_PERM by this process for %s", ev, name)
+		}
+		if err := f.FanotifyRespond(ev, allow); err != nil {
+			t.Fatal(err)
+		}
+		ev.File.Close()
+
+		err = <-done
+		if allow && err != nil {
+			t.Errorf("allowed open failed: %v", err)
+		}
+		if !allow && !errors.Is(err, syscall.EPERM) {
+			t.Errorf("denied open: got %v, want EPERM", err)
+		}
+	}
+}
@@ -0,0 +1,24 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !linux
+// +build !linux
+
+package os
+
+func fanotifyInit(flags, eventFlags uint) (*File, error) {
+	return nil, NewSyscallError("fanotify_init", ErrUnsupported)
+}
+
+func (f *File) fanotifyMark(flags uint, mask uint64, dirfd int, path string) error {
+	return &PathError{Op: "fanotify_mark", Path: path, Err: ErrUnsupported}
+}
+
+func (f *File) readFanotifyEvents() ([]FanotifyEvent, error) {
+	return nil, f.wrapErr("read", ErrUnsupported)
+}
+
+func (f *File) fanotifyRespond(file *File, allow bool) error {
+	return f.wrapErr("write", ErrUnsupported)
+}
@@ -295,19 +295,33 @@ func setStickyBit(name string) error {
 }
 
 // Chdir changes the current working directory to the named directory.
+// The path is resolved when Chdir is called; to change to a directory
+// that is already open, even if it has since been renamed, use
+// File.Chdir or Fchdir.
 // If there is an error, it will be of type *PathError.
 func Chdir(dir string) error {
 	if e := syscall.Chdir(dir); e != nil {
 		testlog.Open(dir) // observe likely non-existent directory
 		return &PathError{Op: "chdir", Path: dir, Err: e}
 	}
+	logChdir()
+	return nil
+}
+
+// Fchdir changes the current working directory to the open directory f,
+// as f.Chdir does.
+func Fchdir(f *File) error {
+	return f.Chdir()
+}
+
+// logChdir reports a change of the working directory to the test log.
+func logChdir() {
 	if log := testlog.Logger(); log != nil {
 		wd, err := Getwd()
 		if err == nil {
 			log.Chdir(wd)
 		}
 	}
-	return nil
 }
 
 // Open opens the named file for reading. If successful, methods on
@@ -327,6 +341,106 @@ func Create(name string) (*File, error) {
 	return OpenFile(name, O_RDWR|O_CREATE|O_TRUNC, 0666)
 }
 
+// OpenOrCreate opens the named file for reading and writing, creating
+// it with mode perm (before umask) if it does not exist. Unlike Create,
+// it never truncates an existing file. It reports whether the file was
+// created, so that the caller can initialize a new file's contents,
+// and does so reliably even if other processes try to create the file
+// at the same time: only one of them is told it created the file.
+// If there is an error, it will be of type *PathError.
+func OpenOrCreate(name string, perm FileMode) (f *File, created bool, err error) {
+	// Each retry means another process removed the file between our
+	// two opens; give up rather than spin if that keeps happening.
+	const maxTries = 100
+	for i := 0; ; i++ {
+		f, err = OpenFile(name, O_RDWR|O_CREATE|O_EXCL, perm)
+		if err == nil {
+			return f, true, nil
+		}
+		if !IsExist(err) {
+			return nil, false, err
+		}
+		f, err = OpenFile(name, O_RDWR, 0)
+		if err == nil {
+			return f, false, nil
+		}
+		if !IsNotExist(err) || i == maxTries-1 {
+			return nil, false, err
+		}
+		// Either the file was removed after we found it, or name is
+		// a symbolic link to a missing file, which O_EXCL refuses to
+		// follow. Only the first is worth retrying.
+		if fi, lerr := Lstat(name); lerr == nil && fi.Mode()&ModeSymlink != 0 {
+			return nil, false, err
+		}
+	}
+}
+
+// CreateLike creates or truncates the named file like Create, but gives
+// it the permission bits of the file template instead of 0666 modified
+// by the umask. If the process is privileged to do so, the new file is
+// also given template's owner and group. This is useful when replacing a
+// file, for instance when rotating logs, so that the new file matches
+// the old one. If template cannot be examined, CreateLike returns the
+// error from Stat and does not create name.
+//
+// A failure to set the ownership is ignored; use CreateLikeOwner to
+// learn about it.
+func CreateLike(name, template string) (*File, error) {
+	f, err := CreateLikeOwner(name, template)
+	if _, ok := err.(*OwnerError); ok {
+		err = nil
+	}
+	return f, err
+}
+
+// An OwnerError records a failure to give a file created by
+// CreateLikeOwner the owner and group of its template.
+type OwnerError struct {
+	Path string
+	Err  error // the error from File.Chown
+}
+
+func (e *OwnerError) Error() string {
+	return "copy owner to " + e.Path + ": " + e.Err.Error()
+}
+
+func (e *OwnerError) Unwrap() error { return e.Err }
+
+// CreateLikeOwner is like CreateLike but reports a failure to give the
+// new file template's ownership. In that case the error is an
+// *OwnerError, and f is also returned: the file has been created with
+// the correct permission bits, and only the ownership differs from
+// template. This typically happens when the process is not privileged.
+// The caller must close f even though err is non-nil. For any other
+// error f is nil.
+// On Windows and Plan 9 ownership is not copied and no *OwnerError is
+// returned.
+func CreateLikeOwner(name, template string) (*File, error) {
+	fi, err := Stat(template)
+	if err != nil {
+		return nil, err
+	}
+	mode := fi.Mode() & (ModePerm | ModeSetuid | ModeSetgid | ModeSticky)
+	f, err := CreateExact(name, mode)
+	if err != nil {
+		return nil, err
+	}
+	if uid, gid, ok := owner(fi); ok {
+		nfi, err := f.Stat()
+		if err != nil {
+			f.Close()
+			return nil, err
+		}
+		if nuid, ngid, _ := owner(nfi); nuid != uid || ngid != gid {
+			if err := f.Chown(uid, gid); err != nil {
+				return f, &OwnerError{Path: name, Err: err}
+			}
+		}
+	}
+	return f, nil
+}
+
 // OpenFile is the generalized open call; most users will use Open
 // or Create instead. It opens the named file with specified flag
 // (O_RDONLY etc.). If the file does not exist, and the O_CREATE flag
@@ -492,6 +606,51 @@ func UserConfigDir() (string, error) {
 	return dir, nil
 }
 
+// UserRuntimeDir returns the default root directory to use for
+// user-specific runtime files, such as sockets, named pipes and PID files.
+// Users should create their own application-specific subdirectory
+// within this one and use that.
+//
+// On Unix systems, it returns $XDG_RUNTIME_DIR as specified by
+// https://specifications.freedesktop.org/basedir-spec/basedir-spec-latest.html.
+// The specification requires that directory to be owned by the user,
+// have mode 0700, and be removed when the user's last session ends, so
+// it must not be used to store data that should persist across logins.
+// On Darwin, it returns $TMPDIR, which launchd sets to a private
+// per-user directory.
+// On Windows, it returns the directory returned by TempDir, which is
+// specific to the user.
+// On Plan 9, it returns /tmp.
+//
+// UserRuntimeDir does not create the directory; it is managed by the
+// operating system. If the location cannot be determined (for example,
+// $XDG_RUNTIME_DIR is not defined), then it will return an error.
+func UserRuntimeDir() (string, error) {
+	var dir string
+
+	switch runtime.GOOS {
+	case "windows":
+		dir = TempDir()
+
+	case "darwin", "ios":
+		dir = Getenv("TMPDIR")
+		if dir == "" {
+			return "", errors.New("$TMPDIR is not defined")
+		}
+
+	case "plan9":
+		dir = "/tmp"
+
+	default: // Unix
+		dir = Getenv("XDG_RUNTIME_DIR")
+		if dir == "" {
+			return "", errors.New("$XDG_RUNTIME_DIR is not defined")
+		}
+	}
+
+	return dir, nil
+}
+
 // UserHomeDir returns the current user's home directory.
 //
 // On Unix, including macOS, it returns the $HOME environment variable.
@@ -518,6 +677,78 @@ func UserHomeDir() (string, error) {
 	return "", errors.New(enverr + " is not defined")
 }
 
+// userDataDir returns the default root directory to use for
+// user-specific application data.
+//
+// On Unix systems, it returns $XDG_DATA_HOME if non-empty, else
+// $HOME/.local/share.
+// On Darwin, it returns $HOME/Library/Application Support.
+// On Windows, it returns %LocalAppData%.
+// On Plan 9, it returns $home/lib.
+func userDataDir() (string, error) {
+	switch runtime.GOOS {
+	case "windows":
+		return UserCacheDir()
+	case "darwin", "ios", "plan9":
+		return UserConfigDir()
+	}
+	if dir := Getenv("XDG_DATA_HOME"); dir != "" {
+		return dir, nil
+	}
+	dir, err := UserHomeDir()
+	if err != nil {
+		return "", errors.New("neither $XDG_DATA_HOME nor $HOME are defined")
+	}
+	return dir + "/.local/share", nil
+}
+
+// AppConfigDir returns the configuration directory for the application
+// named app, a subdirectory of the directory returned by UserConfigDir.
+// If the directory does not exist, AppConfigDir creates it, along with
+// any necessary parents, with mode 0700 (before umask).
+//
+// The application name must be a single path element: names that are
+// empty, ".", "..", or that contain a path separator are rejected with
+// an error wrapping ErrInvalid.
+func AppConfigDir(app string) (string, error) {
+	return appDir(app, UserConfigDir)
+}
+
+// AppCacheDir is like AppConfigDir but returns a subdirectory of the
+// directory returned by UserCacheDir.
+func AppCacheDir(app string) (string, error) {
+	return appDir(app, UserCacheDir)
+}
+
+// AppDataDir is like AppConfigDir but returns a subdirectory of the
+// default root directory for user-specific application data.
+//
+// On Unix systems, that root is $XDG_DATA_HOME if non-empty, else
+// $HOME/.local/share.
+// On Darwin, it is $HOME/Library/Application Support.
+// On Windows, it is %LocalAppData%.
+// On Plan 9, it is $home/lib.
+func AppDataDir(app string) (string, error) {
+	return appDir(app, userDataDir)
+}
+
+// appDir implements AppConfigDir, AppCacheDir and AppDataDir,
+// creating the subdirectory app of the directory returned by base.
+func appDir(app string, base func() (string, error)) (string, error) {
+	if app == "" || app == "." || app == ".." || containsAny(app, "/"+string(PathSeparator)) {
+		return "", &PathError{Op: "appdir", Path: app, Err: ErrInvalid}
+	}
+	dir, err := base()
+	if err != nil {
+		return "", err
+	}
+	dir += string(PathSeparator) + app
+	if err := MkdirAll(dir, 0700); err != nil {
+		return "", err
+	}
+	return dir, nil
+}
+
 // Chmod changes the mode of the named file to mode.
 // If the file is a symbolic link, it changes the mode of the link's target.
 // If there is an error, it will be of type *PathError.
@@ -538,10 +769,104 @@ func UserHomeDir() (string, error) {
 // and ModeTemporary are used.
 func Chmod(name string, mode FileMode) error { return chmod(name, mode) }
 
+// Lchmod changes the mode of the named file to mode. If the file is a
+// symbolic link, it changes the mode of the link itself rather than that
+// of its target. Lchmod never changes the target of a link: where the
+// system cannot change the mode of a symbolic link, Lchmod returns an
+// error wrapping ErrUnsupported instead.
+//
+// On Linux, which does not support modes on symbolic links, Lchmod
+// changes the mode of a file that is not a symbolic link, using /proc
+// so that a link substituted for the file is detected, and returns
+// ErrUnsupported for a link. Lchmod is supported on Darwin and the BSDs,
+// subject to the file system. On other systems it always returns an
+// error wrapping ErrUnsupported.
+// If there is an error, it will be of type *PathError.
+func Lchmod(name string, mode FileMode) error {
+	return lchmod(name, mode)
+}
+
+// Lchtimes changes the access and modification times of the named file,
+// like Chtimes, except that if the file is a symbolic link it changes the
+// times of the link itself rather than those of its target. A zero
+// time.Time value leaves the corresponding time unchanged.
+//
+// On Windows the times of a link are those of the reparse point, which
+// some tools ignore. Lchtimes is not supported on AIX, Solaris, Plan 9
+// or js/wasm, where it returns an error wrapping ErrUnsupported.
+// If there is an error, it will be of type *PathError.
+func Lchtimes(name string, atime, mtime time.Time) error {
+	return lchtimes(name, atime, mtime)
+}
+
+// Junction creates link as an NTFS directory junction that refers to
+// the directory target. Unlike Symlink, creating a junction on Windows
+// requires neither administrator privilege nor Developer Mode. The
+// target must be an existing directory on a local volume; it is
+// converted to an absolute path, since junctions cannot be relative.
+// Lstat reports a junction as a symbolic link, and Readlink returns
+// its target.
+//
+// Junctions exist only on Windows. On other systems Junction returns an
+// error wrapping ErrUnsupported; use Symlink there.
+// If there is an error, it will be of type *LinkError.
+func Junction(target, link string) error {
+	return junction(target, link)
+}
+
+// Tags of common Windows reparse points, as returned by ReparseTag.
+const (
+	ReparseTagMountPoint  uint32 = 0xA0000003 // IO_REPARSE_TAG_MOUNT_POINT: a junction or volume mount point
+	ReparseTagSymlink     uint32 = 0xA000000C // IO_REPARSE_TAG_SYMLINK: a symbolic link
+	ReparseTagAppExecLink uint32 = 0x8000001B // IO_REPARSE_TAG_APPEXECLINK: an app execution alias
+)
+
+// ReparseTag returns the tag of the Windows reparse point name, without
+// following it, along with the path it refers to. For symbolic links
+// and mount points the path is the substitute name exactly as stored,
+// which for an absolute target usually begins with \??\; for app
+// execution aliases it is the target executable. For other kinds of
+// reparse point the path is empty. Unlike Lstat and Readlink, which
+// treat some kinds of reparse point alike, ReparseTag lets callers
+// distinguish junctions from symbolic links from other reparse points.
+//
+// If name is not a reparse point, ReparseTag returns an error.
+// On systems other than Windows it returns an error wrapping ErrUnsupported.
+// If there is an error, it will be of type *PathError.
+func ReparseTag(name string) (tag uint32, path string, err error) {
+	return reparseTag(name)
+}
+
 // Chmod changes the mode of the file to mode.
 // If there is an error, it will be of type *PathError.
 func (f *File) Chmod(mode FileMode) error { return f.chmod(mode) }
 
+// Umask sets the process's file mode creation mask to mask and returns
+// the previous mask. The mask is applied to the permission bits passed
+// to OpenFile, Mkdir and similar functions when they create a file.
+// To read the mask without changing it, call Umask twice, restoring the
+// value returned by the first call.
+//
+// The mask is shared by the whole process, so changing it affects files
+// created concurrently by other goroutines. On Windows and Plan 9, which
+// have no umask, Umask does nothing and returns 0.
+func Umask(mask int) (old int) {
+	return umask(mask)
+}
+
+// WithUmask sets the file mode creation mask to mask, calls fn, and then
+// restores the previous mask, even if fn panics.
+//
+// Like Umask, WithUmask changes state shared by the whole process: files
+// created by other goroutines while fn runs are also subject to mask.
+// Programs that must create a single file with exact permissions may
+// prefer to call Chmod on the open file instead.
+func WithUmask(mask int, fn func()) {
+	old := umask(mask)
+	defer umask(old)
+	fn()
+}
+
 // SetDeadline sets the read and write deadlines for a File.
 // It is equivalent to calling both SetReadDeadline and SetWriteDeadline.
 //
@@ -656,6 +981,11 @@ func (dir dirFS) Open(name string) (fs.File, error) {
 // Because ReadFile reads the whole file, it does not treat an EOF from Read
 // as an error to be reported.
 func ReadFile(name string) ([]byte, error) {
+	return readFile(name, -1)
+}
+
+// readFile implements ReadFile and, if limit >= 0, ReadFileLimit.
+func readFile(name string, limit int64) ([]byte, error) {
 	f, err := Open(name)
 	if err != nil {
 		return nil, err
@@ -665,6 +995,9 @@ func ReadFile(name string) ([]byte, error) {
 	var size int
 	if info, err := f.Stat(); err == nil {
 		size64 := info.Size()
+		if limit >= 0 && size64 > limit {
+			return nil, &PathError{Op: "read", Path: name, Err: ErrFileTooLarge}
+		}
 		if int64(int(size64)) == size64 {
 			size = int(size64)
 		}
@@ -682,11 +1015,28 @@ func ReadFile(name string) ([]byte, error) {
 	data := make([]byte, 0, size)
 	for {
 		if len(data) >= cap(data) {
-			d := append(data[:cap(data)], 0)
-			data = d[:len(data)]
+			// The file is larger than it claimed, as files in /proc
+			// and pipes are, so grow the buffer geometrically.
+			n := 2 * cap(data)
+			if limit >= 0 && int64(n) > limit {
+				n = int(limit) + 1
+			}
+			d := make([]byte, len(data), n)
+			copy(d, data)
+			data = d
 		}
-		n, err := f.Read(data[len(data):cap(data)])
+		buf := data[len(data):cap(data)]
+		if limit >= 0 {
+			// Read at most one byte past the limit.
+			if rem := limit - int64(len(data)); rem < int64(len(buf)) {
+				buf = buf[:rem+1]
+			}
+		}
+		n, err := f.Read(buf)
 		data = data[:len(data)+n]
+		if limit >= 0 && int64(len(data)) > limit {
+			return nil, &PathError{Op: "read", Path: name, Err: ErrFileTooLarge}
+		}
 		if err != nil {
 			if err == io.EOF {
 				err = nil
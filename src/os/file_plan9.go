@@ -489,6 +489,10 @@ func tempDir() string {
 
 // Chdir changes the current working directory to the file,
 // which must be a directory.
+// Unlike the Chdir function, it changes to the directory that f refers
+// to even if its path has been renamed or replaced since f was opened,
+// so it is the race-free way to return to a directory. The file can be
+// a handle returned by OpenPath.
 // If there is an error, it will be of type *PathError.
 func (f *File) Chdir() error {
 	if err := f.checkValid("chdir"); err != nil {
@@ -497,6 +501,7 @@ func (f *File) Chdir() error {
 	if e := syscall.Fchdir(f.fd); e != nil {
 		return &PathError{Op: "chdir", Path: f.name, Err: e}
 	}
+	logChdir()
 	return nil
 }
 
@@ -557,3 +562,8 @@ func newRawConn(file *File) (*rawConn, error) {
 func ignoringEINTR(fn func() error) error {
 	return fn()
 }
+
+// umask does nothing: Plan 9 has no umask.
+func umask(mask int) int {
+	return 0
+}
@@ -190,6 +190,10 @@ func Chtimes(name string, atime time.Time, mtime time.Time) error {
 
 // Chdir changes the current working directory to the file,
 // which must be a directory.
+// Unlike the Chdir function, it changes to the directory that f refers
+// to even if its path has been renamed or replaced since f was opened,
+// so it is the race-free way to return to a directory. The file can be
+// a handle returned by OpenPath.
 // If there is an error, it will be of type *PathError.
 func (f *File) Chdir() error {
 	if err := f.checkValid("chdir"); err != nil {
@@ -198,6 +202,7 @@ func (f *File) Chdir() error {
 	if e := f.pfd.Fchdir(); e != nil {
 		return f.wrapErr("chdir", e)
 	}
+	logChdir()
 	return nil
 }
 
@@ -427,3 +427,7 @@ func newUnixDirent(parent, name string, typ FileMode) (DirEntry, error) {
 	ude.info = info
 	return ude, nil
 }
+
+func umask(mask int) int {
+	return syscall.Umask(mask)
+}
@@ -498,3 +498,8 @@ func Readlink(name string) (string, error) {
 	}
 	return s, nil
 }
+
+// umask does nothing: Windows has no umask.
+func umask(mask int) int {
+	return 0
+}
@@ -0,0 +1,29 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+import "errors"
+
+// errLockHeld is wrapped in the error returned by File.lock when wait
+// is false and another open file holds a conflicting lock.
+var errLockHeld = errors.New("file is locked")
+
+// lock places an advisory lock on the whole of f, shared or exclusive,
+// waiting for conflicting locks to be released if wait is true. The
+// lock is released by unlock or when f is closed.
+func (f *File) lock(exclusive, wait bool) error {
+	if err := f.checkValid("lock"); err != nil {
+		return err
+	}
+	return f.wrapErr("lock", f.lockFile(exclusive, wait))
+}
+
+// unlock releases a lock placed by lock.
+func (f *File) unlock() error {
+	if err := f.checkValid("unlock"); err != nil {
+		return err
+	}
+	return f.wrapErr("unlock", f.unlockFile())
+}
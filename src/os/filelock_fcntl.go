@@ -0,0 +1,52 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build aix || (solaris && !illumos)
+// +build aix solaris,!illumos
+
+package os
+
+import (
+	"runtime"
+	"syscall"
+)
+
+// These systems have no flock, so lock with fcntl instead. Unlike flock
+// locks, fcntl locks belong to the process, so they do not exclude other
+// files opened by the same process, and closing any descriptor for the
+// file releases them.
+
+func (f *File) lockFile(exclusive, wait bool) error {
+	typ := int16(syscall.F_RDLCK)
+	if exclusive {
+		typ = syscall.F_WRLCK
+	}
+	cmd := syscall.F_SETLK
+	if wait {
+		cmd = syscall.F_SETLKW
+	}
+	err := f.fcntlLock(cmd, typ)
+	if err == syscall.EAGAIN || err == syscall.EACCES {
+		return errLockHeld
+	}
+	return err
+}
+
+func (f *File) unlockFile() error {
+	return f.fcntlLock(syscall.F_SETLK, syscall.F_UNLCK)
+}
+
+func (f *File) fcntlLock(cmd int, typ int16) error {
+	lk := syscall.Flock_t{Type: typ, Whence: 0, Start: 0, Len: 0} // whole file
+	var e error
+	if err := f.pfd.RawControl(func(fd uintptr) {
+		e = ignoringEINTR(func() error {
+			return syscall.FcntlFlock(fd, cmd, &lk)
+		})
+	}); err != nil {
+		return err
+	}
+	runtime.KeepAlive(f)
+	return e
+}
@@ -0,0 +1,44 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build darwin || dragonfly || freebsd || illumos || linux || netbsd || openbsd
+// +build darwin dragonfly freebsd illumos linux netbsd openbsd
+
+package os
+
+import (
+	"runtime"
+	"syscall"
+)
+
+func (f *File) lockFile(exclusive, wait bool) error {
+	how := syscall.LOCK_SH
+	if exclusive {
+		how = syscall.LOCK_EX
+	}
+	if !wait {
+		how |= syscall.LOCK_NB
+	}
+	return f.flock(how)
+}
+
+func (f *File) unlockFile() error {
+	return f.flock(syscall.LOCK_UN)
+}
+
+func (f *File) flock(how int) error {
+	var e error
+	if err := f.pfd.RawControl(func(fd uintptr) {
+		e = ignoringEINTR(func() error {
+			return syscall.Flock(int(fd), how)
+		})
+	}); err != nil {
+		return err
+	}
+	runtime.KeepAlive(f)
+	if e == syscall.EWOULDBLOCK {
+		return errLockHeld
+	}
+	return e
+}
@@ -0,0 +1,16 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build (js && wasm) || plan9
+// +build js,wasm plan9
+
+package os
+
+func (f *File) lockFile(exclusive, wait bool) error {
+	return ErrUnsupported
+}
+
+func (f *File) unlockFile() error {
+	return ErrUnsupported
+}
@@ -0,0 +1,48 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+import (
+	"internal/syscall/windows"
+	"runtime"
+	"syscall"
+)
+
+// allBytes locks the whole file, however large.
+const allBytes = ^uint32(0)
+
+func (f *File) lockFile(exclusive, wait bool) error {
+	var flags uint32
+	if exclusive {
+		flags |= windows.LOCKFILE_EXCLUSIVE_LOCK
+	}
+	if !wait {
+		flags |= windows.LOCKFILE_FAIL_IMMEDIATELY
+	}
+	var e error
+	if err := f.pfd.RawControl(func(fd uintptr) {
+		ol := new(syscall.Overlapped)
+		e = windows.LockFileEx(syscall.Handle(fd), flags, 0, allBytes, allBytes, ol)
+	}); err != nil {
+		return err
+	}
+	runtime.KeepAlive(f)
+	if e == windows.ERROR_LOCK_VIOLATION {
+		return errLockHeld
+	}
+	return e
+}
+
+func (f *File) unlockFile() error {
+	var e error
+	if err := f.pfd.RawControl(func(fd uintptr) {
+		ol := new(syscall.Overlapped)
+		e = windows.UnlockFileEx(syscall.Handle(fd), 0, allBytes, allBytes, ol)
+	}); err != nil {
+		return err
+	}
+	runtime.KeepAlive(f)
+	return e
+}
@@ -0,0 +1,157 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+import (
+	"errors"
+	"sync"
+)
+
+// A FileTx applies a series of file system changes that can be
+// reversed as a group. Each change takes effect when its method is
+// called, but nothing is irrecoverably lost until Commit: removed files
+// and files replaced by Rename are moved to a staging directory beside
+// them rather than deleted. Rollback undoes the changes in reverse
+// order.
+//
+// A FileTx is not isolated from other changes to the file system; if
+// other processes change the same files, Rollback may fail. It is safe
+// for concurrent use.
+type FileTx struct {
+	mu     sync.Mutex
+	undo   []func() error // in the order the changes were made
+	staged []string       // staging directories to delete on Commit
+	done   bool
+}
+
+var errTxDone = errors.New("transaction already committed or rolled back")
+
+// NewFileTx returns a new, empty FileTx.
+func NewFileTx() *FileTx {
+	return new(FileTx)
+}
+
+// Rename renames oldpath to newpath, as Rename does. If newpath already
+// exists, it is staged so that Rollback can restore it.
+func (tx *FileTx) Rename(oldpath, newpath string) error {
+	tx.mu.Lock()
+	defer tx.mu.Unlock()
+	if tx.done {
+		return &LinkError{Op: "rename", Old: oldpath, New: newpath, Err: errTxDone}
+	}
+	var restore func() error
+	if _, err := Lstat(newpath); err == nil {
+		if restore, err = tx.stage(newpath); err != nil {
+			return err
+		}
+	}
+	if err := Rename(oldpath, newpath); err != nil {
+		if restore != nil {
+			restore()
+		}
+		return err
+	}
+	tx.undo = append(tx.undo, func() error {
+		if err := Rename(newpath, oldpath); err != nil {
+			return err
+		}
+		if restore != nil {
+			return restore()
+		}
+		return nil
+	})
+	return nil
+}
+
+// Remove removes the named file or directory, including any contents,
+// by moving it to a staging directory. It is deleted by Commit.
+func (tx *FileTx) Remove(name string) error {
+	tx.mu.Lock()
+	defer tx.mu.Unlock()
+	if tx.done {
+		return &PathError{Op: "remove", Path: name, Err: errTxDone}
+	}
+	restore, err := tx.stage(name)
+	if err != nil {
+		return err
+	}
+	tx.undo = append(tx.undo, restore)
+	return nil
+}
+
+// Mkdir creates a new directory, as Mkdir does.
+// Rollback removes it.
+func (tx *FileTx) Mkdir(name string, perm FileMode) error {
+	tx.mu.Lock()
+	defer tx.mu.Unlock()
+	if tx.done {
+		return &PathError{Op: "mkdir", Path: name, Err: errTxDone}
+	}
+	if err := Mkdir(name, perm); err != nil {
+		return err
+	}
+	tx.undo = append(tx.undo, func() error { return Remove(name) })
+	return nil
+}
+
+// Commit finalizes the changes, deleting the files staged by Remove and
+// Rename. It returns the first error encountered while deleting them.
+func (tx *FileTx) Commit() error {
+	tx.mu.Lock()
+	defer tx.mu.Unlock()
+	if tx.done {
+		return errTxDone
+	}
+	tx.done = true
+	var err error
+	for _, dir := range tx.staged {
+		if err1 := RemoveAll(dir); err == nil {
+			err = err1
+		}
+	}
+	tx.undo, tx.staged = nil, nil
+	return err
+}
+
+// Rollback undoes the changes in reverse order, restoring staged files.
+// It attempts every undo step and returns the first error encountered.
+func (tx *FileTx) Rollback() error {
+	tx.mu.Lock()
+	defer tx.mu.Unlock()
+	if tx.done {
+		return errTxDone
+	}
+	tx.done = true
+	var err error
+	for i := len(tx.undo) - 1; i >= 0; i-- {
+		if err1 := tx.undo[i](); err == nil {
+			err = err1
+		}
+	}
+	tx.undo, tx.staged = nil, nil
+	return err
+}
+
+// stage moves name into a new staging directory in the same directory,
+// so that the move never crosses file systems, and returns a function
+// that moves it back.
+func (tx *FileTx) stage(name string) (restore func() error, err error) {
+	dir, err := MkdirTemp(dirname(name), ".filetx-")
+	if err != nil {
+		return nil, err
+	}
+	staged := joinPath(dir, "staged")
+	if err := Rename(name, staged); err != nil {
+		Remove(dir)
+		return nil, err
+	}
+	tx.staged = append(tx.staged, dir)
+	return func() error {
+		if err := Rename(staged, name); err != nil {
+			return err
+		}
+		return Remove(dir)
+	}, nil
+}
@@ -124,3 +124,49 @@ func Getwd() (dir string, err error) {
 
 	return dir, nil
 }
+
+// GetwdLogical returns the logical name of the current directory: the
+// path by which the user reached it, including any symbolic links along
+// the way, as maintained in $PWD by Unix shells. Getwd, by contrast, may
+// return the physical path, with the symbolic links resolved.
+//
+// GetwdLogical uses $PWD only if it is a rooted path without "." or ".."
+// elements that names the current directory. If $PWD is unset, or is
+// stale because the process has changed directory since it was set,
+// GetwdLogical returns the result of Getwd.
+func GetwdLogical() (string, error) {
+	dir := Getenv("PWD")
+	if isLogicalWd(dir) {
+		dot, err := statNolog(".")
+		if err != nil {
+			return "", err
+		}
+		if d, err := statNolog(dir); err == nil && SameFile(dot, d) {
+			return dir, nil
+		}
+	}
+	return Getwd()
+}
+
+// isLogicalWd reports whether dir has the form of a logical
+// working directory: rooted, with no "." or ".." elements.
+func isLogicalWd(dir string) bool {
+	switch {
+	case dir == "":
+		return false
+	case IsPathSeparator(dir[0]):
+	case runtime.GOOS == "windows" && len(dir) >= 3 && dir[1] == ':' && IsPathSeparator(dir[2]):
+	default:
+		return false
+	}
+	start := 0
+	for i := 0; i <= len(dir); i++ {
+		if i == len(dir) || IsPathSeparator(dir[i]) {
+			if elem := dir[start:i]; elem == "." || elem == ".." {
+				return false
+			}
+			start = i + 1
+		}
+	}
+	return true
+}
@@ -0,0 +1,107 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+import "unsafe"
+
+// An InotifyEvent is an event read from an inotify file.
+type InotifyEvent struct {
+	Wd     int    // watch descriptor returned by InotifyAddWatch
+	Mask   uint32 // the event and flags, such as syscall.IN_MODIFY
+	Cookie uint32 // relates the two halves of a rename, or 0
+	Name   string // name of the file, for an event in a watched directory
+}
+
+// inotifyEventSize is the size of struct inotify_event, which is
+// followed by the NUL-padded name.
+const inotifyEventSize = 16
+
+// InotifyInit returns a new inotify file, from which events for the
+// paths added with InotifyAddWatch are read with ReadInotifyEvents.
+// Unlike higher-level file watching, it gives direct control over the
+// event mask and exposes the cookies that pair the IN_MOVED_FROM and
+// IN_MOVED_TO events of a rename. The file works with the runtime
+// poller, so reads honor SetReadDeadline.
+//
+// InotifyInit is only supported on Linux. On other systems it returns
+// an error wrapping ErrUnsupported.
+func InotifyInit() (*File, error) {
+	return inotifyInit()
+}
+
+// InotifyAddWatch adds path to the inotify file f, or changes the
+// events watched for if path is already watched, and returns the watch
+// descriptor that identifies it in events. The mask is a combination
+// of the syscall.IN_* constants.
+func (f *File) InotifyAddWatch(path string, mask uint32) (wd int, err error) {
+	if err := f.checkValid("inotify_add_watch"); err != nil {
+		return -1, err
+	}
+	return f.inotifyAddWatch(path, mask)
+}
+
+// InotifyRmWatch removes the watch wd from the inotify file f. An
+// IN_IGNORED event is generated for it.
+func (f *File) InotifyRmWatch(wd int) error {
+	if err := f.checkValid("inotify_rm_watch"); err != nil {
+		return err
+	}
+	return f.inotifyRmWatch(wd)
+}
+
+// ReadInotifyEvents waits for events on the inotify file f and returns
+// those that are available.
+func (f *File) ReadInotifyEvents() ([]InotifyEvent, error) {
+	if err := f.checkValid("read"); err != nil {
+		return nil, err
+	}
+	// Large enough for many events, and for at least one event
+	// with a name of the maximum length of 255 bytes.
+	buf := make([]byte, 4096)
+	n, err := f.read(buf)
+	if err != nil {
+		return nil, f.wrapErr("read", err)
+	}
+	events, used := ParseInotifyEvents(buf[:n])
+	if used != n {
+		// The kernel returns only whole events.
+		return events, f.wrapErr("read", ErrInvalid)
+	}
+	return events, nil
+}
+
+// ParseInotifyEvents parses the events in b, data read from an inotify
+// file, and returns them with the number of bytes they occupied. If b
+// ends with an incomplete event, that event is not returned, and the
+// caller can complete it with further data.
+func ParseInotifyEvents(b []byte) (events []InotifyEvent, n int) {
+	for len(b)-n >= inotifyEventSize {
+		var hdr struct {
+			wd     int32
+			mask   uint32
+			cookie uint32
+			len    uint32
+		}
+		copy((*[inotifyEventSize]byte)(unsafe.Pointer(&hdr))[:], b[n:])
+		if uint64(hdr.len) > uint64(len(b)-n-inotifyEventSize) {
+			break
+		}
+		name := b[n+inotifyEventSize : n+inotifyEventSize+int(hdr.len)]
+		for i, c := range name {
+			if c == 0 {
+				name = name[:i]
+				break
+			}
+		}
+		events = append(events, InotifyEvent{
+			Wd:     int(hdr.wd),
+			Mask:   hdr.mask,
+			Cookie: hdr.cookie,
+			Name:   string(name),
+		})
+		n += inotifyEventSize + int(hdr.len)
+	}
+	return events, n
+}
@@ -0,0 +1,47 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+import (
+	"runtime"
+	"syscall"
+)
+
+func inotifyInit() (*File, error) {
+	fd, err := syscall.InotifyInit1(syscall.IN_NONBLOCK | syscall.IN_CLOEXEC)
+	if err != nil {
+		return nil, NewSyscallError("inotify_init1", err)
+	}
+	return newFile(uintptr(fd), "inotify", kindNonBlock), nil
+}
+
+func (f *File) inotifyAddWatch(path string, mask uint32) (int, error) {
+	var wd int
+	var e error
+	if err := f.pfd.RawControl(func(fd uintptr) {
+		wd, e = syscall.InotifyAddWatch(int(fd), path, mask)
+	}); err != nil {
+		return -1, f.wrapErr("inotify_add_watch", err)
+	}
+	runtime.KeepAlive(f)
+	if e != nil {
+		return -1, &PathError{Op: "inotify_add_watch", Path: path, Err: e}
+	}
+	return wd, nil
+}
+
+func (f *File) inotifyRmWatch(wd int) error {
+	var e error
+	if err := f.pfd.RawControl(func(fd uintptr) {
+		_, e = syscall.InotifyRmWatch(int(fd), uint32(wd))
+	}); err != nil {
+		return f.wrapErr("inotify_rm_watch", err)
+	}
+	runtime.KeepAlive(f)
+	if e != nil {
+		return f.wrapErr("inotify_rm_watch", e)
+	}
+	return nil
+}
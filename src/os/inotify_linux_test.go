@@ -0,0 +1,106 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os_test
+
+import (
+	. "os"
+	"path/filepath"
+	"reflect"
+	"syscall"
+	"testing"
+	"time"
+	"unsafe"
+)
+
+func TestInotify(t *testing.T) {
+	f, err := InotifyInit()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	dir := t.TempDir()
+	wd, err := f.InotifyAddWatch(dir, syscall.IN_CREATE|syscall.IN_MOVE)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteFile(filepath.Join(dir, "a"), nil, 0666); err != nil {
+		t.Fatal(err)
+	}
+	if err := Rename(filepath.Join(dir, "a"), filepath.Join(dir, "a-much-longer-name")); err != nil {
+		t.Fatal(err)
+	}
+
+	var events []InotifyEvent
+	f.SetReadDeadline(time.Now().Add(10 * time.Second))
+	for len(events) < 3 {
+		evs, err := f.ReadInotifyEvents()
+		if err != nil {
+			t.Fatal(err)
+		}
+		events = append(events, evs...)
+	}
+	for i, want := range []struct {
+		mask uint32
+		name string
+	}{
+		{syscall.IN_CREATE, "a"},
+		{syscall.IN_MOVED_FROM, "a"},
+		{syscall.IN_MOVED_TO, "a-much-longer-name"},
+	} {
+		ev := events[i]
+		if ev.Wd != wd || ev.Mask != want.mask || ev.Name != want.name {
+			t.Errorf("event %d = %+v, want mask %#x and name %q", i, ev, want.mask, want.name)
+		}
+	}
+	if events[1].Cookie == 0 || events[1].Cookie != events[2].Cookie {
+		t.Errorf("rename cookies %d and %d are not the same nonzero value", events[1].Cookie, events[2].Cookie)
+	}
+
+	if err := f.InotifyRmWatch(wd); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.InotifyRmWatch(wd); err == nil {
+		t.Error("second InotifyRmWatch succeeded")
+	}
+}
+
+func TestParseInotifyEvents(t *testing.T) {
+	// Two events as the kernel writes them, the second with its
+	// name padded with NULs.
+	var b []byte
+	for _, ev := range []struct {
+		hdr  [4]uint32 // wd, mask, cookie, len
+		name string
+	}{
+		{[4]uint32{1, syscall.IN_CREATE, 0, 0}, ""},
+		{[4]uint32{2, syscall.IN_MOVED_TO, 7, 16}, "file"},
+	} {
+		b = append(b, (*[16]byte)(unsafe.Pointer(&ev.hdr))[:]...)
+		name := make([]byte, ev.hdr[3])
+		copy(name, ev.name)
+		b = append(b, name...)
+	}
+	all := []InotifyEvent{
+		{Wd: 1, Mask: syscall.IN_CREATE},
+		{Wd: 2, Mask: syscall.IN_MOVED_TO, Cookie: 7, Name: "file"},
+	}
+
+	// Only whole events are parsed from a truncated buffer.
+	for cut := 0; cut <= len(b); cut++ {
+		var want []InotifyEvent
+		wantN := 0
+		if cut >= 16 {
+			want, wantN = all[:1], 16
+		}
+		if cut == len(b) {
+			want, wantN = all, len(b)
+		}
+		events, n := ParseInotifyEvents(b[:cut])
+		if n != wantN || !reflect.DeepEqual(events, want) {
+			t.Errorf("ParseInotifyEvents(b[:%d]) = %+v, %d; want %+v, %d", cut, events, n, want, wantN)
+		}
+	}
+}
@@ -0,0 +1,20 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !linux
+// +build !linux
+
+package os
+
+func inotifyInit() (*File, error) {
+	return nil, NewSyscallError("inotify_init1", ErrUnsupported)
+}
+
+func (f *File) inotifyAddWatch(path string, mask uint32) (int, error) {
+	return -1, &PathError{Op: "inotify_add_watch", Path: path, Err: ErrUnsupported}
+}
+
+func (f *File) inotifyRmWatch(wd int) error {
+	return f.wrapErr("inotify_rm_watch", ErrUnsupported)
+}
@@ -0,0 +1,85 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+import (
+	"errors"
+	"runtime"
+	"sync"
+)
+
+// AcquireSingleInstance ensures that only one process at a time runs
+// under the given name. It opens a lock file, creating it if necessary,
+// and takes an exclusive lock on it. If another process holds the lock,
+// AcquireSingleInstance returns ErrAlreadyRunning, wrapped in a
+// *PathError.
+//
+// If name contains a path separator, it is the path of the lock file.
+// Otherwise it is an application name, and the lock file is name+".lock"
+// in $XDG_RUNTIME_DIR if that is set on Unix systems, or else in the
+// directory returned by TempDir.
+//
+// The release function releases the lock; calls after the first do
+// nothing. The lock is also released when the process exits, however
+// it exits, so a crashed instance never blocks later ones. The lock
+// file is left in place, since removing it would let two processes
+// lock different files of the same name.
+//
+// On systems without file locking, AcquireSingleInstance returns an
+// error wrapping ErrUnsupported.
+func AcquireSingleInstance(name string) (release func() error, err error) {
+	if name == "" {
+		return nil, &PathError{Op: "acquiresingleinstance", Path: name, Err: ErrInvalid}
+	}
+	path := name
+	if !hasPathSeparator(name) {
+		path = joinPath(instanceDir(), name+".lock")
+	}
+	f, err := OpenFile(path, O_RDWR|O_CREATE, 0666)
+	if err != nil {
+		return nil, err
+	}
+	if err := f.lock(true, false); err != nil {
+		f.Close()
+		if errors.Is(err, errLockHeld) {
+			return nil, &PathError{Op: "acquiresingleinstance", Path: path, Err: ErrAlreadyRunning}
+		}
+		return nil, err
+	}
+	var once sync.Once
+	release = func() (err error) {
+		once.Do(func() {
+			err = f.unlock()
+			if cerr := f.Close(); err == nil {
+				err = cerr
+			}
+		})
+		return err
+	}
+	return release, nil
+}
+
+// instanceDir returns the directory holding lock files for
+// AcquireSingleInstance calls given an application name.
+func instanceDir() string {
+	switch runtime.GOOS {
+	case "windows", "plan9", "js":
+	default:
+		if dir := Getenv("XDG_RUNTIME_DIR"); dir != "" {
+			return dir
+		}
+	}
+	return TempDir()
+}
+
+// hasPathSeparator reports whether s contains a path separator.
+func hasPathSeparator(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if IsPathSeparator(s[i]) {
+			return true
+		}
+	}
+	return false
+}
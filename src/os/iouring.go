@@ -0,0 +1,118 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+import "errors"
+
+// An IOUring is a Linux io_uring instance, which performs batches of
+// file operations with a single system call. Operations are queued with
+// ReadAt, WriteAt and Fsync, passed to the kernel together with Submit,
+// and their results are collected, in any order, with Wait.
+//
+// Each operation carries a user data value chosen by the caller, which
+// is returned in its completion to identify it. The buffer of a read or
+// write must not be used, and the file must not be closed, until the
+// operation completes.
+//
+// The methods of an IOUring can be called concurrently. Each completion
+// is returned by exactly one call to Wait.
+type IOUring struct {
+	*ioUring // os-specific
+}
+
+// An IOUringCompletion is the result of an operation performed by an
+// IOUring.
+type IOUringCompletion struct {
+	UserData uint64 // the value passed when the operation was queued
+	N        int    // the number of bytes read or written
+	Err      error  // the error, if any
+}
+
+// NewIOUring creates an IOUring whose submission queue holds entries
+// operations, rounded up to a power of two. At most twice that many
+// operations can be queued or in flight at once. The ring file
+// descriptor is registered with the runtime poller, so Wait blocks
+// only the calling goroutine.
+//
+// NewIOUring is only supported on Linux 5.1 and later. On other systems
+// it returns an error wrapping ErrUnsupported.
+func NewIOUring(entries int) (*IOUring, error) {
+	if entries <= 0 {
+		return nil, NewSyscallError("io_uring_setup", ErrInvalid)
+	}
+	return newIOUring(entries)
+}
+
+// ReadAt queues a read of len(b) bytes from f starting at offset off.
+// Unlike File.ReadAt, the read may return fewer bytes than requested
+// without an error; it returns no bytes and the error io.EOF at the end
+// of the file. If the ring is full, ReadAt returns an error, and the
+// caller should Submit the queued operations and Wait for some to
+// complete before trying again.
+func (r *IOUring) ReadAt(f *File, b []byte, off int64, userData uint64) error {
+	if err := f.checkValid("read"); err != nil {
+		return err
+	}
+	if off < 0 {
+		return &PathError{Op: "readat", Path: f.name, Err: errors.New("negative offset")}
+	}
+	return r.queue(ioUringRead, f, b, off, userData)
+}
+
+// WriteAt queues a write of b to f starting at offset off. The write may
+// complete having written fewer than len(b) bytes. If the ring is full,
+// WriteAt returns an error, as for ReadAt.
+func (r *IOUring) WriteAt(f *File, b []byte, off int64, userData uint64) error {
+	if err := f.checkValid("write"); err != nil {
+		return err
+	}
+	if off < 0 {
+		return &PathError{Op: "writeat", Path: f.name, Err: errors.New("negative offset")}
+	}
+	return r.queue(ioUringWrite, f, b, off, userData)
+}
+
+// Fsync queues a commit of the contents of f to stable storage, as by
+// File.Sync. It is not ordered with respect to other queued operations,
+// so to sync the data of a write the caller must queue the Fsync after
+// the write completes. If the ring is full, Fsync returns an error, as
+// for ReadAt.
+func (r *IOUring) Fsync(f *File, userData uint64) error {
+	if err := f.checkValid("sync"); err != nil {
+		return err
+	}
+	return r.queue(ioUringFsync, f, nil, 0, userData)
+}
+
+// Submit passes the queued operations to the kernel, which starts
+// performing them, and returns how many it passed.
+func (r *IOUring) Submit() (int, error) {
+	return r.submit()
+}
+
+// Wait waits until at least one submitted operation has completed, and
+// stores the results of up to len(completions) completed operations in
+// completions, returning how many it stored. It does not submit queued
+// operations. If no operations are in flight, including because a
+// concurrent call to Wait collected the last completion, Wait returns 0.
+// After Close, Wait returns the results of operations that completed
+// during Close and were not yet collected, and then the error ErrClosed.
+func (r *IOUring) Wait(completions []IOUringCompletion) (int, error) {
+	return r.wait(completions)
+}
+
+// Close submits any queued operations, waits for all operations to
+// complete, and releases the ring. The results of operations that are
+// not collected by a concurrent call to Wait are kept for later calls.
+func (r *IOUring) Close() error {
+	return r.close()
+}
+
+// Kinds of operations performed by an IOUring.
+const (
+	ioUringRead = iota
+	ioUringWrite
+	ioUringFsync
+)
@@ -0,0 +1,345 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+import (
+	"errors"
+	"internal/syscall/unix"
+	"io"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"unsafe"
+)
+
+var errIOUringFull = errors.New("io_uring is full")
+
+// Upper bounds on the number of submission and completion queue
+// entries, as enforced by the kernel.
+const (
+	maxIOUringSQEntries = 1 << 15
+	maxIOUringCQEntries = 1 << 16
+)
+
+type ioUring struct {
+	file *File // the ring, registered with the poller
+
+	sqRing, cqRing, sqeMem []byte // mappings shared with the kernel
+
+	mu      sync.Mutex
+	sqHead  *uint32
+	sqTail  *uint32
+	sqMask  uint32
+	sqArray []uint32
+	sqes    []unix.IoUringSqe
+	cqHead  *uint32
+	cqTail  *uint32
+	cqMask  uint32
+	cqes    []unix.IoUringCqe
+
+	queued    uint32                // entries queued but not yet submitted
+	submitted int32                 // operations in flight in the kernel; read atomically by waitReady
+	inflight  map[uint64]*ioUringOp // queued and submitted operations, by id
+	nextID    uint64
+	ready     []IOUringCompletion // reaped by close but not yet returned by wait
+
+	// Only one goroutine at a time waits for the ring to become
+	// readable; the others wait on cond until it is done.
+	cond    sync.Cond
+	polling bool
+
+	closing bool // close has been called; no more operations are accepted
+	closed  bool // the ring has been released
+}
+
+// An ioUringOp is a queued or submitted operation. It keeps the buffer
+// and file alive until the operation completes.
+type ioUringOp struct {
+	kind     int
+	f        *File
+	buf      []byte
+	iov      syscall.Iovec
+	userData uint64
+}
+
+func newIOUring(entries int) (*IOUring, error) {
+	if entries > maxIOUringSQEntries {
+		entries = maxIOUringSQEntries
+	}
+	var p unix.IoUringParams
+	fd, err := unix.IoUringSetup(uint32(entries), &p)
+	if err != nil {
+		return nil, NewSyscallError("io_uring_setup", err)
+	}
+	// The ring is always close-on-exec.
+	if err := syscall.SetNonblock(fd, true); err != nil {
+		syscall.Close(fd)
+		return nil, NewSyscallError("setnonblock", err)
+	}
+	r := &ioUring{
+		file:     newFile(uintptr(fd), "io_uring", kindNonBlock),
+		inflight: make(map[uint64]*ioUringOp),
+	}
+	r.cond.L = &r.mu
+	if err := r.mmap(fd, &p); err != nil {
+		r.unmap()
+		r.file.Close()
+		return nil, err
+	}
+	return &IOUring{r}, nil
+}
+
+// mmap maps the submission and completion queues of the ring.
+func (r *ioUring) mmap(fd int, p *unix.IoUringParams) error {
+	mmap := func(off int64, size uint32) ([]byte, error) {
+		b, err := syscall.Mmap(fd, off, int(size), syscall.PROT_READ|syscall.PROT_WRITE, syscall.MAP_SHARED|syscall.MAP_POPULATE)
+		if err != nil {
+			return nil, NewSyscallError("mmap", err)
+		}
+		return b, nil
+	}
+	var err error
+	if r.sqRing, err = mmap(unix.IORING_OFF_SQ_RING, p.SqOff.Array+p.SqEntries*4); err != nil {
+		return err
+	}
+	if r.cqRing, err = mmap(unix.IORING_OFF_CQ_RING, p.CqOff.Cqes+p.CqEntries*uint32(unsafe.Sizeof(unix.IoUringCqe{}))); err != nil {
+		return err
+	}
+	if r.sqeMem, err = mmap(unix.IORING_OFF_SQES, p.SqEntries*uint32(unsafe.Sizeof(unix.IoUringSqe{}))); err != nil {
+		return err
+	}
+
+	r.sqHead = (*uint32)(unsafe.Pointer(&r.sqRing[p.SqOff.Head]))
+	r.sqTail = (*uint32)(unsafe.Pointer(&r.sqRing[p.SqOff.Tail]))
+	r.sqMask = *(*uint32)(unsafe.Pointer(&r.sqRing[p.SqOff.RingMask]))
+	r.sqArray = (*[maxIOUringSQEntries]uint32)(unsafe.Pointer(&r.sqRing[p.SqOff.Array]))[:p.SqEntries:p.SqEntries]
+	r.sqes = (*[maxIOUringSQEntries]unix.IoUringSqe)(unsafe.Pointer(&r.sqeMem[0]))[:p.SqEntries:p.SqEntries]
+	r.cqHead = (*uint32)(unsafe.Pointer(&r.cqRing[p.CqOff.Head]))
+	r.cqTail = (*uint32)(unsafe.Pointer(&r.cqRing[p.CqOff.Tail]))
+	r.cqMask = *(*uint32)(unsafe.Pointer(&r.cqRing[p.CqOff.RingMask]))
+	r.cqes = (*[maxIOUringCQEntries]unix.IoUringCqe)(unsafe.Pointer(&r.cqRing[p.CqOff.Cqes]))[:p.CqEntries:p.CqEntries]
+	return nil
+}
+
+func (r *ioUring) unmap() {
+	for _, b := range [][]byte{r.sqRing, r.cqRing, r.sqeMem} {
+		if b != nil {
+			syscall.Munmap(b)
+		}
+	}
+	r.sqRing, r.cqRing, r.sqeMem = nil, nil, nil
+}
+
+func (r *ioUring) queue(kind int, f *File, b []byte, off int64, userData uint64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closing {
+		return ErrClosed
+	}
+	// The kernel consumes entries only when they are submitted, so the
+	// submission queue is full when queued reaches its size. Limiting
+	// the operations in flight to the size of the completion queue
+	// ensures that no completion is dropped.
+	if r.queued == uint32(len(r.sqes)) || len(r.inflight) == len(r.cqes) {
+		return errIOUringFull
+	}
+
+	op := &ioUringOp{kind: kind, f: f, buf: b, userData: userData}
+	sqe := unix.IoUringSqe{UserData: r.nextID}
+	switch kind {
+	case ioUringRead, ioUringWrite:
+		sqe.Opcode = unix.IORING_OP_READV
+		if kind == ioUringWrite {
+			sqe.Opcode = unix.IORING_OP_WRITEV
+		}
+		if len(b) > 0 {
+			op.iov.Base = &b[0]
+			op.iov.SetLen(len(b))
+		}
+		sqe.Addr = uint64(uintptr(unsafe.Pointer(&op.iov)))
+		sqe.Len = 1
+		sqe.Off = uint64(off)
+	case ioUringFsync:
+		sqe.Opcode = unix.IORING_OP_FSYNC
+	}
+	// The descriptor is used when the operation is submitted, or later
+	// by the kernel, so it is not accessed through RawControl; the file
+	// must stay open until the operation completes.
+	sqe.Fd = int32(f.pfd.Sysfd)
+
+	tail := *r.sqTail
+	i := tail & r.sqMask
+	r.sqes[i] = sqe
+	r.sqArray[i] = i
+	atomic.StoreUint32(r.sqTail, tail+1)
+	r.queued++
+	r.inflight[r.nextID] = op
+	r.nextID++
+	return nil
+}
+
+func (r *ioUring) submit() (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closing {
+		return 0, ErrClosed
+	}
+	return r.submitLocked()
+}
+
+func (r *ioUring) submitLocked() (int, error) {
+	submitted := 0
+	for r.queued > 0 {
+		var n int
+		var e error
+		if err := r.file.pfd.RawControl(func(fd uintptr) {
+			n, e = unix.IoUringEnter(int(fd), r.queued, 0, 0)
+		}); err != nil {
+			return submitted, r.file.wrapErr("io_uring_enter", err)
+		}
+		runtime.KeepAlive(r.file)
+		if e == syscall.EINTR {
+			continue
+		}
+		if e != nil {
+			return submitted, r.file.wrapErr("io_uring_enter", e)
+		}
+		r.queued -= uint32(n)
+		atomic.AddInt32(&r.submitted, int32(n))
+		submitted += n
+	}
+	return submitted, nil
+}
+
+func (r *ioUring) wait(completions []IOUringCompletion) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for {
+		n := r.takeReadyLocked(completions)
+		if !r.closed {
+			n += r.reapLocked(completions[n:])
+		}
+		if n > 0 || len(completions) == 0 {
+			return n, nil
+		}
+		if r.closed {
+			return 0, ErrClosed
+		}
+		if atomic.LoadInt32(&r.submitted) == 0 {
+			return 0, nil
+		}
+		if err := r.pollLocked(); err != nil && !r.closed {
+			return 0, err
+		}
+	}
+}
+
+// pollLocked waits until the completion queue is not empty or no
+// operations are in flight, or, if another goroutine is already waiting
+// for that, until it is done. It unlocks r.mu while it waits.
+func (r *ioUring) pollLocked() error {
+	if r.polling {
+		r.cond.Wait()
+		return nil
+	}
+	r.polling = true
+	r.mu.Unlock()
+	err := r.waitReady()
+	r.mu.Lock()
+	r.polling = false
+	r.cond.Broadcast()
+	return err
+}
+
+// waitReady waits until the completion queue is not empty, which is
+// when the ring is reported as readable, or until no operations are in
+// flight, because another goroutine reaped the last completion.
+func (r *ioUring) waitReady() error {
+	err := r.file.pfd.RawRead(func(uintptr) bool {
+		return atomic.LoadUint32(r.cqTail) != atomic.LoadUint32(r.cqHead) ||
+			atomic.LoadInt32(&r.submitted) == 0
+	})
+	return r.file.wrapErr("io_uring_enter", err)
+}
+
+// takeReadyLocked moves completions reaped by close to completions.
+func (r *ioUring) takeReadyLocked(completions []IOUringCompletion) int {
+	n := copy(completions, r.ready)
+	r.ready = r.ready[n:]
+	if len(r.ready) == 0 {
+		r.ready = nil
+	}
+	return n
+}
+
+// reapLocked stores completed operations in completions and removes
+// them from the completion queue.
+func (r *ioUring) reapLocked(completions []IOUringCompletion) int {
+	head := *r.cqHead
+	tail := atomic.LoadUint32(r.cqTail)
+	n := 0
+	for ; head != tail && n < len(completions); head++ {
+		cqe := r.cqes[head&r.cqMask]
+		op := r.inflight[cqe.UserData]
+		delete(r.inflight, cqe.UserData)
+		c := IOUringCompletion{UserData: op.userData}
+		switch {
+		case cqe.Res < 0:
+			c.Err = op.f.wrapErr(ioUringOpNames[op.kind], syscall.Errno(-cqe.Res))
+		case op.kind == ioUringRead && cqe.Res == 0 && len(op.buf) > 0:
+			c.Err = io.EOF
+		default:
+			c.N = int(cqe.Res)
+		}
+		completions[n] = c
+		n++
+	}
+	atomic.StoreUint32(r.cqHead, head)
+	atomic.AddInt32(&r.submitted, -int32(n))
+	return n
+}
+
+var ioUringOpNames = [...]string{
+	ioUringRead:  "read",
+	ioUringWrite: "write",
+	ioUringFsync: "sync",
+}
+
+func (r *ioUring) close() error {
+	r.mu.Lock()
+	if r.closing {
+		r.mu.Unlock()
+		return ErrClosed
+	}
+	r.closing = true
+	_, err := r.submitLocked()
+	// Completions are kept for concurrent and later calls to wait.
+	// Operations that could not be submitted are never performed.
+	var buf [64]IOUringCompletion
+	for atomic.LoadInt32(&r.submitted) > 0 {
+		if n := r.reapLocked(buf[:]); n > 0 {
+			r.ready = append(r.ready, buf[:n]...)
+			continue
+		}
+		if perr := r.pollLocked(); perr != nil {
+			// Operations may still be in flight, so the ring is
+			// left open.
+			r.mu.Unlock()
+			return perr
+		}
+	}
+	r.closed = true
+	r.cond.Broadcast()
+	r.mu.Unlock()
+
+	// Closing the ring file waits for any goroutine still in waitReady
+	// to return, after which the mappings are no longer used.
+	if cerr := r.file.Close(); err == nil {
+		err = cerr
+	}
+	r.unmap()
+	return err
+}
@@ -0,0 +1,30 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !linux
+// +build !linux
+
+package os
+
+type ioUring struct{}
+
+func newIOUring(entries int) (*IOUring, error) {
+	return nil, NewSyscallError("io_uring_setup", ErrUnsupported)
+}
+
+func (r *ioUring) queue(kind int, f *File, b []byte, off int64, userData uint64) error {
+	return ErrUnsupported
+}
+
+func (r *ioUring) submit() (int, error) {
+	return 0, ErrUnsupported
+}
+
+func (r *ioUring) wait(completions []IOUringCompletion) (int, error) {
+	return 0, ErrUnsupported
+}
+
+func (r *ioUring) close() error {
+	return ErrUnsupported
+}
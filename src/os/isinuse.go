@@ -0,0 +1,22 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+// IsInUse reports whether the named file is currently open in any
+// process, including the calling one.
+//
+// On Linux, IsInUse looks through the open file descriptors listed in
+// /proc for one referring to the same file, comparing device and inode
+// numbers rather than paths. Processes whose descriptors the caller is
+// not permitted to see are skipped, so an unprivileged caller may not
+// find every use. On Windows, IsInUse asks the Restart Manager. On
+// other systems it returns an error wrapping ErrUnsupported.
+//
+// The result is only a hint: the file may be opened or closed at any
+// moment after IsInUse looks, so it must not be relied on to make a
+// destructive operation safe.
+func IsInUse(name string) (bool, error) {
+	return isInUse(name)
+}
@@ -0,0 +1,41 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+func isInUse(name string) (bool, error) {
+	fi, err := Stat(name)
+	if err != nil {
+		return false, err
+	}
+	d, err := Open("/proc")
+	if err != nil {
+		return false, &PathError{Op: "isinuse", Path: name, Err: ErrUnsupported}
+	}
+	defer d.Close()
+	pids, err := d.Readdirnames(-1)
+	if err != nil {
+		return false, &PathError{Op: "isinuse", Path: name, Err: err}
+	}
+	for _, pid := range pids {
+		if !isDigits(pid) {
+			continue
+		}
+		fdDir := "/proc/" + pid + "/fd/"
+		f, err := Open(fdDir)
+		if err != nil {
+			// The process has exited, or belongs to
+			// another user and we may not look.
+			continue
+		}
+		fds, _ := f.Readdirnames(-1)
+		f.Close()
+		for _, fd := range fds {
+			if fdi, err := Stat(fdDir + fd); err == nil && SameFile(fi, fdi) {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
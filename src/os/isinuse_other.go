@@ -0,0 +1,12 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !linux && !windows
+// +build !linux,!windows
+
+package os
+
+func isInUse(name string) (bool, error) {
+	return false, &PathError{Op: "isinuse", Path: name, Err: ErrUnsupported}
+}
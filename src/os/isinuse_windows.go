@@ -0,0 +1,44 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+import (
+	"internal/syscall/windows"
+	"syscall"
+)
+
+func isInUse(name string) (bool, error) {
+	if _, err := Stat(name); err != nil {
+		return false, err
+	}
+	// The Restart Manager needs a full path.
+	path, err := syscall.FullPath(name)
+	if err != nil {
+		return false, &PathError{Op: "FullPath", Path: name, Err: err}
+	}
+	p, err := syscall.UTF16PtrFromString(path)
+	if err != nil {
+		return false, &PathError{Op: "isinuse", Path: name, Err: err}
+	}
+
+	var session uint32
+	var key [windows.CCH_RM_SESSION_KEY + 1]uint16
+	if err := windows.RmStartSession(&session, 0, &key[0]); err != nil {
+		return false, &PathError{Op: "RmStartSession", Path: name, Err: err}
+	}
+	defer windows.RmEndSession(session)
+	if err := windows.RmRegisterResources(session, 1, &p, 0, 0, 0, nil); err != nil {
+		return false, &PathError{Op: "RmRegisterResources", Path: name, Err: err}
+	}
+	// Ask only for the number of processes using the file:
+	// with no room for their details, RmGetList returns
+	// ERROR_MORE_DATA if there are any.
+	var needed, n, reasons uint32
+	err = windows.RmGetList(session, &needed, &n, 0, &reasons)
+	if err != nil && err != syscall.ERROR_MORE_DATA {
+		return false, &PathError{Op: "RmGetList", Path: name, Err: err}
+	}
+	return needed > 0, nil
+}
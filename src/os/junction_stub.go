@@ -0,0 +1,12 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !windows
+// +build !windows
+
+package os
+
+func junction(target, link string) error {
+	return &LinkError{"junction", target, link, ErrUnsupported}
+}
@@ -0,0 +1,101 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+import (
+	"errors"
+	"internal/syscall/windows"
+	"syscall"
+)
+
+func junction(target, link string) error {
+	fi, err := Stat(target)
+	if err != nil {
+		return &LinkError{"junction", target, link, underlyingError(err)}
+	}
+	if !fi.IsDir() {
+		return &LinkError{"junction", target, link, errors.New("target is not a directory")}
+	}
+	abs, err := syscall.FullPath(target)
+	if err != nil {
+		return &LinkError{"junction", target, link, err}
+	}
+	buf, err := mountPointReparseData(abs)
+	if err != nil {
+		return &LinkError{"junction", target, link, err}
+	}
+
+	if err := Mkdir(link, 0777); err != nil {
+		return &LinkError{"junction", target, link, underlyingError(err)}
+	}
+	if err := setReparsePoint(link, buf); err != nil {
+		Remove(link)
+		return &LinkError{"junction", target, link, err}
+	}
+	return nil
+}
+
+// mountPointReparseData returns a REPARSE_DATA_BUFFER describing a
+// mount point, or junction, that refers to the absolute path target.
+func mountPointReparseData(target string) ([]byte, error) {
+	// The substitute name is an NT path; the print name is
+	// what tools such as dir display.
+	subst, err := syscall.UTF16FromString(`\??\` + target)
+	if err != nil {
+		return nil, err
+	}
+	print, err := syscall.UTF16FromString(target)
+	if err != nil {
+		return nil, err
+	}
+	// Both strings are stored with their terminating NULs,
+	// which the lengths do not include.
+	substLen := 2 * (len(subst) - 1)
+	printLen := 2 * (len(print) - 1)
+	dataLen := 8 + 2*len(subst) + 2*len(print)
+	if 8+dataLen > syscall.MAXIMUM_REPARSE_DATA_BUFFER_SIZE {
+		return nil, errors.New("target path too long")
+	}
+
+	b := make([]byte, 0, 8+dataLen)
+	b = append32(b, windows.IO_REPARSE_TAG_MOUNT_POINT)
+	b = append16(b, uint16(dataLen))
+	b = append16(b, 0) // Reserved
+	b = append16(b, 0) // SubstituteNameOffset
+	b = append16(b, uint16(substLen))
+	b = append16(b, uint16(substLen+2)) // PrintNameOffset
+	b = append16(b, uint16(printLen))
+	for _, c := range subst {
+		b = append16(b, c)
+	}
+	for _, c := range print {
+		b = append16(b, c)
+	}
+	return b, nil
+}
+
+func append16(b []byte, v uint16) []byte {
+	return append(b, byte(v), byte(v>>8))
+}
+
+func append32(b []byte, v uint32) []byte {
+	return append(b, byte(v), byte(v>>8), byte(v>>16), byte(v>>24))
+}
+
+// setReparsePoint attaches the reparse data buf to the empty directory name.
+func setReparsePoint(name string, buf []byte) error {
+	p, err := syscall.UTF16PtrFromString(fixLongPath(name))
+	if err != nil {
+		return err
+	}
+	h, err := syscall.CreateFile(p, syscall.GENERIC_WRITE, 0, nil, syscall.OPEN_EXISTING,
+		syscall.FILE_FLAG_OPEN_REPARSE_POINT|syscall.FILE_FLAG_BACKUP_SEMANTICS, 0)
+	if err != nil {
+		return err
+	}
+	defer syscall.CloseHandle(h)
+	var n uint32
+	return syscall.DeviceIoControl(h, windows.FSCTL_SET_REPARSE_POINT, &buf[0], uint32(len(buf)), nil, 0, &n, nil)
+}
@@ -0,0 +1,20 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build darwin || dragonfly || freebsd || netbsd || openbsd
+// +build darwin dragonfly freebsd netbsd openbsd
+
+package os
+
+import "internal/syscall/unix"
+
+func lchmod(name string, mode FileMode) error {
+	e := ignoringEINTR(func() error {
+		return unix.Fchmodat(unix.AT_FDCWD, name, syscallMode(mode), unix.AT_SYMLINK_NOFOLLOW)
+	})
+	if e != nil {
+		return &PathError{Op: "lchmod", Path: name, Err: e}
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+import (
+	"internal/itoa"
+	"internal/syscall/unix"
+	"syscall"
+)
+
+// Not defined in package syscall on all architectures.
+const _O_PATH = 0x200000
+
+// lchmod emulates lchmod as glibc does, since Linux cannot change the
+// mode of a symbolic link. It opens name without following a final
+// symlink, so that checking the file type and changing the mode both
+// apply to the same file, and changes the mode through /proc.
+func lchmod(name string, mode FileMode) error {
+	var fd int
+	e := ignoringEINTR(func() (err error) {
+		fd, err = unix.Openat(unix.AT_FDCWD, name, _O_PATH|syscall.O_NOFOLLOW|syscall.O_CLOEXEC, 0)
+		return err
+	})
+	if e != nil {
+		return &PathError{Op: "lchmod", Path: name, Err: e}
+	}
+	defer syscall.Close(fd)
+
+	var st syscall.Stat_t
+	if e := syscall.Fstat(fd, &st); e != nil {
+		return &PathError{Op: "lchmod", Path: name, Err: e}
+	}
+	if st.Mode&syscall.S_IFMT == syscall.S_IFLNK {
+		return &PathError{Op: "lchmod", Path: name, Err: ErrUnsupported}
+	}
+	e = ignoringEINTR(func() error {
+		return syscall.Chmod("/proc/self/fd/"+itoa.Itoa(fd), syscallMode(mode))
+	})
+	if e != nil {
+		return &PathError{Op: "lchmod", Path: name, Err: e}
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build dragonfly || freebsd || netbsd || openbsd
+// +build dragonfly freebsd netbsd openbsd
+
+package os
+
+import (
+	"internal/syscall/unix"
+	"syscall"
+	"time"
+	_ "unsafe" // for linkname
+)
+
+// Implemented in the syscall package.
+//go:linkname utimensat syscall.utimensat
+func utimensat(dirfd int, path string, times *[2]syscall.Timespec, flag int) error
+
+func lchtimes(name string, atime, mtime time.Time) error {
+	// The value of UTIME_OMIT differs between these systems,
+	// so fill in unchanged times explicitly.
+	atime, mtime, err := lfillTimes(name, atime, mtime)
+	if err != nil {
+		return &PathError{Op: "lchtimes", Path: name, Err: err}
+	}
+	ts := [2]syscall.Timespec{
+		syscall.NsecToTimespec(atime.UnixNano()),
+		syscall.NsecToTimespec(mtime.UnixNano()),
+	}
+	if e := utimensat(unix.AT_FDCWD, name, &ts, unix.AT_SYMLINK_NOFOLLOW); e != nil {
+		return &PathError{Op: "lchtimes", Path: name, Err: e}
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+import (
+	"internal/syscall/unix"
+	"syscall"
+	"time"
+	"unsafe"
+)
+
+// Constants from <sys/attr.h>.
+const (
+	_ATTR_BIT_MAP_COUNT = 5
+	_ATTR_CMN_MODTIME   = 0x400
+	_ATTR_CMN_ACCTIME   = 0x1000
+	_FSOPT_NOFOLLOW     = 0x1
+)
+
+// lchtimes uses setattrlist, as the C library's lutimes does,
+// since Darwin has no utimensat system call.
+func lchtimes(name string, atime, mtime time.Time) error {
+	attrs := unix.Attrlist{Bitmapcount: _ATTR_BIT_MAP_COUNT}
+	// The attribute values appear in the order of their bits.
+	var buf [2]syscall.Timespec
+	n := 0
+	if !mtime.IsZero() {
+		attrs.Commonattr |= _ATTR_CMN_MODTIME
+		buf[n] = syscall.NsecToTimespec(mtime.UnixNano())
+		n++
+	}
+	if !atime.IsZero() {
+		attrs.Commonattr |= _ATTR_CMN_ACCTIME
+		buf[n] = syscall.NsecToTimespec(atime.UnixNano())
+		n++
+	}
+	if n == 0 {
+		return nil
+	}
+	err := unix.Setattrlist(name, &attrs, unsafe.Pointer(&buf[0]), uintptr(n)*unsafe.Sizeof(buf[0]), _FSOPT_NOFOLLOW)
+	if err != nil {
+		return &PathError{Op: "lchtimes", Path: name, Err: err}
+	}
+	return nil
+}
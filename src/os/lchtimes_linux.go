@@ -0,0 +1,26 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+import (
+	"internal/syscall/unix"
+	"syscall"
+	"time"
+	"unsafe"
+)
+
+func lchtimes(name string, atime, mtime time.Time) error {
+	p, err := syscall.BytePtrFromString(name)
+	if err != nil {
+		return &PathError{Op: "lchtimes", Path: name, Err: err}
+	}
+	ts := utimensatTimes(atime, mtime)
+	dirfd := unix.AT_FDCWD
+	_, _, errno := syscall.Syscall6(syscall.SYS_UTIMENSAT, uintptr(dirfd), uintptr(unsafe.Pointer(p)), uintptr(unsafe.Pointer(&ts[0])), unix.AT_SYMLINK_NOFOLLOW, 0, 0)
+	if errno != 0 {
+		return &PathError{Op: "lchtimes", Path: name, Err: errno}
+	}
+	return nil
+}
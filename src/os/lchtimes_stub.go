@@ -0,0 +1,14 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build aix || (js && wasm) || plan9 || solaris
+// +build aix js,wasm plan9 solaris
+
+package os
+
+import "time"
+
+func lchtimes(name string, atime, mtime time.Time) error {
+	return &PathError{Op: "lchtimes", Path: name, Err: ErrUnsupported}
+}
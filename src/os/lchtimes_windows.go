@@ -0,0 +1,37 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+import (
+	"syscall"
+	"time"
+)
+
+func lchtimes(name string, atime, mtime time.Time) error {
+	p, err := syscall.UTF16PtrFromString(fixLongPath(name))
+	if err != nil {
+		return &PathError{Op: "lchtimes", Path: name, Err: err}
+	}
+	// FILE_FLAG_OPEN_REPARSE_POINT opens the link itself.
+	h, err := syscall.CreateFile(p, syscall.FILE_WRITE_ATTRIBUTES, syscall.FILE_SHARE_READ|syscall.FILE_SHARE_WRITE|syscall.FILE_SHARE_DELETE,
+		nil, syscall.OPEN_EXISTING, syscall.FILE_FLAG_BACKUP_SEMANTICS|syscall.FILE_FLAG_OPEN_REPARSE_POINT, 0)
+	if err != nil {
+		return &PathError{Op: "lchtimes", Path: name, Err: err}
+	}
+	defer syscall.CloseHandle(h)
+	var a, w *syscall.Filetime
+	if !atime.IsZero() {
+		ft := syscall.NsecToFiletime(atime.UnixNano())
+		a = &ft
+	}
+	if !mtime.IsZero() {
+		ft := syscall.NsecToFiletime(mtime.UnixNano())
+		w = &ft
+	}
+	if err := syscall.SetFileTime(h, nil, a, w); err != nil {
+		return &PathError{Op: "lchtimes", Path: name, Err: err}
+	}
+	return nil
+}
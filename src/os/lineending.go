@@ -0,0 +1,72 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+import "io"
+
+// LineEnding reports the line ending used in the named file: "\r\n",
+// "\n" or "\r" if all the line endings found are of that kind,
+// "mixed" if more than one kind is found, or "" if none is found.
+// To avoid reading all of a large file, it examines only the first
+// sampleSize bytes, and a "\r\n" that straddles their end; a
+// sampleSize of zero or less examines the whole file.
+func LineEnding(name string, sampleSize int) (string, error) {
+	f, err := Open(name)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	var crlf, lf, cr, afterCR bool
+	var off int64 // bytes examined
+	buf := make([]byte, 32<<10)
+read:
+	for {
+		n, err := f.Read(buf)
+		for _, c := range buf[:n] {
+			if afterCR {
+				// The byte after an examined \r is examined
+				// even if it is beyond the sample.
+				afterCR = false
+				if c == '\n' {
+					crlf = true
+					off++
+					continue
+				}
+				cr = true
+			}
+			if sampleSize > 0 && off >= int64(sampleSize) {
+				break read
+			}
+			switch c {
+			case '\n':
+				lf = true
+			case '\r':
+				afterCR = true
+			}
+			off++
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+	if afterCR {
+		cr = true
+	}
+	switch {
+	case crlf && !lf && !cr:
+		return "\r\n", nil
+	case lf && !crlf && !cr:
+		return "\n", nil
+	case cr && !crlf && !lf:
+		return "\r", nil
+	case crlf || lf || cr:
+		return "mixed", nil
+	}
+	return "", nil
+}
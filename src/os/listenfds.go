@@ -0,0 +1,35 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+// ListenFds returns the file descriptors, usually listening sockets,
+// that the service manager passed to the process under the systemd
+// socket activation protocol, as sd_listen_fds(3) does. The descriptors
+// start at 3, and their number is given by the LISTEN_FDS environment
+// variable. They are used only if LISTEN_PID holds the ID of this
+// process; otherwise they were meant for another process, perhaps a
+// parent, and ListenFds returns nil and a nil error, as it does when
+// the variables are unset. The caller can pass the returned files to
+// net.FileListener or net.FilePacketConn.
+//
+// If LISTEN_FDNAMES is set, as it is for sockets with a
+// FileDescriptorName= setting, the name of each File is the
+// corresponding colon-separated element of it. Otherwise each File is
+// named "LISTEN_FD_N", for descriptor N.
+//
+// ListenFds marks the descriptors close-on-exec and unsets the three
+// environment variables, so that child processes do not inherit them.
+// It should therefore be called only once.
+//
+// On Windows, Plan 9 and js/wasm, ListenFds always returns nil and a
+// nil error.
+func ListenFds() ([]*File, error) {
+	defer func() {
+		Unsetenv("LISTEN_PID")
+		Unsetenv("LISTEN_FDS")
+		Unsetenv("LISTEN_FDNAMES")
+	}()
+	return listenFds()
+}
@@ -0,0 +1,70 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build aix || darwin || dragonfly || freebsd || linux || netbsd || openbsd || solaris
+// +build aix darwin dragonfly freebsd linux netbsd openbsd solaris
+
+package os
+
+import (
+	"errors"
+	"internal/itoa"
+	"syscall"
+)
+
+// listenFdsStart is the first file descriptor passed by
+// the socket activation protocol, SD_LISTEN_FDS_START.
+const listenFdsStart = 3
+
+func listenFds() ([]*File, error) {
+	pid := Getenv("LISTEN_PID")
+	if pid == "" {
+		return nil, nil
+	}
+	if p, ok := parseDecimal(pid); !ok {
+		return nil, errListenEnv("LISTEN_PID", pid)
+	} else if p != Getpid() {
+		return nil, nil
+	}
+	nfds := Getenv("LISTEN_FDS")
+	n, ok := parseDecimal(nfds)
+	if !ok || n > 1<<20 {
+		return nil, errListenEnv("LISTEN_FDS", nfds)
+	}
+	var names []string
+	if s, ok := LookupEnv("LISTEN_FDNAMES"); ok && n > 0 {
+		names = splitFdNames(s)
+		if len(names) != n {
+			return nil, errListenEnv("LISTEN_FDNAMES", s)
+		}
+	}
+	files := make([]*File, n)
+	for i := range files {
+		fd := listenFdsStart + i
+		syscall.CloseOnExec(fd)
+		name := "LISTEN_FD_" + itoa.Itoa(fd)
+		if names != nil {
+			name = names[i]
+		}
+		files[i] = NewFile(uintptr(fd), name)
+	}
+	return files, nil
+}
+
+func errListenEnv(name, value string) error {
+	return errors.New("os: invalid " + name + " value " + value)
+}
+
+// splitFdNames splits the colon-separated list s.
+func splitFdNames(s string) []string {
+	var names []string
+	start := 0
+	for i := 0; i < len(s); i++ {
+		if s[i] == ':' {
+			names = append(names, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(names, s[start:])
+}
@@ -0,0 +1,69 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+// LookPathAll searches for executables named file in the directories
+// named by the PATH environment variable, as exec.LookPath does, but
+// returns every match rather than only the first, in the order in which
+// they appear in the path. The first result is the file that
+// exec.LookPath would choose; the rest are the files it shadows.
+// A directory listed more than once contributes its match only once.
+//
+// LookPathAll applies the same rules as exec.LookPath: on Unix, a
+// match must have an execute permission bit set; on Windows, the
+// extensions listed in PATHEXT are tried and the current directory is
+// searched first; and if file contains a path separator (on Plan 9, if
+// it begins with "/", "#", "./" or "../"), it is tried directly and
+// the path is not consulted.
+//
+// If no executable is found, LookPathAll returns an empty slice and a
+// *PathError wrapping ErrNotExist, or the error that prevented file
+// from being used directly.
+func LookPathAll(file string) ([]string, error) {
+	paths, err := lookPathAll(file)
+	if err == nil && len(paths) == 0 {
+		err = ErrNotExist
+	}
+	if err != nil {
+		return []string{}, &PathError{Op: "lookpath", Path: file, Err: underlyingError(err)}
+	}
+	return paths, nil
+}
+
+// appendPath appends path to paths unless it is already present.
+func appendPath(paths []string, path string) []string {
+	for _, p := range paths {
+		if p == path {
+			return paths
+		}
+	}
+	return append(paths, path)
+}
+
+// splitPathList splits a path list.
+// This is based on genSplit from strings/strings.go
+func splitPathList(pathList string) []string {
+	if pathList == "" {
+		return nil
+	}
+	n := 1
+	for i := 0; i < len(pathList); i++ {
+		if pathList[i] == PathListSeparator {
+			n++
+		}
+	}
+	start := 0
+	a := make([]string, n)
+	na := 0
+	for i := 0; i+1 <= len(pathList) && na+1 < n; i++ {
+		if pathList[i] == PathListSeparator {
+			a[na] = pathList[start:i]
+			na++
+			start = i + 1
+		}
+	}
+	a[na] = pathList[start:]
+	return a[:na+1]
+}
@@ -0,0 +1,41 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build aix || darwin || dragonfly || freebsd || (js && wasm) || linux || netbsd || openbsd || solaris
+// +build aix darwin dragonfly freebsd js,wasm linux netbsd openbsd solaris
+
+package os
+
+func lookPathAll(file string) ([]string, error) {
+	if containsSeparator(file) {
+		if err := lookPathExecutable(file); err != nil {
+			return nil, err
+		}
+		return []string{file}, nil
+	}
+	var paths []string
+	for _, dir := range splitPathList(Getenv("PATH")) {
+		if dir == "" {
+			// Unix shell semantics: path element "" means "."
+			dir = "."
+		}
+		path := joinPath(dir, file)
+		if lookPathExecutable(path) == nil {
+			paths = appendPath(paths, path)
+		}
+	}
+	return paths, nil
+}
+
+// lookPathExecutable is findExecutable from os/exec.
+func lookPathExecutable(file string) error {
+	d, err := Stat(file)
+	if err != nil {
+		return err
+	}
+	if m := d.Mode(); !m.IsDir() && m&0111 != 0 {
+		return nil
+	}
+	return ErrPermission
+}
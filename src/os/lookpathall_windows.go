@@ -0,0 +1,117 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+import "internal/bytealg"
+
+func lookPathAll(file string) ([]string, error) {
+	var exts []string
+	if x := Getenv(`PATHEXT`); x != "" {
+		for _, e := range splitPathList(x) {
+			if e == "" {
+				continue
+			}
+			if e[0] != '.' {
+				e = "." + e
+			}
+			exts = append(exts, toLowerASCII(e))
+		}
+	} else {
+		exts = []string{".com", ".exe", ".bat", ".cmd"}
+	}
+
+	if containsSeparator(file) || bytealg.IndexByteString(file, ':') >= 0 {
+		f, err := lookPathExecutable(file, exts)
+		if err != nil {
+			return nil, err
+		}
+		return []string{f}, nil
+	}
+	var paths []string
+	if f, err := lookPathExecutable(joinPath(".", file), exts); err == nil {
+		paths = append(paths, f)
+	}
+	for _, dir := range splitQuotedPathList(Getenv("path")) {
+		if f, err := lookPathExecutable(joinPath(dir, file), exts); err == nil {
+			paths = appendPath(paths, f)
+		}
+	}
+	return paths, nil
+}
+
+// lookPathExecutable is findExecutable from os/exec.
+func lookPathExecutable(file string, exts []string) (string, error) {
+	if len(exts) == 0 {
+		return file, lookPathStat(file)
+	}
+	if hasExt(file) {
+		if lookPathStat(file) == nil {
+			return file, nil
+		}
+	}
+	for _, e := range exts {
+		if f := file + e; lookPathStat(f) == nil {
+			return f, nil
+		}
+	}
+	return "", ErrNotExist
+}
+
+func lookPathStat(file string) error {
+	d, err := Stat(file)
+	if err != nil {
+		return err
+	}
+	if d.IsDir() {
+		return ErrPermission
+	}
+	return nil
+}
+
+// hasExt reports whether the final element of file has an extension.
+func hasExt(file string) bool {
+	for i := len(file) - 1; i >= 0; i-- {
+		switch file[i] {
+		case '.':
+			return true
+		case ':', '\\', '/':
+			return false
+		}
+	}
+	return false
+}
+
+// splitQuotedPathList is filepath.SplitList: it splits a path list,
+// respecting but removing double quotes.
+func splitQuotedPathList(path string) []string {
+	var list []string
+	if path == "" {
+		return list
+	}
+	var elem []byte
+	quo := false
+	for i := 0; i < len(path); i++ {
+		switch c := path[i]; {
+		case c == '"':
+			quo = !quo
+		case c == PathListSeparator && !quo:
+			list = append(list, string(elem))
+			elem = elem[:0]
+		default:
+			elem = append(elem, c)
+		}
+	}
+	return append(list, string(elem))
+}
+
+func toLowerASCII(s string) string {
+	b := []byte(s)
+	for i, c := range b {
+		if 'A' <= c && c <= 'Z' {
+			b[i] = c + 'a' - 'A'
+		}
+	}
+	return string(b)
+}
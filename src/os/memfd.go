@@ -0,0 +1,60 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+// Flags to MemfdCreate.
+const (
+	// MFD_ALLOW_SEALING allows seals to be added to the file. Without
+	// it the file is created with SEAL_SEAL, so AddSeals fails.
+	MFD_ALLOW_SEALING uint = 0x2
+)
+
+// Seals, as added by AddSeals. Once added, a seal cannot be removed.
+const (
+	SEAL_SEAL   uint = 0x1 // further seals cannot be added
+	SEAL_SHRINK uint = 0x2 // the file cannot be truncated to a smaller size
+	SEAL_GROW   uint = 0x4 // the file cannot grow, by writes or truncation
+	SEAL_WRITE  uint = 0x8 // the contents cannot be modified
+)
+
+// MemfdCreate creates an anonymous file that lives in memory and
+// returns it open for reading and writing. The name is used only for
+// debugging, and need not be unique. The file is removed when the last
+// reference to it is closed.
+//
+// An anonymous file can be shared with another process, for example by
+// passing it in ProcAttr.Files or with SendFD over a Unix socket. If it
+// is created with the MFD_ALLOW_SEALING flag, the creator can add seals
+// with AddSeals, after which the kernel refuses the sealed operations
+// to every holder of the file, including the creator; the recipient can
+// check them with GetSeals. A typical use is to write the data, add
+// SEAL_SHRINK|SEAL_GROW|SEAL_WRITE|SEAL_SEAL, and pass the file to a
+// process that can then trust that the contents will not change.
+//
+// The file is close-on-exec. MemfdCreate is only supported on Linux. On
+// other systems it returns an error wrapping ErrUnsupported.
+func MemfdCreate(name string, flags uint) (*File, error) {
+	return memfdCreate(name, flags)
+}
+
+// AddSeals adds seals to f, which must have been created by MemfdCreate
+// with the MFD_ALLOW_SEALING flag. Adding SEAL_WRITE fails with EBUSY
+// while f is mapped into memory shared and writable, as by Map with
+// MapReadWrite. Any error is of type *PathError.
+func (f *File) AddSeals(seals uint) error {
+	if err := f.checkValid("fcntl"); err != nil {
+		return err
+	}
+	return f.addSeals(seals)
+}
+
+// GetSeals returns the seals of f. For a file that does not support
+// sealing, it returns an error wrapping EINVAL.
+func (f *File) GetSeals() (uint, error) {
+	if err := f.checkValid("fcntl"); err != nil {
+		return 0, err
+	}
+	return f.getSeals()
+}
@@ -0,0 +1,43 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+import (
+	"internal/syscall/unix"
+	"runtime"
+)
+
+func memfdCreate(name string, flags uint) (*File, error) {
+	fd, err := unix.MemfdCreate(name, int(flags)|unix.MFD_CLOEXEC)
+	if err != nil {
+		return nil, &PathError{Op: "memfd_create", Path: name, Err: err}
+	}
+	return newFile(uintptr(fd), "memfd:"+name, kindOpenFile), nil
+}
+
+func (f *File) addSeals(seals uint) error {
+	_, err := f.sealsFcntl(unix.F_ADD_SEALS, int(seals))
+	return err
+}
+
+func (f *File) getSeals() (uint, error) {
+	seals, err := f.sealsFcntl(unix.F_GET_SEALS, 0)
+	return uint(seals), err
+}
+
+func (f *File) sealsFcntl(cmd, arg int) (int, error) {
+	var v int
+	var e error
+	if err := f.pfd.RawControl(func(fd uintptr) {
+		v, e = unix.Fcntl(int(fd), cmd, arg)
+	}); err != nil {
+		return 0, f.wrapErr("fcntl", err)
+	}
+	runtime.KeepAlive(f)
+	if e != nil {
+		return 0, f.wrapErr("fcntl", e)
+	}
+	return v, nil
+}
@@ -0,0 +1,20 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !linux
+// +build !linux
+
+package os
+
+func memfdCreate(name string, flags uint) (*File, error) {
+	return nil, &PathError{Op: "memfd_create", Path: name, Err: ErrUnsupported}
+}
+
+func (f *File) addSeals(seals uint) error {
+	return f.wrapErr("fcntl", ErrUnsupported)
+}
+
+func (f *File) getSeals() (uint, error) {
+	return 0, f.wrapErr("fcntl", ErrUnsupported)
+}
@@ -0,0 +1,91 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+import "time"
+
+// FileMetadata describes changes to the metadata of a file,
+// for use with File.ApplyMetadata.
+type FileMetadata struct {
+	// Mode is the new mode of the file, interpreted as by Chmod.
+	// It is applied only if SetMode is true.
+	Mode    FileMode
+	SetMode bool
+
+	// Uid and Gid are the new owner and group of the file,
+	// interpreted as by Chown: a value of -1 leaves that id unchanged.
+	// They are applied only if SetOwner is true.
+	Uid, Gid int
+	SetOwner bool
+
+	// Atime and Mtime are the new access and modification times of
+	// the file. A zero time leaves the corresponding time unchanged.
+	Atime time.Time
+	Mtime time.Time
+}
+
+// ApplyMetadata changes the ownership, mode and times of the file as
+// described by meta, operating on the open file rather than on its name.
+//
+// The changes are applied in a fixed order: ownership first, then the
+// mode (so that a mode containing ModeSetuid or ModeSetgid is not
+// cleared by the change of owner), then the times (so that they are not
+// disturbed by the other changes). ApplyMetadata stops at the first
+// error and returns it; the changes applied before the error remain
+// in effect. Fields that are left unchanged cost no system call.
+//
+// On platforms that cannot change the times of an open file,
+// ApplyMetadata changes the times of the file's name instead.
+// If there is an error, it will be of type *PathError.
+func (f *File) ApplyMetadata(meta FileMetadata) error {
+	if err := f.checkValid("applymetadata"); err != nil {
+		return err
+	}
+	if meta.SetOwner && (meta.Uid != -1 || meta.Gid != -1) {
+		if err := f.Chown(meta.Uid, meta.Gid); err != nil {
+			return err
+		}
+	}
+	if meta.SetMode {
+		if err := f.Chmod(meta.Mode); err != nil {
+			return err
+		}
+	}
+	if !meta.Atime.IsZero() || !meta.Mtime.IsZero() {
+		if err := f.chtimes(meta.Atime, meta.Mtime); err != nil {
+			return f.wrapErr("chtimes", err)
+		}
+	}
+	return nil
+}
+
+// fillTimes returns at and mt with zero values
+// replaced by the current times of the file.
+func (f *File) fillTimes(at, mt time.Time) (time.Time, time.Time, error) {
+	return fillTimesFrom(f.Stat, at, mt)
+}
+
+// lfillTimes is like fillTimes but for the named file,
+// not following a final symbolic link.
+func lfillTimes(name string, at, mt time.Time) (time.Time, time.Time, error) {
+	return fillTimesFrom(func() (FileInfo, error) { return Lstat(name) }, at, mt)
+}
+
+func fillTimesFrom(stat func() (FileInfo, error), at, mt time.Time) (time.Time, time.Time, error) {
+	if !at.IsZero() && !mt.IsZero() {
+		return at, mt, nil
+	}
+	fi, err := stat()
+	if err != nil {
+		return at, mt, underlyingError(err)
+	}
+	if at.IsZero() {
+		at = atime(fi)
+	}
+	if mt.IsZero() {
+		mt = fi.ModTime()
+	}
+	return at, mt, nil
+}
@@ -0,0 +1,252 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+import "errors"
+
+// MapProt specifies the access permitted to a MappedFile.
+type MapProt int
+
+const (
+	// MapReadOnly maps the file for reading. Writing to the mapped
+	// memory crashes the program.
+	MapReadOnly MapProt = iota
+	// MapReadWrite maps the file for reading and writing. Writes to
+	// the mapped memory are carried through to the file; use Flush
+	// to wait for them to reach stable storage.
+	MapReadWrite
+)
+
+// MapAdvice describes the expected pattern of access to a MappedFile.
+type MapAdvice int
+
+const (
+	// MapNormal requests the default behavior.
+	MapNormal MapAdvice = iota
+	// MapSequential indicates that pages will be accessed in order,
+	// so the system may read ahead aggressively and free pages soon
+	// after they are used.
+	MapSequential
+	// MapRandom indicates that pages will be accessed in random
+	// order, so read-ahead is likely to be wasted.
+	MapRandom
+	// MapWillNeed indicates that the region will be accessed soon,
+	// so the system may start reading it in.
+	MapWillNeed
+	// MapDontNeed indicates that the region will not be accessed
+	// soon, so the system may free the pages backing it. Later
+	// accesses read the data back from the file.
+	MapDontNeed
+)
+
+// A MappedFile is a region of a file mapped into memory.
+//
+// The slice returned by Bytes refers directly to the mapped memory and
+// is valid only until Close is called. Using it after Close, including
+// through any slice derived from it, crashes the program; callers must
+// copy out any data they need to keep. A MappedFile is not safe for
+// concurrent use with its Close method.
+type MappedFile struct {
+	data     []byte // entire mapping, starting at an aligned offset
+	b        []byte // region requested by the caller, within data
+	writable bool
+	closed   bool
+}
+
+// Map maps length bytes of f, starting at offset off, into memory.
+// A length of zero maps from off to the end of the file. The region
+// must lie within the file; to map a larger region for writing, extend
+// the file first with Truncate. The offset need not be page aligned.
+//
+// The mapping is independent of f: f may be closed while the MappedFile
+// remains in use. The mapping is released only by Close. It is not
+// released when the MappedFile becomes unreachable, because the slice
+// returned by Bytes may still be in use; a MappedFile that is never
+// closed leaks its mapping.
+//
+// Map is supported on Unix systems other than AIX and Solaris, and on
+// Windows. On other systems it returns an error wrapping ErrUnsupported.
+func Map(f *File, off, length int64, prot MapProt) (*MappedFile, error) {
+	if err := f.checkValid("mmap"); err != nil {
+		return nil, err
+	}
+	if prot != MapReadOnly && prot != MapReadWrite {
+		return nil, &PathError{Op: "mmap", Path: f.name, Err: errors.New("invalid protection")}
+	}
+	if off < 0 || length < 0 {
+		return nil, &PathError{Op: "mmap", Path: f.name, Err: ErrInvalid}
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	size := fi.Size()
+	if length == 0 {
+		length = size - off
+	}
+	if off > size || length > size-off {
+		return nil, &PathError{Op: "mmap", Path: f.name, Err: errors.New("region extends beyond end of file")}
+	}
+	if length == 0 {
+		// Mapping zero bytes is an error on most systems;
+		// an empty region needs no mapping.
+		return &MappedFile{writable: prot == MapReadWrite}, nil
+	}
+
+	// The mapping must start at a multiple of the granularity.
+	gran := int64(mapGranularity())
+	start := off &^ (gran - 1)
+	n := int(off - start + length)
+	if int64(n) != off-start+length {
+		return nil, &PathError{Op: "mmap", Path: f.name, Err: errors.New("region too large")}
+	}
+	data, err := f.mmap(start, n, prot)
+	if err != nil {
+		return nil, err
+	}
+	m := &MappedFile{
+		data:     data,
+		b:        data[off-start : n : n],
+		writable: prot == MapReadWrite,
+	}
+	return m, nil
+}
+
+// Bytes returns the mapped region. It returns nil after Close.
+func (m *MappedFile) Bytes() []byte {
+	return m.b
+}
+
+// Len returns the length of the mapped region.
+func (m *MappedFile) Len() int {
+	return len(m.b)
+}
+
+// Flush writes any changes made to the mapped memory back to the file
+// and waits for the write to complete. It does nothing for a read-only
+// mapping.
+func (m *MappedFile) Flush() error {
+	if m.closed {
+		return ErrClosed
+	}
+	if !m.writable || len(m.data) == 0 {
+		return nil
+	}
+	if err := msync(m.data); err != nil {
+		return NewSyscallError("msync", err)
+	}
+	return nil
+}
+
+// Advise tells the system how the mapped region is likely to be
+// accessed, so that it can tune read-ahead and caching. The advice
+// applies to the whole mapping and does not change the contents of
+// the memory. On Unix systems it is implemented by madvise; on Windows
+// it has no effect.
+func (m *MappedFile) Advise(advice MapAdvice) error {
+	if m.closed {
+		return ErrClosed
+	}
+	if advice < MapNormal || advice > MapDontNeed {
+		return NewSyscallError("madvise", ErrInvalid)
+	}
+	if len(m.data) == 0 {
+		return nil
+	}
+	if err := madvise(m.data, advice); err != nil {
+		return NewSyscallError("madvise", err)
+	}
+	return nil
+}
+
+// Lock locks the mapped region into physical memory, so that it is not
+// written to swap. See MlockRegion for the limits that apply.
+func (m *MappedFile) Lock() error {
+	if m.closed {
+		return ErrClosed
+	}
+	return MlockRegion(m.data)
+}
+
+// Unlock undoes the effect of Lock.
+func (m *MappedFile) Unlock() error {
+	if m.closed {
+		return ErrClosed
+	}
+	return MunlockRegion(m.data)
+}
+
+// Close unmaps the region. After Close, the memory returned by Bytes
+// must no longer be used. Close returns ErrClosed if the MappedFile has
+// already been closed.
+func (m *MappedFile) Close() error {
+	if m.closed {
+		return ErrClosed
+	}
+	m.closed = true
+	data := m.data
+	m.data, m.b = nil, nil
+	if len(data) == 0 {
+		return nil
+	}
+	if err := munmap(data); err != nil {
+		return NewSyscallError("munmap", err)
+	}
+	return nil
+}
+
+// memlockLimitError reports that locking memory failed because the
+// process would exceed its limit on locked memory.
+type memlockLimitError struct {
+	err error
+}
+
+func (e *memlockLimitError) Error() string {
+	return "locked memory limit exceeded: " + e.err.Error()
+}
+
+func (e *memlockLimitError) Unwrap() error { return e.err }
+
+// MlockRegion locks the pages containing b into physical memory, so that
+// they are never written to swap. It is intended for buffers holding
+// secrets such as key material. The pages stay locked until
+// MunlockRegion is called or the memory is unmapped; b should therefore
+// not be memory that the Go runtime may reuse for other purposes, such
+// as a short-lived slice, but a long-lived buffer or a MappedFile.
+//
+// The amount of memory a process may lock is limited: on Unix systems by
+// RLIMIT_MEMLOCK, and on Windows by the minimum working set size. When a
+// call fails because of that limit the error says so and also wraps the
+// underlying system error.
+//
+// Locking does not keep the memory out of core dumps; use
+// ExcludeFromCoreDump for that. MlockRegion is implemented by mlock on
+// Unix systems and VirtualLock on Windows; on other systems it returns
+// an error wrapping ErrUnsupported.
+func MlockRegion(b []byte) error {
+	if len(b) == 0 {
+		return nil
+	}
+	if err := mlock(b); err != nil {
+		if isMemlockLimit(err) {
+			err = &memlockLimitError{err}
+		}
+		return NewSyscallError("mlock", err)
+	}
+	return nil
+}
+
+// MunlockRegion unlocks the pages containing b, allowing them to be
+// swapped again. Pages are locked at most once, so unlocking a page
+// that is shared with another locked region unlocks it for both.
+func MunlockRegion(b []byte) error {
+	if len(b) == 0 {
+		return nil
+	}
+	if err := munlock(b); err != nil {
+		return NewSyscallError("munlock", err)
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build aix || (js && wasm) || plan9 || solaris
+// +build aix js,wasm plan9 solaris
+
+package os
+
+func mapGranularity() int {
+	return 4096
+}
+
+func (f *File) mmap(off int64, n int, prot MapProt) ([]byte, error) {
+	return nil, f.wrapErr("mmap", ErrUnsupported)
+}
+
+func munmap(data []byte) error {
+	return ErrUnsupported
+}
+
+func msync(data []byte) error {
+	return ErrUnsupported
+}
+
+func madvise(data []byte, advice MapAdvice) error {
+	return ErrUnsupported
+}
+
+func mlock(b []byte) error {
+	return ErrUnsupported
+}
+
+func munlock(b []byte) error {
+	return ErrUnsupported
+}
+
+func isMemlockLimit(err error) bool {
+	return false
+}
@@ -0,0 +1,77 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+// +build darwin dragonfly freebsd linux netbsd openbsd
+
+package os
+
+import (
+	"internal/syscall/unix"
+	"runtime"
+	"syscall"
+)
+
+func mapGranularity() int {
+	return syscall.Getpagesize()
+}
+
+func (f *File) mmap(off int64, n int, prot MapProt) ([]byte, error) {
+	sysProt := syscall.PROT_READ
+	if prot == MapReadWrite {
+		sysProt |= syscall.PROT_WRITE
+	}
+	var data []byte
+	var e error
+	if err := f.pfd.RawControl(func(fd uintptr) {
+		data, e = syscall.Mmap(int(fd), off, n, sysProt, syscall.MAP_SHARED)
+	}); err != nil {
+		return nil, f.wrapErr("mmap", err)
+	}
+	runtime.KeepAlive(f)
+	if e != nil {
+		return nil, f.wrapErr("mmap", e)
+	}
+	return data, nil
+}
+
+func munmap(data []byte) error {
+	return syscall.Munmap(data)
+}
+
+func msync(data []byte) error {
+	return unix.Msync(data, unix.MS_SYNC)
+}
+
+func madvise(data []byte, advice MapAdvice) error {
+	var a int
+	switch advice {
+	case MapNormal:
+		a = syscall.MADV_NORMAL
+	case MapSequential:
+		a = syscall.MADV_SEQUENTIAL
+	case MapRandom:
+		a = syscall.MADV_RANDOM
+	case MapWillNeed:
+		a = syscall.MADV_WILLNEED
+	case MapDontNeed:
+		a = syscall.MADV_DONTNEED
+	}
+	return unix.Madvise(data, a)
+}
+
+func mlock(b []byte) error {
+	return unix.Mlock(b)
+}
+
+func munlock(b []byte) error {
+	return unix.Munlock(b)
+}
+
+// isMemlockLimit reports whether err from mlock means that RLIMIT_MEMLOCK
+// would be exceeded. Linux and the BSDs report ENOMEM, or EPERM when the
+// limit is zero; Darwin reports EAGAIN.
+func isMemlockLimit(err error) bool {
+	return err == syscall.ENOMEM || err == syscall.EAGAIN || err == syscall.EPERM
+}
@@ -0,0 +1,81 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+import (
+	"internal/unsafeheader"
+	"runtime"
+	"syscall"
+	"unsafe"
+)
+
+// mapGranularity returns the system allocation granularity, to which
+// MapViewOfFile offsets must be aligned. It is 64kB on all versions of
+// Windows.
+func mapGranularity() int {
+	return 64 << 10
+}
+
+func (f *File) mmap(off int64, n int, prot MapProt) ([]byte, error) {
+	pageProt, access := uint32(syscall.PAGE_READONLY), uint32(syscall.FILE_MAP_READ)
+	if prot == MapReadWrite {
+		pageProt, access = syscall.PAGE_READWRITE, syscall.FILE_MAP_WRITE
+	}
+	var addr uintptr
+	var e error
+	if err := f.pfd.RawControl(func(fd uintptr) {
+		h, err := syscall.CreateFileMapping(syscall.Handle(fd), nil, pageProt, 0, 0, nil)
+		if err != nil {
+			e = NewSyscallError("CreateFileMapping", err)
+			return
+		}
+		// The view holds its own reference to the mapping object.
+		defer syscall.CloseHandle(h)
+		addr, err = syscall.MapViewOfFile(h, access, uint32(off>>32), uint32(off), uintptr(n))
+		if err != nil {
+			e = NewSyscallError("MapViewOfFile", err)
+		}
+	}); err != nil {
+		return nil, f.wrapErr("mmap", err)
+	}
+	runtime.KeepAlive(f)
+	if e != nil {
+		return nil, f.wrapErr("mmap", e)
+	}
+	var data []byte
+	hdr := (*unsafeheader.Slice)(unsafe.Pointer(&data))
+	hdr.Data = unsafe.Pointer(addr)
+	hdr.Len = n
+	hdr.Cap = n
+	return data, nil
+}
+
+func munmap(data []byte) error {
+	return syscall.UnmapViewOfFile(uintptr(unsafe.Pointer(&data[0])))
+}
+
+func msync(data []byte) error {
+	return syscall.FlushViewOfFile(uintptr(unsafe.Pointer(&data[0])), uintptr(len(data)))
+}
+
+// madvise does nothing: Windows has no general equivalent, and the
+// closest, PrefetchVirtualMemory, is only a hint for MapWillNeed.
+func madvise(data []byte, advice MapAdvice) error {
+	return nil
+}
+
+func mlock(b []byte) error {
+	return syscall.VirtualLock(uintptr(unsafe.Pointer(&b[0])), uintptr(len(b)))
+}
+
+func munlock(b []byte) error {
+	return syscall.VirtualUnlock(uintptr(unsafe.Pointer(&b[0])), uintptr(len(b)))
+}
+
+const _ERROR_WORKING_SET_QUOTA = syscall.Errno(1453)
+
+func isMemlockLimit(err error) bool {
+	return err == _ERROR_WORKING_SET_QUOTA
+}
@@ -0,0 +1,93 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+import "io"
+
+// A MultiWriteError records the failures of a write to several files,
+// as returned by MultiWriteFile and by the writer returned by
+// MultiWriter. The write succeeded for the files that have no error.
+type MultiWriteError struct {
+	// Errs holds an error for each file that failed, in the order in
+	// which the files were given. Each error identifies its file,
+	// usually as a *PathError.
+	Errs []error
+}
+
+func (e *MultiWriteError) Error() string {
+	s := e.Errs[0].Error()
+	for _, err := range e.Errs[1:] {
+		s += "; " + err.Error()
+	}
+	return s
+}
+
+// Unwrap returns the first of the errors, so that errors.Is and
+// errors.As examine it.
+func (e *MultiWriteError) Unwrap() error {
+	return e.Errs[0]
+}
+
+// MultiWriteFile writes data to each of the named files, creating them
+// with permissions perm (before umask) if necessary, as WriteFile does.
+// A failure to write one file does not stop the others from being
+// written; if any fail, the error is a *MultiWriteError.
+func MultiWriteFile(names []string, data []byte, perm FileMode) error {
+	var errs []error
+	for _, name := range names {
+		if err := WriteFile(name, data, perm); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if errs != nil {
+		return &MultiWriteError{errs}
+	}
+	return nil
+}
+
+// MultiWriter returns a writer that writes the data passed to each of
+// its writes to all of files, for example to keep an audit copy of a
+// log. Unlike io.MultiWriter, a failure to write to one file does not
+// stop the data from being written to the others. Once a write to a
+// file fails, the writer stops writing to that file, since the data it
+// holds is incomplete, and every later Write reports the same error for
+// it. If any file has failed, Write returns a *MultiWriteError, and the
+// count of bytes written to the file that received the fewest, which is
+// 0 for a file that failed earlier.
+func MultiWriter(files ...*File) io.Writer {
+	return &multiFileWriter{
+		files: append([]*File(nil), files...),
+		errs:  make([]error, len(files)),
+	}
+}
+
+type multiFileWriter struct {
+	files []*File
+	errs  []error // the error of each failed file, or nil
+}
+
+func (w *multiFileWriter) Write(p []byte) (int, error) {
+	n := len(p)
+	var errs []error
+	for i, f := range w.files {
+		if w.errs[i] != nil {
+			errs = append(errs, w.errs[i])
+			n = 0
+			continue
+		}
+		m, err := f.Write(p)
+		if err != nil {
+			w.errs[i] = err
+			errs = append(errs, err)
+		}
+		if m < n {
+			n = m
+		}
+	}
+	if errs != nil {
+		return n, &MultiWriteError{errs}
+	}
+	return n, nil
+}
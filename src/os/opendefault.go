@@ -0,0 +1,30 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+// OpenDefault opens target, which may be a file name or a URL, in the
+// application that the user has chosen to handle it, as clicking on it
+// in a file manager or a link in a terminal would. It uses ShellExecute
+// on Windows, the open command on macOS, and xdg-open, or else
+// "gio open", on other Unix systems.
+//
+// OpenDefault does not wait for the application to exit. It reports
+// an error if the handler cannot be started, for example because no
+// application is associated with target. On Unix the launching program
+// is given a short time to report such a failure; if it is still
+// running after that, OpenDefault assumes the application has started
+// and returns nil. Any error is of type *PathError.
+//
+// On platforms without a notion of a default application, OpenDefault
+// returns an error wrapping ErrUnsupported.
+func OpenDefault(target string) error {
+	if target == "" {
+		return &PathError{Op: "OpenDefault", Path: target, Err: ErrInvalid}
+	}
+	if err := openDefault(target); err != nil {
+		return &PathError{Op: "OpenDefault", Path: target, Err: underlyingError(err)}
+	}
+	return nil
+}
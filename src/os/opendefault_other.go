@@ -0,0 +1,12 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build aix || android || ios || js || plan9
+// +build aix android ios js plan9
+
+package os
+
+func openDefault(target string) error {
+	return ErrUnsupported
+}
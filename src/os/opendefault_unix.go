@@ -0,0 +1,72 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build (darwin && !ios) || dragonfly || freebsd || (linux && !android) || netbsd || openbsd || solaris
+// +build darwin,!ios dragonfly freebsd linux,!android netbsd openbsd solaris
+
+package os
+
+import (
+	"errors"
+	"runtime"
+	"time"
+)
+
+// openDefaultWait is how long OpenDefault waits for the launching
+// program to exit. It is a variable for testing.
+var openDefaultWait = time.Second
+
+func openDefault(target string) error {
+	launchers := [][]string{{"xdg-open"}, {"gio", "open"}}
+	if runtime.GOOS == "darwin" {
+		launchers = [][]string{{"open"}}
+	}
+	if target[0] == '-' {
+		// A URL cannot begin with '-', so target is a relative file
+		// name. Keep the launcher from taking it for an option.
+		target = "." + string(PathSeparator) + target
+	}
+	for _, argv := range launchers {
+		paths, err := lookPathAll(argv[0])
+		if err != nil || len(paths) == 0 {
+			continue
+		}
+		return runLauncher(paths[0], append(argv, target))
+	}
+	return errors.New(launchers[0][0] + ": executable file not found in $PATH")
+}
+
+// runLauncher starts the program at path with arguments argv and waits
+// for up to openDefaultWait for it to exit, reporting an error if it
+// fails.
+func runLauncher(path string, argv []string) error {
+	null, err := OpenFile(DevNull, O_RDWR, 0)
+	if err != nil {
+		return err
+	}
+	p, err := StartProcess(path, argv, &ProcAttr{Files: []*File{null, null, null}})
+	null.Close()
+	if err != nil {
+		return err
+	}
+	done := make(chan error, 1)
+	go func() {
+		state, err := p.Wait()
+		if err == nil && !state.Success() {
+			err = errors.New(argv[0] + ": " + state.String())
+		}
+		done <- err
+	}()
+	t := time.NewTimer(openDefaultWait)
+	defer t.Stop()
+	select {
+	case err := <-done:
+		return err
+	case <-t.C:
+		// The launcher may run the application in the foreground,
+		// and so not exit until it does. It is reaped in the
+		// background.
+		return nil
+	}
+}
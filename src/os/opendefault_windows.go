@@ -0,0 +1,19 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+import (
+	"internal/syscall/windows"
+	"syscall"
+)
+
+func openDefault(target string) error {
+	file, err := syscall.UTF16PtrFromString(target)
+	if err != nil {
+		return err
+	}
+	verb, _ := syscall.UTF16PtrFromString("open")
+	return windows.ShellExecute(0, verb, file, nil, nil, windows.SW_SHOWNORMAL)
+}
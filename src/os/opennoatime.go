@@ -0,0 +1,19 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+// OpenNoATime opens the named file for reading, like Open, but asks
+// the system not to update the file's access time when it is read,
+// which is useful for programs such as indexers and backup tools that
+// scan many files.
+//
+// On Linux the file is opened with O_NOATIME. The kernel permits that
+// only for the file's owner or a privileged process, so if it fails
+// with EPERM OpenNoATime falls back to a plain Open. On other systems
+// OpenNoATime is the same as Open. Either way, it only makes a
+// difference on file systems mounted with access time updates enabled.
+func OpenNoATime(name string) (*File, error) {
+	return openNoATime(name)
+}
@@ -0,0 +1,16 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+import "syscall"
+
+func openNoATime(name string) (*File, error) {
+	f, err := OpenFile(name, O_RDONLY|syscall.O_NOATIME, 0)
+	if err != nil && underlyingErrorIs(err, syscall.EPERM) {
+		// Not the owner of the file: read it without the flag.
+		return Open(name)
+	}
+	return f, err
+}
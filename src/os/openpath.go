@@ -0,0 +1,26 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+import "internal/testlog"
+
+// OpenPath opens the named file or directory as a handle to its
+// location in the file system, without opening its contents. On Linux
+// it uses O_PATH, so it does not need permission to read the file, and
+// works, for example, on a directory that cannot be listed. The handle
+// cannot be read or written, but it can be passed to Stat and Chdir,
+// and its descriptor, from Fd, can be used as the directory of system
+// calls that take one, such as openat(2). It refers to the same file
+// even if the path is later renamed or replaced, which makes it a safe
+// starting point for operations relative to a directory.
+//
+// If the named file is a symbolic link, OpenPath opens its target. On
+// systems other than Linux, OpenPath opens the file for reading, as
+// Open does, and so requires permission to read it.
+// If there is an error, it will be of type *PathError.
+func OpenPath(name string) (*File, error) {
+	testlog.Open(name)
+	return openPath(name)
+}
@@ -0,0 +1,24 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+import (
+	"internal/syscall/unix"
+	"syscall"
+)
+
+func openPath(name string) (*File, error) {
+	var r int
+	err := ignoringEINTR(func() error {
+		var err error
+		r, err = syscall.Open(name, unix.O_PATH|syscall.O_CLOEXEC, 0)
+		return err
+	})
+	if err != nil {
+		return nil, &PathError{Op: "open", Path: name, Err: err}
+	}
+	// An O_PATH descriptor cannot be added to the poller.
+	return newFile(uintptr(r), name, kindNewFile), nil
+}
@@ -0,0 +1,51 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os_test
+
+import (
+	"io"
+	. "os"
+	"path/filepath"
+	"syscall"
+	"testing"
+)
+
+func TestOpenPathNoReadPermission(t *testing.T) {
+	locked := filepath.Join(t.TempDir(), "locked")
+	if err := Mkdir(locked, 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteFile(filepath.Join(locked, "file"), []byte("data"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	// Without read permission the directory cannot be listed, but it
+	// can be opened as a path and searched through the handle.
+	if err := Chmod(locked, 0100); err != nil {
+		t.Fatal(err)
+	}
+	defer Chmod(locked, 0700)
+
+	f, err := OpenPath(locked)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if fi, err := f.Stat(); err != nil || !fi.IsDir() {
+		t.Fatalf("Stat = %v, %v; want a directory", fi, err)
+	}
+	if _, err := f.ReadDir(-1); err == nil {
+		t.Error("ReadDir of O_PATH handle succeeded")
+	}
+
+	fd, err := syscall.Openat(int(f.Fd()), "file", syscall.O_RDONLY|syscall.O_CLOEXEC, 0)
+	if err != nil {
+		t.Fatalf("openat through O_PATH handle: %v", err)
+	}
+	g := NewFile(uintptr(fd), "file")
+	defer g.Close()
+	if b, err := io.ReadAll(g); err != nil || string(b) != "data" {
+		t.Errorf("ReadAll = %q, %v; want %q", b, err, "data")
+	}
+}
@@ -0,0 +1,16 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+// OpenSequential opens the named file for reading, like Open, and
+// advises the system that the file will be read sequentially from
+// start to finish, so that it can read ahead more aggressively.
+// On Linux and FreeBSD the advice is given with posix_fadvise; on
+// Windows the file is opened with FILE_FLAG_SEQUENTIAL_SCAN. On other
+// systems OpenSequential is the same as Open. The advice is only a
+// hint: if the system rejects it, the file is opened anyway.
+func OpenSequential(name string) (*File, error) {
+	return openSequential(name)
+}
@@ -0,0 +1,23 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build freebsd || linux
+// +build freebsd linux
+
+package os
+
+import "internal/syscall/unix"
+
+func openSequential(name string) (*File, error) {
+	f, err := Open(name)
+	if err != nil {
+		return nil, err
+	}
+	// Ignore errors: the advice is only a hint, and is
+	// rejected for pipes and some file systems.
+	f.pfd.RawControl(func(fd uintptr) {
+		unix.Fadvise(int(fd), unix.POSIX_FADV_SEQUENTIAL)
+	})
+	return f, nil
+}
@@ -6,6 +6,8 @@ package os_test
 
 import (
 	"bytes"
+	"compress/gzip"
+	"context"
 	"errors"
 	"flag"
 	"fmt"
@@ -438,6 +440,105 @@ func TestFileReadDir(t *testing.T) {
 	testReadDir(t.TempDir(), nil, t)
 }
 
+func TestReadDirFiltered(t *testing.T) {
+	dir := t.TempDir()
+	// More files than fit in one batch.
+	for i := 0; i < 300; i++ {
+		if err := WriteFile(filepath.Join(dir, fmt.Sprintf("file%03d", i)), nil, 0666); err != nil {
+			t.Fatal(err)
+		}
+	}
+	wantDirs := []string{"a", "b", "c"}
+	for _, d := range []string{"c", "a", "b"} {
+		if err := Mkdir(filepath.Join(dir, d), 0777); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	names := func(entries []DirEntry) []string {
+		var s []string
+		for _, e := range entries {
+			s = append(s, e.Name())
+		}
+		return s
+	}
+
+	dirs, err := ReadDirs(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := names(dirs); !reflect.DeepEqual(got, wantDirs) {
+		t.Errorf("ReadDirs = %q, want %q", got, wantDirs)
+	}
+
+	files, err := ReadDirFiltered(dir, func(d DirEntry) bool {
+		return d.Type().IsRegular() && strings.HasSuffix(d.Name(), "9")
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 30 {
+		t.Errorf("ReadDirFiltered returned %d entries, want 30", len(files))
+	}
+	if got := names(files); !sort.StringsAreSorted(got) {
+		t.Errorf("ReadDirFiltered entries are not sorted: %q", got)
+	}
+
+	if _, err := ReadDirs(filepath.Join(dir, "missing")); !IsNotExist(err) {
+		t.Errorf("ReadDirs of missing directory: err = %v, want not-exist error", err)
+	}
+}
+
+func TestReadDirDepth(t *testing.T) {
+	root := t.TempDir()
+	for _, d := range []string{"a/b/c/d", "e"} {
+		if err := MkdirAll(filepath.Join(root, filepath.FromSlash(d)), 0777); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, f := range []string{"f", "a/g", "a/b/h", "a/b/c/i"} {
+		if err := WriteFile(filepath.Join(root, filepath.FromSlash(f)), nil, 0666); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		depth int
+		want  []string
+	}{
+		{0, []string{"a", "e", "f"}},
+		{1, []string{"a", "a/b", "a/g", "e", "f"}},
+		{2, []string{"a", "a/b", "a/b/c", "a/b/h", "a/g", "e", "f"}},
+		{-1, []string{"a", "a/b", "a/b/c", "a/b/c/d", "a/b/c/i", "a/b/h", "a/g", "e", "f"}},
+	}
+	for _, tt := range tests {
+		paths, err := ReadDirDepth(root, tt.depth)
+		if err != nil {
+			t.Fatalf("ReadDirDepth(%d): %v", tt.depth, err)
+		}
+		for i := range paths {
+			paths[i] = filepath.ToSlash(paths[i])
+		}
+		if !reflect.DeepEqual(paths, tt.want) {
+			t.Errorf("ReadDirDepth(%d) = %q, want %q", tt.depth, paths, tt.want)
+		}
+	}
+
+	if testenv.HasSymlink() {
+		// A symbolic link to an ancestor is listed but not followed.
+		if err := Symlink(root, filepath.Join(root, "e", "loop")); err != nil {
+			t.Fatal(err)
+		}
+		paths, err := ReadDirDepth(root, -1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(paths) != 10 {
+			t.Errorf("ReadDirDepth with symlink loop returned %d paths, want 10: %q", len(paths), paths)
+		}
+	}
+}
+
 func benchmarkReaddirname(path string, b *testing.B) {
 	var nentries int
 	for i := 0; i < b.N; i++ {
@@ -613,6 +714,193 @@ func TestReaddirnamesOneAtATime(t *testing.T) {
 	}
 }
 
+func TestFileDirEntries(t *testing.T) {
+	dir := t.TempDir()
+	const n = 600 // more than one batch
+	for i := 0; i < n; i++ {
+		if err := WriteFile(filepath.Join(dir, fmt.Sprintf("f%03d", i)), []byte("x"), 0666); err != nil {
+			t.Fatal(err)
+		}
+	}
+	f, err := Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	seen := make(map[string]bool)
+	f.DirEntries()(func(e DirEntry, err error) bool {
+		if err != nil {
+			t.Fatalf("DirEntries: %v", err)
+		}
+		if seen[e.Name()] {
+			t.Errorf("DirEntries: %s seen twice", e.Name())
+		}
+		seen[e.Name()] = true
+		return true
+	})
+	if len(seen) != n {
+		t.Errorf("DirEntries yielded %d entries, want %d", len(seen), n)
+	}
+
+	// Stopping early leaves the rest for later reads.
+	f2, err := Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f2.Close()
+	count := 0
+	f2.DirEntries()(func(e DirEntry, err error) bool {
+		count++
+		return count < 10
+	})
+	if count != 10 {
+		t.Errorf("DirEntries called yield %d times after stop, want 10", count)
+	}
+	rest, err := f2.ReadDir(-1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rest) == 0 || len(rest) >= n {
+		t.Errorf("ReadDir after stopping returned %d entries", len(rest))
+	}
+
+	// A read error is yielded with a nil entry.
+	reg, err := Open(filepath.Join(dir, "f000"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reg.Close()
+	var errs int
+	reg.DirEntries()(func(e DirEntry, err error) bool {
+		if err == nil || e != nil {
+			t.Errorf("DirEntries of file: got %v, %v; want nil, error", e, err)
+		}
+		errs++
+		return true
+	})
+	if errs != 1 {
+		t.Errorf("DirEntries of file yielded %d times, want 1", errs)
+	}
+}
+
+func TestDirEntries(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"a", "b", "c"} {
+		if err := WriteFile(filepath.Join(dir, name), nil, 0666); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var names []string
+	DirEntries(dir)(func(e DirEntry, err error) bool {
+		if err != nil {
+			t.Fatalf("DirEntries: %v", err)
+		}
+		names = append(names, e.Name())
+		return true
+	})
+	sort.Strings(names)
+	if want := []string{"a", "b", "c"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("DirEntries = %q, want %q", names, want)
+	}
+
+	// Stopping early still closes the directory, so it can be removed
+	// even on systems that refuse to remove open directories.
+	count := 0
+	DirEntries(dir)(func(e DirEntry, err error) bool {
+		count++
+		return false
+	})
+	if count != 1 {
+		t.Errorf("DirEntries called yield %d times after stop, want 1", count)
+	}
+	if err := RemoveAll(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	var gotErr error
+	DirEntries(dir)(func(e DirEntry, err error) bool {
+		if e != nil {
+			t.Errorf("DirEntries of missing dir yielded entry %v", e)
+		}
+		gotErr = err
+		return true
+	})
+	if !IsNotExist(gotErr) {
+		t.Errorf("DirEntries of missing dir: got %v, want not-exist error", gotErr)
+	}
+}
+
+func TestCountDirEntries(t *testing.T) {
+	dir := t.TempDir()
+	for i := 0; i <= 600; i += 300 { // spans several batches
+		n, err := CountDirEntries(dir)
+		if err != nil || n != i {
+			t.Fatalf("CountDirEntries = %d, %v; want %d, nil", n, err, i)
+		}
+		for j := i; j < i+300; j++ {
+			if err := WriteFile(filepath.Join(dir, fmt.Sprint(j)), nil, 0666); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if _, err := CountDirEntries(filepath.Join(dir, "missing")); !IsNotExist(err) {
+		t.Errorf("CountDirEntries of missing dir: got %v, want not-exist error", err)
+	}
+}
+
+func TestFiles(t *testing.T) {
+	root := t.TempDir()
+	for _, name := range []string{"b/c/d", "b/e", "f", "a"} {
+		name = filepath.Join(root, filepath.FromSlash(name))
+		if err := MkdirAll(filepath.Dir(name), 0777); err != nil {
+			t.Fatal(err)
+		}
+		if err := WriteFile(name, nil, 0666); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := Mkdir(filepath.Join(root, "empty"), 0777); err != nil {
+		t.Fatal(err)
+	}
+	files := func(root string, max int) []string {
+		var paths []string
+		Files(root)(func(path string, err error) bool {
+			if err != nil {
+				t.Fatalf("Files: %v", err)
+			}
+			rel, err := filepath.Rel(root, path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			paths = append(paths, filepath.ToSlash(rel))
+			return len(paths) != max
+		})
+		return paths
+	}
+	if got, want := files(root, -1), []string{"a", "b/c/d", "b/e", "f"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Files = %q, want %q", got, want)
+	}
+	if got, want := files(root, 2), []string{"a", "b/c/d"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Files stopped after 2 = %q, want %q", got, want)
+	}
+	if got, want := files(filepath.Join(root, "f"), -1), []string{"."}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Files of regular file = %q, want %q", got, want)
+	}
+
+	var gotErr error
+	missing := filepath.Join(root, "missing")
+	Files(missing)(func(path string, err error) bool {
+		if path != missing {
+			t.Errorf("Files of missing root yielded path %q, want %q", path, missing)
+		}
+		gotErr = err
+		return true
+	})
+	if !IsNotExist(gotErr) {
+		t.Errorf("Files of missing root: got %v, want not-exist error", gotErr)
+	}
+}
+
 func TestReaddirNValues(t *testing.T) {
 	if testing.Short() {
 		t.Skip("test.short; skipping")
@@ -1473,6 +1761,61 @@ func TestChdirAndGetwd(t *testing.T) {
 	fd.Close()
 }
 
+func TestGetwdLogical(t *testing.T) {
+	testenv.MustHaveSymlink(t)
+
+	dir, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	real := filepath.Join(dir, "real")
+	link := filepath.Join(dir, "link")
+	if err := Mkdir(real, 0777); err != nil {
+		t.Fatal(err)
+	}
+	if err := Symlink(real, link); err != nil {
+		t.Fatal(err)
+	}
+
+	oldwd, err := Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Chdir(link); err != nil {
+		t.Fatal(err)
+	}
+	defer Chdir(oldwd)
+
+	for _, tt := range []struct {
+		pwd, want string
+	}{
+		{link, link},
+		{real, real},
+		{dir, ""}, // stale
+		{link + string(PathSeparator) + ".." + string(PathSeparator) + "link", ""}, // not clean
+		{"link", ""}, // relative
+		{"", ""},     // unset
+	} {
+		t.Setenv("PWD", tt.pwd)
+		got, err := GetwdLogical()
+		if err != nil {
+			t.Fatalf("PWD=%q: GetwdLogical: %v", tt.pwd, err)
+		}
+		want := tt.want
+		if want == "" {
+			fi1, err1 := Stat(got)
+			fi2, err2 := Stat(real)
+			if err1 != nil || err2 != nil || !SameFile(fi1, fi2) {
+				t.Errorf("PWD=%q: GetwdLogical = %q, want a path to %q", tt.pwd, got, real)
+			}
+			continue
+		}
+		if got != want {
+			t.Errorf("PWD=%q: GetwdLogical = %q, want %q", tt.pwd, got, want)
+		}
+	}
+}
+
 // Test that Chdir+Getwd is program-wide.
 func TestProgWideChdir(t *testing.T) {
 	const N = 10
@@ -1544,75 +1887,268 @@ func TestProgWideChdir(t *testing.T) {
 	}
 }
 
-func TestSeek(t *testing.T) {
-	f := newFile("TestSeek", t)
-	defer Remove(f.Name())
-	defer f.Close()
-
-	const data = "hello, world\n"
-	io.WriteString(f, data)
-
-	type test struct {
-		in     int64
-		whence int
-		out    int64
+func TestWithWd(t *testing.T) {
+	oldwd, err := Getwd()
+	if err != nil {
+		t.Fatal(err)
 	}
-	var tests = []test{
-		{0, io.SeekCurrent, int64(len(data))},
-		{0, io.SeekStart, 0},
-		{5, io.SeekStart, 5},
-		{0, io.SeekEnd, int64(len(data))},
-		{0, io.SeekStart, 0},
-		{-1, io.SeekEnd, int64(len(data)) - 1},
-		{1 << 33, io.SeekStart, 1 << 33},
-		{1 << 33, io.SeekEnd, 1<<33 + int64(len(data))},
+	dir := t.TempDir()
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		sub := filepath.Join(dir, fmt.Sprint(i))
+		if err := Mkdir(sub, 0777); err != nil {
+			t.Fatal(err)
+		}
+		if err := WriteFile(filepath.Join(sub, "id"), []byte(fmt.Sprint(i)), 0666); err != nil {
+			t.Fatal(err)
+		}
+		wg.Add(1)
+		go func(i int, sub string) {
+			defer wg.Done()
+			err := WithWd(sub, func() error {
+				b, err := ReadFile("id")
+				if err != nil {
+					return err
+				}
+				if string(b) != fmt.Sprint(i) {
+					return fmt.Errorf("read id %q, want %d", b, i)
+				}
+				return nil
+			})
+			if err != nil {
+				t.Errorf("WithWd(%s): %v", sub, err)
+			}
+		}(i, sub)
+	}
+	wg.Wait()
 
-		// Issue 21681, Windows 4G-1, etc:
-		{1<<32 - 1, io.SeekStart, 1<<32 - 1},
-		{0, io.SeekCurrent, 1<<32 - 1},
-		{2<<32 - 1, io.SeekStart, 2<<32 - 1},
-		{0, io.SeekCurrent, 2<<32 - 1},
+	errFn := errors.New("fn failed")
+	if err := WithWd(dir, func() error { return errFn }); err != errFn {
+		t.Errorf("WithWd returned %v, want %v", err, errFn)
 	}
-	for i, tt := range tests {
-		off, err := f.Seek(tt.in, tt.whence)
-		if off != tt.out || err != nil {
-			if e, ok := err.(*PathError); ok && e.Err == syscall.EINVAL && tt.out > 1<<32 && runtime.GOOS == "linux" {
-				mounts, _ := os.ReadFile("/proc/mounts")
-				if strings.Contains(string(mounts), "reiserfs") {
-					// Reiserfs rejects the big seeks.
-					t.Skipf("skipping test known to fail on reiserfs; https://golang.org/issue/91")
-				}
+	if err := WithWd(filepath.Join(dir, "missing"), func() error {
+		t.Error("fn called for missing directory")
+		return nil
+	}); !IsNotExist(err) {
+		t.Errorf("WithWd of missing directory: got %v, want not-exist error", err)
+	}
+	func() {
+		defer func() {
+			if r := recover(); r != "boom" {
+				t.Errorf("recovered %v, want boom", r)
 			}
-			t.Errorf("#%d: Seek(%v, %v) = %v, %v want %v, nil", i, tt.in, tt.whence, off, err, tt.out)
-		}
+		}()
+		WithWd(dir, func() error { panic("boom") })
+	}()
+
+	if wd, err := Getwd(); err != nil || wd != oldwd {
+		t.Errorf("after WithWd, Getwd = %q, %v; want %q", wd, err, oldwd)
 	}
 }
 
-func TestSeekError(t *testing.T) {
-	switch runtime.GOOS {
-	case "js", "plan9":
-		t.Skipf("skipping test on %v", runtime.GOOS)
+func TestFchdir(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("File.Chdir is not implemented on windows")
 	}
-
-	r, w, err := Pipe()
+	oldwd, err := Getwd()
 	if err != nil {
 		t.Fatal(err)
 	}
-	_, err = r.Seek(0, 0)
-	if err == nil {
-		t.Fatal("Seek on pipe should fail")
+	dir := t.TempDir()
+	a := filepath.Join(dir, "a")
+	if err := Mkdir(a, 0777); err != nil {
+		t.Fatal(err)
 	}
-	if perr, ok := err.(*PathError); !ok || perr.Err != syscall.ESPIPE {
-		t.Errorf("Seek returned error %v, want &PathError{Err: syscall.ESPIPE}", err)
+	if err := WriteFile(filepath.Join(a, "id"), []byte("a"), 0666); err != nil {
+		t.Fatal(err)
 	}
-	_, err = w.Seek(0, 0)
-	if err == nil {
-		t.Fatal("Seek on pipe should fail")
+	h, err := OpenPath(a)
+	if err != nil {
+		t.Fatal(err)
 	}
-	if perr, ok := err.(*PathError); !ok || perr.Err != syscall.ESPIPE {
-		t.Errorf("Seek returned error %v, want &PathError{Err: syscall.ESPIPE}", err)
+	defer h.Close()
+	// The handle follows the directory when it is renamed.
+	b := filepath.Join(dir, "b")
+	if err := Rename(a, b); err != nil {
+		t.Fatal(err)
 	}
-}
+
+	err = WithWd(dir, func() error {
+		if err := Fchdir(h); err != nil {
+			return err
+		}
+		if data, err := ReadFile("id"); err != nil || string(data) != "a" {
+			return fmt.Errorf("ReadFile(id) = %q, %v; want %q", data, err, "a")
+		}
+		wd, err := Stat(".")
+		if err != nil {
+			return err
+		}
+		want, err := Stat(b)
+		if err != nil {
+			return err
+		}
+		if !SameFile(wd, want) {
+			return errors.New("working directory is not the renamed directory")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Errorf("Fchdir within WithWd: %v", err)
+	}
+	if wd, err := Getwd(); err != nil || wd != oldwd {
+		t.Errorf("after WithWd, Getwd = %q, %v; want %q", wd, err, oldwd)
+	}
+}
+
+func TestOpenInPath(t *testing.T) {
+	dir := t.TempDir()
+	a := filepath.Join(dir, "a")
+	b := filepath.Join(dir, "b")
+	c := filepath.Join(dir, "c")
+	for _, d := range []string{a, b, c, filepath.Join(a, "app.conf")} {
+		if err := Mkdir(d, 0777); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, d := range []string{b, c} {
+		if err := WriteFile(filepath.Join(d, "app.conf"), []byte(d), 0666); err != nil {
+			t.Fatal(err)
+		}
+	}
+	dirs := []string{filepath.Join(dir, "missing"), a, b, c}
+
+	f, found, err := OpenInPath("app.conf", dirs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := io.ReadAll(f)
+	f.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if found != b || string(data) != b {
+		t.Errorf("OpenInPath found %q containing %q; want %q", found, data, b)
+	}
+
+	path, err := FindInPath("app.conf", dirs)
+	if want := filepath.Join(b, "app.conf"); err != nil || path != want {
+		t.Errorf("FindInPath = %q, %v; want %q", path, err, want)
+	}
+
+	if _, _, err := OpenInPath("other.conf", dirs); !errors.Is(err, fs.ErrNotExist) || !strings.Contains(err.Error(), c) {
+		t.Errorf("OpenInPath of missing file: got %v, want not-exist error listing %s", err, c)
+	}
+	if _, err := FindInPath("other.conf", dirs); !errors.Is(err, fs.ErrNotExist) || !strings.Contains(err.Error(), a) {
+		t.Errorf("FindInPath of missing file: got %v, want not-exist error listing %s", err, a)
+	}
+}
+
+func TestLookPathAll(t *testing.T) {
+	switch runtime.GOOS {
+	case "windows", "plan9":
+		t.Skipf("test uses Unix path search rules, not those of %s", runtime.GOOS)
+	}
+	dir := t.TempDir()
+	var dirs []string
+	for i, perm := range []FileMode{0755, 0644, 0755} {
+		d := filepath.Join(dir, fmt.Sprint(i))
+		if err := Mkdir(d, 0777); err != nil {
+			t.Fatal(err)
+		}
+		if err := WriteFile(filepath.Join(d, "prog"), nil, perm); err != nil {
+			t.Fatal(err)
+		}
+		dirs = append(dirs, d)
+	}
+	t.Setenv("PATH", strings.Join([]string{dirs[0], dirs[1], filepath.Join(dir, "missing"), dirs[2], dirs[0]}, string(PathListSeparator)))
+
+	paths, err := LookPathAll("prog")
+	want := []string{filepath.Join(dirs[0], "prog"), filepath.Join(dirs[2], "prog")}
+	if err != nil || !reflect.DeepEqual(paths, want) {
+		t.Errorf("LookPathAll(prog) = %q, %v; want %q", paths, err, want)
+	}
+
+	paths, err = LookPathAll("other")
+	if !errors.Is(err, fs.ErrNotExist) || paths == nil || len(paths) != 0 {
+		t.Errorf("LookPathAll(other) = %#v, %v; want empty slice and not-exist error", paths, err)
+	}
+
+	direct := filepath.Join(dirs[1], "prog")
+	if paths, err := LookPathAll(direct); !errors.Is(err, fs.ErrPermission) {
+		t.Errorf("LookPathAll(%s) = %q, %v; want permission error", direct, paths, err)
+	}
+}
+
+func TestSeek(t *testing.T) {
+	f := newFile("TestSeek", t)
+	defer Remove(f.Name())
+	defer f.Close()
+
+	const data = "hello, world\n"
+	io.WriteString(f, data)
+
+	type test struct {
+		in     int64
+		whence int
+		out    int64
+	}
+	var tests = []test{
+		{0, io.SeekCurrent, int64(len(data))},
+		{0, io.SeekStart, 0},
+		{5, io.SeekStart, 5},
+		{0, io.SeekEnd, int64(len(data))},
+		{0, io.SeekStart, 0},
+		{-1, io.SeekEnd, int64(len(data)) - 1},
+		{1 << 33, io.SeekStart, 1 << 33},
+		{1 << 33, io.SeekEnd, 1<<33 + int64(len(data))},
+
+		// Issue 21681, Windows 4G-1, etc:
+		{1<<32 - 1, io.SeekStart, 1<<32 - 1},
+		{0, io.SeekCurrent, 1<<32 - 1},
+		{2<<32 - 1, io.SeekStart, 2<<32 - 1},
+		{0, io.SeekCurrent, 2<<32 - 1},
+	}
+	for i, tt := range tests {
+		off, err := f.Seek(tt.in, tt.whence)
+		if off != tt.out || err != nil {
+			if e, ok := err.(*PathError); ok && e.Err == syscall.EINVAL && tt.out > 1<<32 && runtime.GOOS == "linux" {
+				mounts, _ := os.ReadFile("/proc/mounts")
+				if strings.Contains(string(mounts), "reiserfs") {
+					// Reiserfs rejects the big seeks.
+					t.Skipf("skipping test known to fail on reiserfs; https://golang.org/issue/91")
+				}
+			}
+			t.Errorf("#%d: Seek(%v, %v) = %v, %v want %v, nil", i, tt.in, tt.whence, off, err, tt.out)
+		}
+	}
+}
+
+func TestSeekError(t *testing.T) {
+	switch runtime.GOOS {
+	case "js", "plan9":
+		t.Skipf("skipping test on %v", runtime.GOOS)
+	}
+
+	r, w, err := Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = r.Seek(0, 0)
+	if err == nil {
+		t.Fatal("Seek on pipe should fail")
+	}
+	if perr, ok := err.(*PathError); !ok || perr.Err != syscall.ESPIPE {
+		t.Errorf("Seek returned error %v, want &PathError{Err: syscall.ESPIPE}", err)
+	}
+	_, err = w.Seek(0, 0)
+	if err == nil {
+		t.Fatal("Seek on pipe should fail")
+	}
+	if perr, ok := err.(*PathError); !ok || perr.Err != syscall.ESPIPE {
+		t.Errorf("Seek returned error %v, want &PathError{Err: syscall.ESPIPE}", err)
+	}
+}
 
 type openErrorTest struct {
 	path  string
@@ -2426,120 +2962,618 @@ func mkdirTree(t *testing.T, root string, level, max int) {
 
 // Test that simultaneous RemoveAll do not report an error.
 // As long as it gets removed, we should be happy.
-func TestRemoveAllRace(t *testing.T) {
-	if runtime.GOOS == "windows" {
-		// Windows has very strict rules about things like
-		// removing directories while someone else has
-		// them open. The racing doesn't work out nicely
-		// like it does on Unix.
-		t.Skip("skipping on windows")
+func TestRemoveRetry(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "file")
+	if err := WriteFile(name, nil, 0666); err != nil {
+		t.Fatal(err)
+	}
+	if err := RemoveRetry(name, 3, time.Millisecond); err != nil {
+		t.Fatalf("RemoveRetry: %v", err)
+	}
+	if _, err := Lstat(name); !IsNotExist(err) {
+		t.Fatalf("Lstat after RemoveRetry: got %v, want not-exist error", err)
 	}
 
-	n := runtime.GOMAXPROCS(16)
-	defer runtime.GOMAXPROCS(n)
-	root, err := os.MkdirTemp("", "issue")
-	if err != nil {
-		t.Fatal(err)
+	// A missing file is not retried, so the long backoff is not waited for.
+	start := time.Now()
+	if err := RemoveRetry(name, 3, time.Hour); !IsNotExist(err) {
+		t.Errorf("RemoveRetry of missing file: got %v, want not-exist error", err)
 	}
-	mkdirTree(t, root, 1, 6)
-	hold := make(chan struct{})
-	var wg sync.WaitGroup
-	for i := 0; i < 4; i++ {
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			<-hold
-			err := RemoveAll(root)
-			if err != nil {
-				t.Errorf("unexpected error: %T, %q", err, err)
-			}
-		}()
+	if d := time.Since(start); d > time.Minute {
+		t.Errorf("RemoveRetry of missing file took %v", d)
 	}
-	close(hold) // let workers race to remove root
-	wg.Wait()
 }
 
-// Test that reading from a pipe doesn't use up a thread.
-func TestPipeThreads(t *testing.T) {
-	switch runtime.GOOS {
-	case "freebsd":
-		t.Skip("skipping on FreeBSD; issue 19093")
-	case "illumos", "solaris":
-		t.Skip("skipping on Solaris and illumos; issue 19111")
-	case "windows":
-		t.Skip("skipping on Windows; issue 19098")
-	case "plan9":
-		t.Skip("skipping on Plan 9; does not support runtime poller")
-	case "js":
-		t.Skip("skipping on js; no support for os.Pipe")
+func TestRemoveOnReboot(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "file")
+	if err := WriteFile(name, nil, 0666); err != nil {
+		t.Fatal(err)
 	}
+	if err := RemoveOnReboot(name); err != nil {
+		t.Fatalf("RemoveOnReboot: %v", err)
+	}
+	if _, err := Lstat(name); !IsNotExist(err) {
+		t.Fatalf("Lstat after RemoveOnReboot: got %v, want not-exist error", err)
+	}
+	if err := RemoveOnReboot(name); !IsNotExist(err) {
+		t.Errorf("RemoveOnReboot of missing file: got %v, want not-exist error", err)
+	}
+}
 
-	threads := 100
+func TestIsInUse(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "file")
+	if err := WriteFile(name, nil, 0666); err != nil {
+		t.Fatal(err)
+	}
+	inUse, err := IsInUse(name)
+	if errors.Is(err, ErrUnsupported) {
+		t.Skipf("IsInUse: %v", err)
+	}
+	if err != nil || inUse {
+		t.Fatalf("IsInUse of closed file = %v, %v; want false, nil", inUse, err)
+	}
+	f, err := Open(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	inUse, err = IsInUse(name)
+	f.Close()
+	if err != nil || !inUse {
+		t.Errorf("IsInUse of open file = %v, %v; want true, nil", inUse, err)
+	}
+	if _, err := IsInUse(filepath.Join(t.TempDir(), "missing")); !IsNotExist(err) {
+		t.Errorf("IsInUse of missing file: got %v, want not-exist error", err)
+	}
+}
 
-	// OpenBSD has a low default for max number of files.
-	if runtime.GOOS == "openbsd" {
-		threads = 50
+func TestReopen(t *testing.T) {
+	dir := t.TempDir()
+	name := filepath.Join(dir, "file")
+	f, err := Create(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if _, err := f.WriteString("hello"); err != nil {
+		t.Fatal(err)
 	}
 
-	r := make([]*File, threads)
-	w := make([]*File, threads)
-	for i := 0; i < threads; i++ {
-		rp, wp, err := Pipe()
+	check := func(op string) {
+		t.Helper()
+		r, err := f.Reopen(O_RDONLY)
 		if err != nil {
-			for j := 0; j < i; j++ {
-				r[j].Close()
-				w[j].Close()
-			}
-			t.Fatal(err)
+			t.Fatalf("Reopen %s: %v", op, err)
+		}
+		defer r.Close()
+		if r.Name() != f.Name() {
+			t.Errorf("Reopen %s: Name = %q, want %q", op, r.Name(), f.Name())
+		}
+		b, err := io.ReadAll(r)
+		if err != nil || string(b) != "hello" {
+			t.Errorf("Reopen %s: read %q, %v; want %q", op, b, err, "hello")
+		}
+		if _, err := r.Write([]byte("x")); err == nil {
+			t.Errorf("Reopen %s: Write to read-only file succeeded", op)
 		}
-		r[i] = rp
-		w[i] = wp
 	}
+	check("")
 
-	defer debug.SetMaxThreads(debug.SetMaxThreads(threads / 2))
-
-	creading := make(chan bool, threads)
-	cdone := make(chan bool, threads)
-	for i := 0; i < threads; i++ {
-		go func(i int) {
-			var b [1]byte
-			creading <- true
-			if _, err := r[i].Read(b[:]); err != nil {
-				t.Error(err)
-			}
-			if err := r[i].Close(); err != nil {
-				t.Error(err)
-			}
-			cdone <- true
-		}(i)
+	if runtime.GOOS == "linux" {
+		// The file is reopened even when its name is gone.
+		if err := Rename(name, name+".new"); err != nil {
+			t.Fatal(err)
+		}
+		check("after rename")
+		if err := Remove(name + ".new"); err != nil {
+			t.Fatal(err)
+		}
+		check("after remove")
 	}
 
-	for i := 0; i < threads; i++ {
-		<-creading
+	f.Close()
+	if _, err := f.Reopen(O_RDONLY); err == nil {
+		t.Error("Reopen of closed file succeeded")
 	}
+}
 
-	// If we are still alive, it means that the 100 goroutines did
-	// not require 100 threads.
-
-	for i := 0; i < threads; i++ {
-		if _, err := w[i].Write([]byte{0}); err != nil {
-			t.Error(err)
-		}
-		if err := w[i].Close(); err != nil {
-			t.Error(err)
-		}
-		<-cdone
+func TestOpenTail(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "log")
+	if err := WriteFile(name, []byte("old\n"), 0666); err != nil {
+		t.Fatal(err)
+	}
+	f, err := OpenTail(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	appendFile(t, name, "new\n")
+	b, err := io.ReadAll(f)
+	if err != nil || string(b) != "new\n" {
+		t.Errorf("read %q, %v; want %q", b, err, "new\n")
 	}
 }
 
-func testDoubleCloseError(t *testing.T, path string) {
-	file, err := Open(path)
+func appendFile(t *testing.T, name, data string) {
+	t.Helper()
+	f, err := OpenFile(name, O_WRONLY|O_APPEND|O_CREATE, 0666)
 	if err != nil {
 		t.Fatal(err)
 	}
-	if err := file.Close(); err != nil {
-		t.Fatalf("unexpected error from Close: %v", err)
-	}
+	defer f.Close()
+	if _, err := f.WriteString(data); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTail(t *testing.T) {
+	defer func(d time.Duration) { *TailPollInterval = d }(*TailPollInterval)
+	*TailPollInterval = time.Millisecond
+
+	name := filepath.Join(t.TempDir(), "log")
+	if err := WriteFile(name, []byte("old\n"), 0666); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	c, tailErr, err := Tail(ctx, name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := func(want string) {
+		t.Helper()
+		select {
+		case line := <-c:
+			if string(line) != want {
+				t.Fatalf("Tail sent %q, want %q", line, want)
+			}
+		case <-time.After(10 * time.Second):
+			t.Fatalf("timed out waiting for %q", want)
+		}
+	}
+
+	appendFile(t, name, "a\nb")
+	expect("a\n")
+	appendFile(t, name, "c\n")
+	expect("bc\n")
+
+	// Truncation restarts from the beginning.
+	if err := Truncate(name, 0); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(50 * time.Millisecond)
+	appendFile(t, name, "d\n")
+	expect("d\n")
+
+	if runtime.GOOS != "windows" { // open files cannot be renamed
+		// Rotation finishes the old file, then follows the new one.
+		if err := Rename(name, name+".1"); err != nil {
+			t.Fatal(err)
+		}
+		appendFile(t, name+".1", "e\n")
+		appendFile(t, name, "f\n")
+		expect("e\n")
+		expect("f\n")
+	}
+
+	cancel()
+	for range c {
+	}
+	if err := tailErr(); err != context.Canceled {
+		t.Errorf("Tail stopped with %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestTailError(t *testing.T) {
+	// A directory can be opened, but not read as a file.
+	c, tailErr, err := Tail(context.Background(), t.TempDir())
+	if err != nil {
+		t.Skipf("Tail of a directory: %v", err)
+	}
+	select {
+	case _, ok := <-c:
+		if ok {
+			t.Fatal("Tail of a directory sent a line")
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("timed out waiting for Tail of a directory to fail")
+	}
+	var pe *PathError
+	if err := tailErr(); !errors.As(err, &pe) {
+		t.Errorf("Tail of a directory stopped with %v, want a *PathError", err)
+	}
+}
+
+func TestOpenOrCreate(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "file")
+	f, created, err := OpenOrCreate(name, 0666)
+	if err != nil || !created {
+		t.Fatalf("OpenOrCreate of new file: created = %v, err = %v; want true, nil", created, err)
+	}
+	if _, err := f.WriteString("data"); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	f, created, err = OpenOrCreate(name, 0666)
+	if err != nil || created {
+		t.Fatalf("OpenOrCreate of existing file: created = %v, err = %v; want false, nil", created, err)
+	}
+	b, err := io.ReadAll(f)
+	f.Close()
+	if err != nil || string(b) != "data" {
+		t.Errorf("existing file contains %q, %v; want %q", b, err, "data")
+	}
+
+	// Only one of several concurrent callers creates the file.
+	name = filepath.Join(t.TempDir(), "race")
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	n := 0
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			f, created, err := OpenOrCreate(name, 0666)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			f.Close()
+			if created {
+				mu.Lock()
+				n++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	if n != 1 {
+		t.Errorf("%d callers created the file, want 1", n)
+	}
+
+	if _, _, err := OpenOrCreate(filepath.Join(name, "sub"), 0666); err == nil {
+		t.Error("OpenOrCreate under a file succeeded")
+	}
+
+	// A dangling symbolic link is reported, not retried forever.
+	if testenv.HasSymlink() {
+		link := filepath.Join(t.TempDir(), "link")
+		if err := Symlink("missing", link); err != nil {
+			t.Fatal(err)
+		}
+		if _, _, err := OpenOrCreate(link, 0666); !IsNotExist(err) {
+			t.Errorf("OpenOrCreate of dangling symlink: got %v, want not-exist error", err)
+		}
+	}
+}
+
+func TestRotatingFile(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "log")
+	if err := WriteFile(name, []byte("old\n"), 0666); err != nil {
+		t.Fatal(err)
+	}
+	r, err := OpenRotating(name, 10, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range []string{"a1234\n", "b1234\n", "c1234\n", "d1234\n", "e\n", "f\n"} {
+		if _, err := r.Write([]byte(line)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := r.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Write([]byte("x")); !errors.Is(err, ErrClosed) {
+		t.Errorf("Write after Close: got %v, want ErrClosed", err)
+	}
+
+	// "old\na1234\n" fits exactly; each later 6-byte line starts a new
+	// file until "d1234\ne\nf\n", and only two backups are kept.
+	for file, want := range map[string]string{
+		name:        "d1234\ne\nf\n",
+		name + ".1": "c1234\n",
+		name + ".2": "b1234\n",
+	} {
+		b, err := ReadFile(file)
+		if err != nil || string(b) != want {
+			t.Errorf("%s contains %q, %v; want %q", file, b, err, want)
+		}
+	}
+	if _, err := Stat(name + ".3"); !IsNotExist(err) {
+		t.Errorf("Stat of third backup: got %v, want not-exist error", err)
+	}
+}
+
+func TestRotatingFileCompression(t *testing.T) {
+	gz := func(dst io.Writer, src io.Reader) error {
+		zw := gzip.NewWriter(dst)
+		if _, err := io.Copy(zw, src); err != nil {
+			return err
+		}
+		return zw.Close()
+	}
+	gunzip := func(name string) string {
+		t.Helper()
+		f, err := Open(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+		zr, err := gzip.NewReader(f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := io.ReadAll(zr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(b)
+	}
+
+	name := filepath.Join(t.TempDir(), "log")
+	r, err := OpenRotating(name, 6, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.SetCompression(".gz", gz)
+	for _, line := range []string{"a1234\n", "b1234\n", "c1234\n", "d1234\n"} {
+		if _, err := r.Write([]byte(line)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := r.Close(); err != nil { // waits for compression
+		t.Fatal(err)
+	}
+	if got := gunzip(name + ".1.gz"); got != "c1234\n" {
+		t.Errorf("%s.1.gz contains %q, want %q", name, got, "c1234\n")
+	}
+	if got := gunzip(name + ".2.gz"); got != "b1234\n" {
+		t.Errorf("%s.2.gz contains %q, want %q", name, got, "b1234\n")
+	}
+	for _, gone := range []string{".1", ".2", ".3", ".3.gz"} {
+		if _, err := Stat(name + gone); !IsNotExist(err) {
+			t.Errorf("Stat(%s%s): got %v, want not-exist error", name, gone, err)
+		}
+	}
+
+	// A failed compression leaves the backup as it was.
+	name = filepath.Join(t.TempDir(), "log")
+	r, err = OpenRotating(name, 6, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.SetCompression(".gz", func(io.Writer, io.Reader) error { return errors.New("fail") })
+	for _, line := range []string{"a1234\n", "b1234\n"} {
+		if _, err := r.Write([]byte(line)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := r.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if b, err := ReadFile(name + ".1"); err != nil || string(b) != "a1234\n" {
+		t.Errorf("%s.1 contains %q, %v; want %q", name, b, err, "a1234\n")
+	}
+	if entries, err := ReadDir(filepath.Dir(name)); err != nil || len(entries) != 2 {
+		t.Errorf("directory has %d entries, %v; want 2", len(entries), err)
+	}
+
+	// Writes and rotations do not wait for compression.
+	name = filepath.Join(t.TempDir(), "log")
+	r, err = OpenRotating(name, 6, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	release := make(chan struct{})
+	r.SetCompression(".gz", func(dst io.Writer, src io.Reader) error {
+		<-release
+		return gz(dst, src)
+	})
+	wrote := make(chan error)
+	go func() {
+		for _, line := range []string{"a1234\n", "b1234\n", "c1234\n", "d1234\n", "e1234\n"} {
+			if _, err := r.Write([]byte(line)); err != nil {
+				wrote <- err
+				return
+			}
+		}
+		wrote <- nil
+	}()
+	select {
+	case err := <-wrote:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("Write blocked while a backup was compressed")
+	}
+	close(release)
+	if err := r.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if got := gunzip(name + ".1.gz"); got != "d1234\n" {
+		t.Errorf("%s.1.gz contains %q, want %q", name, got, "d1234\n")
+	}
+	if got := gunzip(name + ".2.gz"); got != "c1234\n" {
+		t.Errorf("%s.2.gz contains %q, want %q", name, got, "c1234\n")
+	}
+	if entries, err := ReadDir(filepath.Dir(name)); err != nil || len(entries) != 3 {
+		t.Errorf("directory has %d entries, %v; want 3", len(entries), err)
+	}
+}
+
+func TestRotatingFileTime(t *testing.T) {
+	dir := t.TempDir()
+	name := filepath.Join(dir, "log")
+	write := func(name, data string, mtime time.Time) {
+		t.Helper()
+		if err := WriteFile(name, []byte(data), 0666); err != nil {
+			t.Fatal(err)
+		}
+		if err := Chtimes(name, mtime, mtime); err != nil {
+			t.Fatal(err)
+		}
+	}
+	yesterday := time.Now().Add(-30 * time.Hour)
+	ancient := time.Date(2000, 1, 1, 12, 0, 0, 0, time.UTC)
+	write(name, "old\n", yesterday)
+	write(name+"-2000-01-01", "ancient\n", ancient)
+	write(name+"-notes", "unrelated\n", ancient)
+
+	r, err := OpenRotatingTime(name, 24*time.Hour, 48*time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	// The file was last written in an earlier day, so the
+	// first write rotates it.
+	if _, err := r.Write([]byte("new\n")); err != nil {
+		t.Fatal(err)
+	}
+	backup := name + "-" + yesterday.UTC().Format("2006-01-02")
+	for file, want := range map[string]string{name: "new\n", backup: "old\n"} {
+		b, err := ReadFile(file)
+		if err != nil || string(b) != want {
+			t.Errorf("%s contains %q, %v; want %q", file, b, err, want)
+		}
+	}
+
+	// Backups older than maxAge are removed in the background,
+	// and other files are left alone.
+	for i := 0; ; i++ {
+		if _, err := Stat(name + "-2000-01-01"); IsNotExist(err) {
+			break
+		}
+		if i == 1000 {
+			t.Fatal("old backup not removed")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if _, err := Stat(name + "-notes"); err != nil {
+		t.Errorf("unrelated file: %v", err)
+	}
+
+	if _, err := OpenRotatingTime(name, 0, 0); err == nil {
+		t.Error("OpenRotatingTime with zero interval succeeded")
+	}
+}
+
+func TestRemoveAllRace(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		// Windows has very strict rules about things like
+		// removing directories while someone else has
+		// them open. The racing doesn't work out nicely
+		// like it does on Unix.
+		t.Skip("skipping on windows")
+	}
+
+	n := runtime.GOMAXPROCS(16)
+	defer runtime.GOMAXPROCS(n)
+	root, err := os.MkdirTemp("", "issue")
+	if err != nil {
+		t.Fatal(err)
+	}
+	mkdirTree(t, root, 1, 6)
+	hold := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-hold
+			err := RemoveAll(root)
+			if err != nil {
+				t.Errorf("unexpected error: %T, %q", err, err)
+			}
+		}()
+	}
+	close(hold) // let workers race to remove root
+	wg.Wait()
+}
+
+// Test that reading from a pipe doesn't use up a thread.
+func TestPipeThreads(t *testing.T) {
+	switch runtime.GOOS {
+	case "freebsd":
+		t.Skip("skipping on FreeBSD; issue 19093")
+	case "illumos", "solaris":
+		t.Skip("skipping on Solaris and illumos; issue 19111")
+	case "windows":
+		t.Skip("skipping on Windows; issue 19098")
+	case "plan9":
+		t.Skip("skipping on Plan 9; does not support runtime poller")
+	case "js":
+		t.Skip("skipping on js; no support for os.Pipe")
+	}
+
+	threads := 100
+
+	// OpenBSD has a low default for max number of files.
+	if runtime.GOOS == "openbsd" {
+		threads = 50
+	}
+
+	r := make([]*File, threads)
+	w := make([]*File, threads)
+	for i := 0; i < threads; i++ {
+		rp, wp, err := Pipe()
+		if err != nil {
+			for j := 0; j < i; j++ {
+				r[j].Close()
+				w[j].Close()
+			}
+			t.Fatal(err)
+		}
+		r[i] = rp
+		w[i] = wp
+	}
+
+	defer debug.SetMaxThreads(debug.SetMaxThreads(threads / 2))
+
+	creading := make(chan bool, threads)
+	cdone := make(chan bool, threads)
+	for i := 0; i < threads; i++ {
+		go func(i int) {
+			var b [1]byte
+			creading <- true
+			if _, err := r[i].Read(b[:]); err != nil {
+				t.Error(err)
+			}
+			if err := r[i].Close(); err != nil {
+				t.Error(err)
+			}
+			cdone <- true
+		}(i)
+	}
+
+	for i := 0; i < threads; i++ {
+		<-creading
+	}
+
+	// If we are still alive, it means that the 100 goroutines did
+	// not require 100 threads.
+
+	for i := 0; i < threads; i++ {
+		if _, err := w[i].Write([]byte{0}); err != nil {
+			t.Error(err)
+		}
+		if err := w[i].Close(); err != nil {
+			t.Error(err)
+		}
+		<-cdone
+	}
+}
+
+func testDoubleCloseError(t *testing.T, path string) {
+	file, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := file.Close(); err != nil {
+		t.Fatalf("unexpected error from Close: %v", err)
+	}
 	if err := file.Close(); err == nil {
 		t.Error("second Close did not fail")
 	} else if pe, ok := err.(*PathError); !ok {
@@ -2551,243 +3585,1578 @@ func testDoubleCloseError(t *testing.T, path string) {
 	}
 }
 
-func TestDoubleCloseError(t *testing.T) {
-	testDoubleCloseError(t, filepath.Join(sfdir, sfname))
-	testDoubleCloseError(t, sfdir)
+func TestDoubleCloseError(t *testing.T) {
+	testDoubleCloseError(t, filepath.Join(sfdir, sfname))
+	testDoubleCloseError(t, sfdir)
+}
+
+func TestUserHomeDir(t *testing.T) {
+	dir, err := UserHomeDir()
+	if dir == "" && err == nil {
+		t.Fatal("UserHomeDir returned an empty string but no error")
+	}
+	if err != nil {
+		t.Skipf("UserHomeDir failed: %v", err)
+	}
+	fi, err := Stat(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !fi.IsDir() {
+		t.Fatalf("dir %s is not directory; type = %v", dir, fi.Mode())
+	}
+}
+
+func TestExpandTilde(t *testing.T) {
+	home, err := UserHomeDir()
+	if err != nil {
+		t.Skipf("UserHomeDir failed: %v", err)
+	}
+	sep := string(PathSeparator)
+	tests := []struct {
+		path, want string
+	}{
+		{"", ""},
+		{"~", home},
+		{"~" + sep + "x", home + sep + "x"},
+		{"a~b", "a~b"},
+		{sep + "~", sep + "~"},
+	}
+	for _, tt := range tests {
+		got, err := ExpandTilde(tt.path)
+		if err != nil || got != tt.want {
+			t.Errorf("ExpandTilde(%q) = %q, %v; want %q, nil", tt.path, got, err, tt.want)
+		}
+	}
+	if _, err := ExpandTilde("~no-such-user-for-go-test/x"); err == nil {
+		t.Error("ExpandTilde of unknown user succeeded")
+	}
+	if runtime.GOOS == "linux" {
+		if _, err := Stat("/etc/passwd"); err == nil {
+			got, err := ExpandTilde("~root/x")
+			if err != nil || !strings.HasSuffix(got, "/x") || got == "~root/x" {
+				t.Errorf("ExpandTilde(~root/x) = %q, %v", got, err)
+			}
+		}
+	}
+}
+
+func TestDirSeek(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		testenv.SkipFlaky(t, 36019)
+	}
+	wd, err := Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := Open(wd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dirnames1, err := f.Readdirnames(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ret, err := f.Seek(0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ret != 0 {
+		t.Fatalf("seek result not zero: %d", ret)
+	}
+
+	dirnames2, err := f.Readdirnames(0)
+	if err != nil {
+		t.Fatal(err)
+		return
+	}
+
+	if len(dirnames1) != len(dirnames2) {
+		t.Fatalf("listings have different lengths: %d and %d\n", len(dirnames1), len(dirnames2))
+	}
+	for i, n1 := range dirnames1 {
+		n2 := dirnames2[i]
+		if n1 != n2 {
+			t.Fatalf("different name i=%d n1=%s n2=%s\n", i, n1, n2)
+		}
+	}
+}
+
+func TestReaddirSmallSeek(t *testing.T) {
+	// See issue 37161. Read only one entry from a directory,
+	// seek to the beginning, and read again. We should not see
+	// duplicate entries.
+	if runtime.GOOS == "windows" {
+		testenv.SkipFlaky(t, 36019)
+	}
+	wd, err := Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	df, err := Open(filepath.Join(wd, "testdata", "issue37161"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	names1, err := df.Readdirnames(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = df.Seek(0, 0); err != nil {
+		t.Fatal(err)
+	}
+	names2, err := df.Readdirnames(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(names2) != 3 {
+		t.Fatalf("first names: %v, second names: %v", names1, names2)
+	}
+}
+
+// isDeadlineExceeded reports whether err is or wraps os.ErrDeadlineExceeded.
+// We also check that the error has a Timeout method that returns true.
+func isDeadlineExceeded(err error) bool {
+	if !IsTimeout(err) {
+		return false
+	}
+	if !errors.Is(err, ErrDeadlineExceeded) {
+		return false
+	}
+	return true
+}
+
+// Test that opening a file does not change its permissions.  Issue 38225.
+func TestOpenFileKeepsPermissions(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	name := filepath.Join(dir, "x")
+	f, err := Create(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Error(err)
+	}
+	f, err = OpenFile(name, O_WRONLY|O_CREATE|O_TRUNC, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fi, err := f.Stat(); err != nil {
+		t.Error(err)
+	} else if fi.Mode()&0222 == 0 {
+		t.Errorf("f.Stat.Mode after OpenFile is %v, should be writable", fi.Mode())
+	}
+	if err := f.Close(); err != nil {
+		t.Error(err)
+	}
+	if fi, err := Stat(name); err != nil {
+		t.Error(err)
+	} else if fi.Mode()&0222 == 0 {
+		t.Errorf("Stat after OpenFile is %v, should be writable", fi.Mode())
+	}
+}
+
+func TestDirFS(t *testing.T) {
+	// On Windows, we force the MFT to update by reading the actual metadata from GetFileInformationByHandle and then
+	// explicitly setting that. Otherwise it might get out of sync with FindFirstFile. See golang.org/issues/42637.
+	if runtime.GOOS == "windows" {
+		if err := filepath.WalkDir("./testdata/dirfs", func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				t.Fatal(err)
+			}
+			info, err := d.Info()
+			if err != nil {
+				t.Fatal(err)
+			}
+			stat, err := Stat(path) // This uses GetFileInformationByHandle internally.
+			if err != nil {
+				t.Fatal(err)
+			}
+			if stat.ModTime() == info.ModTime() {
+				return nil
+			}
+			if err := Chtimes(path, stat.ModTime(), stat.ModTime()); err != nil {
+				t.Log(err) // We only log, not die, in case the test directory is not writable.
+			}
+			return nil
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := fstest.TestFS(DirFS("./testdata/dirfs"), "a", "b", "dir/x"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Test that Open does not accept backslash as separator.
+	d := DirFS(".")
+	_, err := d.Open(`testdata\dirfs`)
+	if err == nil {
+		t.Fatalf(`Open testdata\dirfs succeeded`)
+	}
+}
+
+func TestDirFSPathsValid(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skipf("skipping on Windows")
+	}
+
+	d := t.TempDir()
+	if err := os.WriteFile(filepath.Join(d, "control.txt"), []byte(string("Hello, world!")), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(d, `e:xperi\ment.txt`), []byte(string("Hello, colon and backslash!")), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	fsys := os.DirFS(d)
+	err := fs.WalkDir(fsys, ".", func(path string, e fs.DirEntry, err error) error {
+		if fs.ValidPath(e.Name()) {
+			t.Logf("%q ok", e.Name())
+		} else {
+			t.Errorf("%q INVALID", e.Name())
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReadFileProc(t *testing.T) {
+	// Linux files in /proc report 0 size,
+	// but then if ReadFile reads just a single byte at offset 0,
+	// the read at offset 1 returns EOF instead of more data.
+	// ReadFile has a minimum read size of 512 to work around this,
+	// but test explicitly that it's working.
+	name := "/proc/sys/fs/pipe-max-size"
+	if _, err := Stat(name); err != nil {
+		t.Skip(err)
+	}
+	data, err := ReadFile(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data) == 0 || data[len(data)-1] != '\n' {
+		t.Fatalf("read %s: not newline-terminated: %q", name, data)
+	}
+}
+
+func TestReadFileUnsized(t *testing.T) {
+	// A pipe reports size 0, so ReadFile must grow its buffer
+	// as it goes without losing or duplicating data.
+	if runtime.GOOS != "linux" {
+		t.Skip("test requires /dev/fd on Linux")
+	}
+	r, w, err := Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	want := bytes.Repeat([]byte("0123456789abcdef"), 1<<16)
+	go func() {
+		w.Write(want)
+		w.Close()
+	}()
+	got, err := ReadFile(fmt.Sprintf("/dev/fd/%d", r.Fd()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("ReadFile of pipe returned %d bytes, want %d", len(got), len(want))
+	}
+}
+
+func TestWriteStringAlloc(t *testing.T) {
+	if runtime.GOOS == "js" {
+		t.Skip("js allocates a lot during File.WriteString")
+	}
+	d := t.TempDir()
+	f, err := Create(filepath.Join(d, "whiteboard.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	allocs := testing.AllocsPerRun(100, func() {
+		f.WriteString("I will not allocate when passed a string longer than 32 bytes.\n")
+	})
+	if allocs != 0 {
+		t.Errorf("expected 0 allocs for File.WriteString, got %v", allocs)
+	}
+}
+
+// An appDirRoot describes where AppConfigDir, AppCacheDir or AppDataDir
+// puts application directories on one system: below the directory named
+// by the environment variable env, in the subdirectory sub.
+type appDirRoot struct {
+	env, sub string
+}
+
+func TestAppDir(t *testing.T) {
+	tests := []struct {
+		name  string
+		fn    func(string) (string, error)
+		unset string                // environment variable to clear, if any
+		roots map[string]appDirRoot // by GOOS; "" for other Unix systems
+	}{
+		{"AppConfigDir", AppConfigDir, "", map[string]appDirRoot{
+			"windows": {"AppData", ""},
+			"plan9":   {"home", "lib"},
+			"darwin":  {"HOME", "Library/Application Support"},
+			"ios":     {"HOME", "Library/Application Support"},
+			"":        {"XDG_CONFIG_HOME", ""},
+		}},
+		{"AppCacheDir", AppCacheDir, "", map[string]appDirRoot{
+			"windows": {"LocalAppData", ""},
+			"plan9":   {"home", "lib/cache"},
+			"darwin":  {"HOME", "Library/Caches"},
+			"ios":     {"HOME", "Library/Caches"},
+			"":        {"XDG_CACHE_HOME", ""},
+		}},
+		{"AppDataDir", AppDataDir, "", map[string]appDirRoot{
+			"windows": {"LocalAppData", ""},
+			"plan9":   {"home", "lib"},
+			"darwin":  {"HOME", "Library/Application Support"},
+			"ios":     {"HOME", "Library/Application Support"},
+			"":        {"XDG_DATA_HOME", ""},
+		}},
+		{"AppDataDirNoXDG", AppDataDir, "XDG_DATA_HOME", map[string]appDirRoot{
+			"windows": {},
+			"plan9":   {},
+			"darwin":  {},
+			"ios":     {},
+			"":        {"HOME", ".local/share"},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root, ok := tt.roots[runtime.GOOS]
+			if !ok {
+				root = tt.roots[""]
+			}
+			if root.env == "" {
+				t.Skipf("not applicable on %s", runtime.GOOS)
+			}
+			if tt.unset != "" {
+				t.Setenv(tt.unset, "")
+			}
+			base := t.TempDir()
+			t.Setenv(root.env, base)
+			base = filepath.Join(base, filepath.FromSlash(root.sub))
+
+			dir, err := tt.fn("gopher")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := filepath.Join(base, "gopher"); dir != want {
+				t.Errorf("%s = %q, want %q", tt.name, dir, want)
+			}
+			fi, err := Stat(dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !fi.IsDir() {
+				t.Fatalf("%s is not a directory; mode = %v", dir, fi.Mode())
+			}
+			if runtime.GOOS != "windows" && runtime.GOOS != "plan9" && fi.Mode().Perm()&0077 != 0 {
+				t.Errorf("%s has mode %v, want no group or other permissions", dir, fi.Mode())
+			}
+
+			// A second call finds the existing directory.
+			if dir2, err := tt.fn("gopher"); err != nil || dir2 != dir {
+				t.Errorf("second %s = %q, %v; want %q, nil", tt.name, dir2, err, dir)
+			}
+
+			for _, app := range []string{"", ".", "..", "a/b", "../escape", string(PathSeparator) + "abs"} {
+				if dir, err := tt.fn(app); !errors.Is(err, ErrInvalid) {
+					t.Errorf("%s(%q) = %q, %v; want error wrapping ErrInvalid", tt.name, app, dir, err)
+				}
+			}
+		})
+	}
+}
+
+func TestUserRuntimeDir(t *testing.T) {
+	switch runtime.GOOS {
+	case "windows", "plan9":
+		t.Skipf("UserRuntimeDir does not use the environment on %s", runtime.GOOS)
+	}
+	key := "XDG_RUNTIME_DIR"
+	if runtime.GOOS == "darwin" || runtime.GOOS == "ios" {
+		key = "TMPDIR"
+	}
+	want := t.TempDir()
+	t.Setenv(key, want)
+	dir, err := UserRuntimeDir()
+	if err != nil || dir != want {
+		t.Errorf("UserRuntimeDir() = %q, %v; want %q, nil", dir, err, want)
+	}
+
+	t.Setenv(key, "")
+	if dir, err := UserRuntimeDir(); err == nil {
+		t.Errorf("UserRuntimeDir() with empty $%s = %q, want error", key, dir)
+	}
+}
+
+func TestApplyMetadata(t *testing.T) {
+	f, err := Create(filepath.Join(t.TempDir(), "TestApplyMetadata"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	st, err := f.Stat()
+	if err != nil {
+		t.Fatal(err)
+	}
+	mtime := st.ModTime().Add(-time.Hour).Truncate(time.Second)
+	meta := FileMetadata{
+		Mode:    0640,
+		SetMode: true,
+		Mtime:   mtime,
+	}
+	if runtime.GOOS != "windows" && runtime.GOOS != "plan9" {
+		meta.Uid, meta.Gid, meta.SetOwner = Getuid(), Getgid(), true
+	}
+	if err := f.ApplyMetadata(meta); err != nil {
+		t.Fatalf("ApplyMetadata: %v", err)
+	}
+
+	st, err = f.Stat()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !st.ModTime().Equal(mtime) {
+		t.Errorf("ModTime = %v, want %v", st.ModTime(), mtime)
+	}
+	if runtime.GOOS != "windows" && st.Mode().Perm() != 0640 {
+		t.Errorf("Mode = %v, want %v", st.Mode().Perm(), FileMode(0640))
+	}
+
+	// A zero FileMetadata changes nothing.
+	if err := f.ApplyMetadata(FileMetadata{}); err != nil {
+		t.Fatalf("ApplyMetadata with no changes: %v", err)
+	}
+	st2, err := f.Stat()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !st2.ModTime().Equal(st.ModTime()) || st2.Mode() != st.Mode() {
+		t.Errorf("ApplyMetadata with no changes modified file: %v %v -> %v %v", st.Mode(), st.ModTime(), st2.Mode(), st2.ModTime())
+	}
+}
+
+func TestSameContents(t *testing.T) {
+	dir := t.TempDir()
+	name1 := filepath.Join(dir, "a")
+	name2 := filepath.Join(dir, "b")
+	for _, name := range []string{name1, name2} {
+		if err := WriteFile(name, []byte("hello, world\n"), 0666); err != nil {
+			t.Fatal(err)
+		}
+	}
+	stat := func(name string, mtime time.Time) FileInfo {
+		t.Helper()
+		if err := Chtimes(name, mtime, mtime); err != nil {
+			t.Fatal(err)
+		}
+		fi, err := Stat(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return fi
+	}
+
+	base := time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)
+	fi1 := stat(name1, base)
+	fi2 := stat(name2, base)
+	if !SameContents(fi1, fi2) {
+		t.Errorf("SameContents with equal size and time = false, want true")
+	}
+	if NewerThan(fi1, fi2) || NewerThan(fi2, fi1) {
+		t.Errorf("NewerThan with equal times = true, want false")
+	}
+
+	// A whole-second time matches a time with a sub-second part.
+	fi1 = stat(name1, base.Add(500*time.Millisecond))
+	if !SameContents(fi1, fi2) {
+		t.Errorf("SameContents with sub-second difference = false, want true")
+	}
+
+	fi1 = stat(name1, base.Add(2*time.Second))
+	if SameContents(fi1, fi2) {
+		t.Errorf("SameContents with two-second difference = true, want false")
+	}
+	if !SameContentsWithin(fi1, fi2, 2*time.Second) {
+		t.Errorf("SameContentsWithin 2s with two-second difference = false, want true")
+	}
+	if !NewerThan(fi1, fi2) || NewerThan(fi2, fi1) {
+		t.Errorf("NewerThan(newer, older), NewerThan(older, newer) = %v, %v; want true, false", NewerThan(fi1, fi2), NewerThan(fi2, fi1))
+	}
+
+	if err := WriteFile(name2, []byte("hello\n"), 0666); err != nil {
+		t.Fatal(err)
+	}
+	fi1 = stat(name1, base)
+	fi2 = stat(name2, base)
+	if SameContents(fi1, fi2) {
+		t.Errorf("SameContents with different sizes = true, want false")
+	}
+}
+
+func TestMap(t *testing.T) {
+	switch runtime.GOOS {
+	case "aix", "js", "plan9", "solaris", "illumos":
+		t.Skipf("Map is not supported on %s", runtime.GOOS)
+	}
+	f, err := CreateTemp(t.TempDir(), "map")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	data := make([]byte, 3*Getpagesize()+100)
+	for i := range data {
+		data[i] = byte(i % 251)
+	}
+	if _, err := f.Write(data); err != nil {
+		t.Fatal(err)
+	}
+
+	// An unaligned read-only mapping.
+	off, length := int64(Getpagesize()+7), int64(Getpagesize())
+	m, err := Map(f, off, length, MapReadOnly)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(m.Bytes(), data[off:off+length]) {
+		t.Error("read-only mapping does not match file contents")
+	}
+	if err := m.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if m.Bytes() != nil {
+		t.Error("Bytes after Close is not nil")
+	}
+	if err := m.Close(); err != ErrClosed {
+		t.Errorf("second Close = %v, want ErrClosed", err)
+	}
+
+	// A read-write mapping of the rest of the file.
+	m, err = Map(f, off, 0, MapReadWrite)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Len() != len(data)-int(off) {
+		t.Errorf("Len = %d, want %d", m.Len(), len(data)-int(off))
+	}
+	for _, advice := range []MapAdvice{MapSequential, MapRandom, MapWillNeed, MapNormal} {
+		if err := m.Advise(advice); err != nil {
+			t.Errorf("Advise(%d): %v", advice, err)
+		}
+	}
+	if err := m.Advise(MapAdvice(-1)); err == nil {
+		t.Error("Advise with invalid advice succeeded")
+	}
+	if err := m.Lock(); err != nil {
+		// The locked memory limit may be too small.
+		t.Logf("Lock: %v", err)
+	} else if err := m.Unlock(); err != nil {
+		t.Errorf("Unlock: %v", err)
+	}
+	copy(m.Bytes(), "hello")
+	if err := m.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Close(); err != nil {
+		t.Fatal(err)
+	}
+	got := make([]byte, 5)
+	if _, err := f.ReadAt(got, off); err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "hello" {
+		t.Errorf("file contains %q after write through mapping, want %q", got, "hello")
+	}
+
+	if _, err := Map(f, int64(len(data)), 1, MapReadOnly); err == nil {
+		t.Error("Map beyond end of file succeeded")
+	}
+
+	// The memory stays mapped while only the slice returned by Bytes
+	// is reachable.
+	m, err = Map(f, 0, 0, MapReadOnly)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := m.Bytes()
+	m = nil
+	runtime.GC()
+	runtime.GC()
+	if !bytes.Equal(b[len(b)-100:], data[len(data)-100:]) {
+		t.Error("mapping changed after the MappedFile became unreachable")
+	}
+}
+
+func TestExcludeFromCoreDump(t *testing.T) {
+	switch runtime.GOOS {
+	case "linux", "freebsd", "dragonfly":
+	default:
+		if err := ExcludeFromCoreDump(make([]byte, 1)); !errors.Is(err, ErrUnsupported) {
+			t.Errorf("ExcludeFromCoreDump = %v, want ErrUnsupported", err)
+		}
+		return
+	}
+	secret := make([]byte, 3*Getpagesize())
+	b := secret[10 : Getpagesize()+20]
+	if err := ExcludeFromCoreDump(b); err != nil {
+		t.Fatal(err)
+	}
+	if err := IncludeInCoreDump(b); err != nil {
+		t.Fatal(err)
+	}
 }
 
-func TestUserHomeDir(t *testing.T) {
-	dir, err := UserHomeDir()
-	if dir == "" && err == nil {
-		t.Fatal("UserHomeDir returned an empty string but no error")
+func TestSecureRemove(t *testing.T) {
+	dir := t.TempDir()
+	name := filepath.Join(dir, "secret")
+	data := bytes.Repeat([]byte("secret"), 20000)
+	if err := WriteFile(name, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := SecureRemove(name, 0); !errors.Is(err, ErrInvalid) {
+		t.Errorf("SecureRemove with 0 passes = %v, want ErrInvalid", err)
+	}
+	// A hard link keeps the data reachable after removal.
+	link := filepath.Join(dir, "link")
+	haveLink := Link(name, link) == nil
+	if err := SecureRemove(name, 2); err != nil {
+		t.Fatal(err)
+	}
+	if haveLink {
+		got, err := ReadFile(link)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(got) != len(data) || bytes.Contains(got, []byte("secret")) {
+			t.Errorf("file contents were not overwritten")
+		}
+	}
+	if _, err := Lstat(name); !IsNotExist(err) {
+		t.Errorf("Lstat after SecureRemove = %v, want not exist", err)
+	}
+	if err := SecureRemove(dir, 1); err == nil {
+		t.Error("SecureRemove of a directory succeeded")
+	}
+	if err := SecureRemove(name, 1); !IsNotExist(err) {
+		t.Errorf("SecureRemove of missing file = %v, want not exist", err)
+	}
+
+	// A symbolic link is not followed, so its target is untouched.
+	if testenv.HasSymlink() {
+		target := filepath.Join(dir, "target")
+		if err := WriteFile(target, data, 0600); err != nil {
+			t.Fatal(err)
+		}
+		if err := Symlink(target, name); err != nil {
+			t.Fatal(err)
+		}
+		if err := SecureRemove(name, 1); err == nil {
+			t.Error("SecureRemove of a symbolic link succeeded")
+		}
+		if got, err := ReadFile(target); err != nil || !bytes.Equal(got, data) {
+			t.Errorf("SecureRemove of a symbolic link changed its target")
+		}
+	}
+}
+
+func TestCreateLike(t *testing.T) {
+	dir := t.TempDir()
+	template := filepath.Join(dir, "template")
+	if err := WriteFile(template, []byte("old"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := Chmod(template, 0640); err != nil {
+		t.Fatal(err)
+	}
+	tfi, err := Stat(template)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	name := filepath.Join(dir, "new")
+	f, err := CreateLikeOwner(name, template)
+	if err != nil {
+		t.Fatalf("CreateLikeOwner with a template owned by the current user: %v", err)
+	}
+	if _, err := f.WriteString("new"); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+	fi, err := Stat(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fi.Mode() != tfi.Mode() {
+		t.Errorf("mode = %v, want %v", fi.Mode(), tfi.Mode())
+	}
+	if fi.Size() != 3 {
+		t.Errorf("size = %d, want 3", fi.Size())
+	}
+
+	if _, err := CreateLike(filepath.Join(dir, "other"), filepath.Join(dir, "missing")); !IsNotExist(err) {
+		t.Errorf("CreateLike with missing template = %v, want not exist", err)
+	}
+	if _, err := Stat(filepath.Join(dir, "other")); !IsNotExist(err) {
+		t.Error("CreateLike with missing template created the file")
+	}
+}
+
+func TestLchtimes(t *testing.T) {
+	switch runtime.GOOS {
+	case "aix", "js", "plan9", "solaris", "illumos":
+		t.Skipf("Lchtimes is not supported on %s", runtime.GOOS)
+	}
+	testenv.MustHaveSymlink(t)
+	dir := t.TempDir()
+	target := filepath.Join(dir, "target")
+	if err := WriteFile(target, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	link := filepath.Join(dir, "link")
+	if err := Symlink(target, link); err != nil {
+		t.Fatal(err)
+	}
+	before, err := Stat(target)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mtime := time.Date(2001, time.February, 3, 4, 5, 6, 0, time.UTC)
+	if err := Lchtimes(link, time.Time{}, mtime); err != nil {
+		t.Fatal(err)
+	}
+	fi, err := Lstat(link)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !fi.ModTime().Equal(mtime) {
+		t.Errorf("link mtime = %v, want %v", fi.ModTime(), mtime)
+	}
+	after, err := Stat(target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !after.ModTime().Equal(before.ModTime()) {
+		t.Errorf("target mtime changed from %v to %v", before.ModTime(), after.ModTime())
+	}
+}
+
+func TestIsCaseSensitive(t *testing.T) {
+	dir := t.TempDir()
+	sensitive, err := IsCaseSensitive(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Logf("IsCaseSensitive(%q) = %v", dir, sensitive)
+
+	// The probe file must be gone.
+	if names, err := ReadDir(dir); err != nil {
+		t.Fatal(err)
+	} else if len(names) != 0 {
+		t.Errorf("IsCaseSensitive left %d files behind", len(names))
+	}
+
+	// Check the answer independently.
+	if err := WriteFile(filepath.Join(dir, "name"), nil, 0666); err != nil {
+		t.Fatal(err)
+	}
+	_, err = Stat(filepath.Join(dir, "NAME"))
+	if want := err != nil; sensitive != want {
+		t.Errorf("IsCaseSensitive = %v, but Stat of the upper-case name returned %v", sensitive, err)
+	}
+
+	// A file is examined through its directory.
+	if got, err := IsCaseSensitive(filepath.Join(dir, "name")); err != nil || got != sensitive {
+		t.Errorf("IsCaseSensitive(file) = %v, %v; want %v, nil", got, err, sensitive)
+	}
+	if _, err := IsCaseSensitive(filepath.Join(dir, "missing")); !IsNotExist(err) {
+		t.Errorf("IsCaseSensitive(missing) = %v, want not exist", err)
+	}
+}
+
+func TestIncrementFile(t *testing.T) {
+	switch runtime.GOOS {
+	case "js", "plan9":
+		t.Skipf("file locking not supported on %s", runtime.GOOS)
+	}
+	name := filepath.Join(t.TempDir(), "counter")
+	if n, err := IncrementFile(name, 5); err != nil || n != 5 {
+		t.Fatalf("IncrementFile on new file = %d, %v; want 5, nil", n, err)
+	}
+	if n, err := IncrementFile(name, -7); err != nil || n != -2 {
+		t.Fatalf("IncrementFile(-7) = %d, %v; want -2, nil", n, err)
+	}
+	if b, err := ReadFile(name); err != nil || string(b) != "-2\n" {
+		t.Fatalf("counter file holds %q, %v; want %q", b, err, "-2\n")
+	}
+
+	// Concurrent increments must not be lost. Locks on aix and solaris
+	// belong to the process, so they do not exclude goroutines.
+	if runtime.GOOS != "aix" && runtime.GOOS != "solaris" {
+		const workers, each = 4, 25
+		var wg sync.WaitGroup
+		for i := 0; i < workers; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for j := 0; j < each; j++ {
+					if _, err := IncrementFile(name, 1); err != nil {
+						t.Error(err)
+						return
+					}
+				}
+			}()
+		}
+		wg.Wait()
+		if n, err := IncrementFile(name, 0); err != nil || n != workers*each-2 {
+			t.Errorf("after concurrent increments, counter = %d, %v; want %d", n, err, workers*each-2)
+		}
+	}
+
+	if err := WriteFile(name, []byte("twelve\n"), 0666); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := IncrementFile(name, 1); err == nil {
+		t.Error("IncrementFile on malformed file succeeded")
+	} else if _, ok := err.(*PathError); !ok {
+		t.Errorf("IncrementFile on malformed file: got %T %v, want *PathError", err, err)
+	}
+	if b, _ := ReadFile(name); string(b) != "twelve\n" {
+		t.Errorf("malformed counter file was changed to %q", b)
+	}
+	if err := WriteFile(name, []byte("9223372036854775807"), 0666); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := IncrementFile(name, 1); err == nil {
+		t.Error("IncrementFile overflow succeeded")
+	}
+}
+
+func TestPidFile(t *testing.T) {
+	if Getenv("GO_WANT_HELPER_PROCESS") == "1" {
+		err := WritePidFile(Getenv("GO_PIDFILE"))
+		fmt.Print(errors.Is(err, ErrAlreadyRunning))
+		Exit(0)
+	}
+	testenv.MustHaveExec(t)
+
+	dir := t.TempDir()
+	name := filepath.Join(dir, "test.pid")
+	if err := WritePidFile(name); err != nil {
+		t.Fatal(err)
+	}
+	// Writing again from the same process succeeds.
+	if err := WritePidFile(name); err != nil {
+		t.Fatal(err)
+	}
+	pid, alive, err := ReadPidFile(name)
+	if err != nil || pid != Getpid() || !alive {
+		t.Fatalf("ReadPidFile = %d, %v, %v; want %d, true, nil", pid, alive, err, Getpid())
+	}
+
+	// Another process must be refused.
+	cmd := osexec.Command(Args[0], "-test.run=^TestPidFile$")
+	cmd.Env = append(Environ(), "GO_WANT_HELPER_PROCESS=1", "GO_PIDFILE="+name)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("helper process failed: %v\n%s", err, out)
+	}
+	if string(out) != "true" {
+		t.Errorf("WritePidFile in another process: got ErrAlreadyRunning = %s, want true", out)
+	}
+	if pid, _, _ := ReadPidFile(name); pid != Getpid() {
+		t.Errorf("PID file changed to %d by refused process", pid)
+	}
+
+	// A file naming an exited process is stale.
+	stale := filepath.Join(dir, "stale.pid")
+	if err := WriteFile(stale, []byte(fmt.Sprintf("%d\n", cmd.Process.Pid)), 0666); err != nil {
+		t.Fatal(err)
+	}
+	if pid, alive, err := ReadPidFile(stale); err != nil || pid != cmd.Process.Pid || alive {
+		t.Errorf("ReadPidFile(stale) = %d, %v, %v; want %d, false, nil", pid, alive, err, cmd.Process.Pid)
+	}
+
+	if err := WriteFile(stale, []byte("not a pid\n"), 0666); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := ReadPidFile(stale); err == nil {
+		t.Error("ReadPidFile of malformed file succeeded")
+	}
+}
+
+func TestAcquireSingleInstance(t *testing.T) {
+	if Getenv("GO_WANT_HELPER_PROCESS") == "1" {
+		release, err := AcquireSingleInstance(Getenv("GO_INSTANCE_LOCK"))
+		if err == nil {
+			err = release()
+		}
+		fmt.Print(errors.Is(err, ErrAlreadyRunning))
+		Exit(0)
+	}
+	switch runtime.GOOS {
+	case "js", "plan9":
+		t.Skipf("file locking not supported on %s", runtime.GOOS)
+	}
+	testenv.MustHaveExec(t)
+
+	name := filepath.Join(t.TempDir(), "instance.lock")
+	tryOther := func() string {
+		cmd := osexec.Command(Args[0], "-test.run=^TestAcquireSingleInstance$")
+		cmd.Env = append(Environ(), "GO_WANT_HELPER_PROCESS=1", "GO_INSTANCE_LOCK="+name)
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("helper process failed: %v\n%s", err, out)
+		}
+		return string(out)
+	}
+
+	release, err := AcquireSingleInstance(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := tryOther(); got != "true" {
+		t.Errorf("while locked, other process got ErrAlreadyRunning = %s, want true", got)
+	}
+	if err := release(); err != nil {
+		t.Fatal(err)
+	}
+	if err := release(); err != nil {
+		t.Errorf("second release: %v", err)
+	}
+	if got := tryOther(); got != "false" {
+		t.Errorf("after release, other process got ErrAlreadyRunning = %s, want false", got)
+	}
+
+	// An application name selects a lock file in a standard directory.
+	dir := t.TempDir()
+	for _, env := range []string{"XDG_RUNTIME_DIR", "TMPDIR", "TMP"} {
+		t.Setenv(env, dir)
+	}
+	release, err = AcquireSingleInstance("go-os-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Stat(filepath.Join(dir, "go-os-test.lock")); err != nil {
+		t.Error(err)
+	}
+	if err := release(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestOverlay(t *testing.T) {
+	base := t.TempDir()
+	for name, data := range map[string]string{
+		"a.txt":       "a",
+		"b.txt":       "b",
+		"dir/c.txt":   "c",
+		"dir/d.txt":   "d",
+		"gone/e.txt":  "e",
+		"empty/.keep": "",
+	} {
+		path := filepath.Join(base, filepath.FromSlash(name))
+		if err := MkdirAll(filepath.Dir(path), 0777); err != nil {
+			t.Fatal(err)
+		}
+		if err := WriteFile(path, []byte(data), 0666); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	o, err := NewOverlay(base)
+	if err != nil {
+		t.Fatal(err)
+	}
+	must := func(err error) {
+		t.Helper()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	must(o.WriteFile("a.txt", []byte("new a"), 0666))
+	must(o.WriteFile("dir/new.txt", []byte("new"), 0666))
+	must(o.Remove("b.txt"))
+	must(o.Remove("dir/c.txt"))
+	must(o.Remove("gone/e.txt"))
+	must(o.Remove("gone"))
+	must(o.Mkdir("gone", 0777))
+	must(o.WriteFile("gone/f.txt", []byte("f"), 0666))
+	must(o.Mkdir("fresh", 0777))
+
+	if err := o.Remove("dir"); err == nil {
+		t.Error("Remove of non-empty directory succeeded")
+	}
+	if err := o.WriteFile("missing/x.txt", nil, 0666); !IsNotExist(err) {
+		t.Errorf("WriteFile in missing directory: got %v, want not exist", err)
+	}
+	if err := o.Mkdir("dir", 0777); !IsExist(err) {
+		t.Errorf("Mkdir of existing directory: got %v, want exist", err)
+	}
+	if _, err := o.Stat("b.txt"); !IsNotExist(err) {
+		t.Errorf("Stat of removed file: got %v, want not exist", err)
+	}
+	if b, err := o.ReadFile("a.txt"); err != nil || string(b) != "new a" {
+		t.Errorf("ReadFile(a.txt) = %q, %v; want %q, nil", b, err, "new a")
+	}
+
+	if err := fstest.TestFS(o, "a.txt", "dir/d.txt", "dir/new.txt", "empty/.keep", "fresh", "gone/f.txt"); err != nil {
+		t.Fatal(err)
+	}
+	// Whiteouts must hide removed names from listings.
+	for dir, want := range map[string]string{
+		".":    "a.txt dir empty fresh gone",
+		"dir":  "d.txt new.txt",
+		"gone": "f.txt",
+	} {
+		entries, err := o.ReadDir(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var names []string
+		for _, e := range entries {
+			names = append(names, e.Name())
+		}
+		if got := strings.Join(names, " "); got != want {
+			t.Errorf("ReadDir(%q) = %s, want %s", dir, got, want)
+		}
+	}
+
+	// The base tree is untouched.
+	for name, want := range map[string]string{"a.txt": "a", "b.txt": "b", "dir/c.txt": "c", "gone/e.txt": "e"} {
+		b, err := ReadFile(filepath.Join(base, filepath.FromSlash(name)))
+		if err != nil || string(b) != want {
+			t.Errorf("base %s = %q, %v; want %q, nil", name, b, err, want)
+		}
+	}
+	if _, err := Stat(filepath.Join(base, "dir", "new.txt")); !IsNotExist(err) {
+		t.Errorf("write through overlay reached base tree: %v", err)
+	}
+}
+
+func TestCopyFileProgress(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "src")
+	want := bytes.Repeat([]byte("copy"), 50000)
+	if err := WriteFile(src, want, 0640); err != nil {
+		t.Fatal(err)
+	}
+
+	dst := filepath.Join(dir, "dst")
+	var calls int
+	var last int64
+	progress := func(copied, total int64) {
+		calls++
+		if copied < last || total != int64(len(want)) {
+			t.Errorf("progress(%d, %d) after %d; want increasing copied and total %d", copied, total, last, len(want))
+		}
+		last = copied
+	}
+	if err := CopyFileProgress(dst, src, progress, 0); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := ReadFile(dst); err != nil || !bytes.Equal(got, want) {
+		t.Fatalf("copy holds %d bytes, %v; want %d bytes", len(got), err, len(want))
+	}
+	if calls == 0 || last != int64(len(want)) {
+		t.Errorf("progress called %d times, last with %d; want final call with %d", calls, last, len(want))
+	}
+
+	// With a rate limit, everything past the initial burst of a
+	// quarter second's worth is throttled.
+	const rate = 400000
+	start := time.Now()
+	if err := CopyFileProgress(dst, src, nil, rate); err != nil {
+		t.Fatal(err)
+	}
+	min := time.Duration(float64(len(want)-rate/4) / rate * float64(time.Second))
+	if d := time.Since(start); d < min*9/10 {
+		t.Errorf("rate-limited copy took %v, want at least %v", d, min)
+	}
+	if got, err := ReadFile(dst); err != nil || !bytes.Equal(got, want) {
+		t.Fatalf("rate-limited copy holds %d bytes, %v; want %d bytes", len(got), err, len(want))
+	}
+
+	if err := CopyFileProgress(dst, dir, nil, 0); err == nil {
+		t.Error("CopyFileProgress of a directory succeeded")
+	}
+
+	// Copying a file onto itself fails without truncating it.
+	if err := CopyFileProgress(src, src, nil, 0); err == nil {
+		t.Error("CopyFileProgress of a file onto itself succeeded")
+	}
+	if got, err := ReadFile(src); err != nil || !bytes.Equal(got, want) {
+		t.Errorf("source holds %d bytes, %v after copy onto itself; want %d bytes", len(got), err, len(want))
+	}
+}
+
+func TestSyncDir(t *testing.T) {
+	src, dst := t.TempDir(), t.TempDir()
+	write := func(dir, name, data string) {
+		t.Helper()
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := MkdirAll(filepath.Dir(path), 0777); err != nil {
+			t.Fatal(err)
+		}
+		if err := WriteFile(path, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
 	}
-	if err != nil {
-		t.Skipf("UserHomeDir failed: %v", err)
+	check := func(name, want string) {
+		t.Helper()
+		got, err := ReadFile(filepath.Join(dst, filepath.FromSlash(name)))
+		if err != nil || string(got) != want {
+			t.Errorf("dst %s = %q, %v; want %q", name, got, err, want)
+		}
 	}
-	fi, err := Stat(dir)
+	write(src, "a", "aaa")
+	write(src, "sub/b", "bbb")
+	write(src, "sub/deep/c", "ccc")
+	write(dst, "extra", "x")
+	write(dst, "sub/extra", "x")
+
+	stats, err := SyncDir(dst, src, SyncOptions{})
 	if err != nil {
 		t.Fatal(err)
 	}
-	if !fi.IsDir() {
-		t.Fatalf("dir %s is not directory; type = %v", dir, fi.Mode())
+	if stats.Copied != 3 || stats.Bytes != 9 || stats.Deleted != 0 {
+		t.Errorf("first sync: %+v", stats)
 	}
-}
+	check("a", "aaa")
+	check("sub/b", "bbb")
+	check("sub/deep/c", "ccc")
+	check("extra", "x")
 
-func TestDirSeek(t *testing.T) {
-	if runtime.GOOS == "windows" {
-		testenv.SkipFlaky(t, 36019)
-	}
-	wd, err := Getwd()
+	// Unchanged files are skipped.
+	stats, err = SyncDir(dst, src, SyncOptions{})
 	if err != nil {
 		t.Fatal(err)
 	}
-	f, err := Open(wd)
+	if stats.Copied != 0 || stats.Skipped != 3 {
+		t.Errorf("second sync: %+v", stats)
+	}
+
+	// A change of the same size with the same modification time is
+	// only found by comparing contents.
+	fi, err := Stat(filepath.Join(src, "a"))
 	if err != nil {
 		t.Fatal(err)
 	}
-	dirnames1, err := f.Readdirnames(0)
-	if err != nil {
+	write(src, "a", "AAA")
+	if err := Chtimes(filepath.Join(src, "a"), fi.ModTime(), fi.ModTime()); err != nil {
 		t.Fatal(err)
 	}
+	if stats, err = SyncDir(dst, src, SyncOptions{}); err != nil || stats.Copied != 0 {
+		t.Errorf("sync by time = %+v, %v; want nothing copied", stats, err)
+	}
+	if stats, err = SyncDir(dst, src, SyncOptions{Checksum: true}); err != nil || stats.Copied != 1 {
+		t.Errorf("sync by contents = %+v, %v; want 1 copied", stats, err)
+	}
+	check("a", "AAA")
 
-	ret, err := f.Seek(0, 0)
+	stats, err = SyncDir(dst, src, SyncOptions{Delete: true})
 	if err != nil {
 		t.Fatal(err)
 	}
-	if ret != 0 {
-		t.Fatalf("seek result not zero: %d", ret)
+	if stats.Deleted != 2 {
+		t.Errorf("sync with delete: %+v, want 2 deleted", stats)
+	}
+	for _, name := range []string{"extra", "sub/extra"} {
+		if _, err := Lstat(filepath.Join(dst, filepath.FromSlash(name))); !IsNotExist(err) {
+			t.Errorf("%s not deleted: %v", name, err)
+		}
 	}
 
-	dirnames2, err := f.Readdirnames(0)
-	if err != nil {
+	// A destination nested in the source is not copied into itself.
+	nested := filepath.Join(src, "backup")
+	if _, err := SyncDir(nested, src, SyncOptions{}); err != nil {
 		t.Fatal(err)
-		return
+	}
+	if _, err := Lstat(filepath.Join(nested, "backup")); !IsNotExist(err) {
+		t.Errorf("SyncDir copied the destination into itself: %v", err)
 	}
 
-	if len(dirnames1) != len(dirnames2) {
-		t.Fatalf("listings have different lengths: %d and %d\n", len(dirnames1), len(dirnames2))
+	// A source nested in the destination is not deleted, nor is a
+	// directory between them.
+	outer := t.TempDir()
+	inner := filepath.Join(outer, "x", "src")
+	write(inner, "f", "F")
+	write(outer, "extra", "E")
+	if _, err := SyncDir(outer, inner, SyncOptions{Delete: true}); err != nil {
+		t.Fatal(err)
 	}
-	for i, n1 := range dirnames1 {
-		n2 := dirnames2[i]
-		if n1 != n2 {
-			t.Fatalf("different name i=%d n1=%s n2=%s\n", i, n1, n2)
-		}
+	if b, err := ReadFile(filepath.Join(inner, "f")); err != nil || string(b) != "F" {
+		t.Errorf("source after SyncDir into its ancestor: %q, %v", b, err)
+	}
+	if b, err := ReadFile(filepath.Join(outer, "f")); err != nil || string(b) != "F" {
+		t.Errorf("copy in ancestor: %q, %v", b, err)
+	}
+	if _, err := Lstat(filepath.Join(outer, "extra")); !IsNotExist(err) {
+		t.Errorf("extra entry not deleted: %v", err)
 	}
 }
 
-func TestReaddirSmallSeek(t *testing.T) {
-	// See issue 37161. Read only one entry from a directory,
-	// seek to the beginning, and read again. We should not see
-	// duplicate entries.
-	if runtime.GOOS == "windows" {
-		testenv.SkipFlaky(t, 36019)
+func TestSyncDirSymlinks(t *testing.T) {
+	testenv.MustHaveSymlink(t)
+	src, dst, outside := t.TempDir(), t.TempDir(), t.TempDir()
+	if err := WriteFile(filepath.Join(outside, "keep"), []byte("keep"), 0644); err != nil {
+		t.Fatal(err)
 	}
-	wd, err := Getwd()
-	if err != nil {
+	if err := Symlink("target", filepath.Join(src, "link")); err != nil {
 		t.Fatal(err)
 	}
-	df, err := Open(filepath.Join(wd, "testdata", "issue37161"))
-	if err != nil {
+	if err := Mkdir(filepath.Join(src, "dir"), 0777); err != nil {
 		t.Fatal(err)
 	}
-	names1, err := df.Readdirnames(1)
-	if err != nil {
+	if err := WriteFile(filepath.Join(src, "dir", "keep"), []byte("new"), 0644); err != nil {
 		t.Fatal(err)
 	}
-	if _, err = df.Seek(0, 0); err != nil {
+	// A link in dst must not be written through.
+	if err := Symlink(outside, filepath.Join(dst, "dir")); err != nil {
 		t.Fatal(err)
 	}
-	names2, err := df.Readdirnames(0)
-	if err != nil {
+	if _, err := SyncDir(dst, src, SyncOptions{Delete: true}); err != nil {
 		t.Fatal(err)
 	}
-	if len(names2) != 3 {
-		t.Fatalf("first names: %v, second names: %v", names1, names2)
+	if target, err := Readlink(filepath.Join(dst, "link")); err != nil || target != "target" {
+		t.Errorf("Readlink(dst/link) = %q, %v; want %q", target, err, "target")
+	}
+	if b, err := ReadFile(filepath.Join(outside, "keep")); err != nil || string(b) != "keep" {
+		t.Errorf("file outside dst = %q, %v; want unchanged", b, err)
+	}
+	if fi, err := Lstat(filepath.Join(dst, "dir")); err != nil || !fi.IsDir() {
+		t.Errorf("dst/dir is not a directory: %v", err)
 	}
 }
 
-// isDeadlineExceeded reports whether err is or wraps os.ErrDeadlineExceeded.
-// We also check that the error has a Timeout method that returns true.
-func isDeadlineExceeded(err error) bool {
-	if !IsTimeout(err) {
-		return false
+func TestWalkDirParallel(t *testing.T) {
+	root := t.TempDir()
+	for i := 0; i < 4; i++ {
+		for j := 0; j < 4; j++ {
+			dir := filepath.Join(root, fmt.Sprintf("d%d", i), fmt.Sprintf("e%d", j))
+			if err := MkdirAll(dir, 0777); err != nil {
+				t.Fatal(err)
+			}
+			for k := 0; k < 3; k++ {
+				if err := WriteFile(filepath.Join(dir, fmt.Sprintf("f%d", k)), nil, 0666); err != nil {
+					t.Fatal(err)
+				}
+			}
+		}
 	}
-	if !errors.Is(err, ErrDeadlineExceeded) {
-		return false
+
+	walk := func(concurrency int, fn func(path string, d fs.DirEntry) error) ([]string, error) {
+		var mu sync.Mutex
+		var paths []string
+		err := WalkDirParallel(root, concurrency, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			mu.Lock()
+			paths = append(paths, path)
+			mu.Unlock()
+			return fn(path, d)
+		})
+		sort.Strings(paths)
+		return paths, err
 	}
-	return true
-}
 
-// Test that opening a file does not change its permissions.  Issue 38225.
-func TestOpenFileKeepsPermissions(t *testing.T) {
-	t.Parallel()
-	dir := t.TempDir()
-	name := filepath.Join(dir, "x")
-	f, err := Create(name)
-	if err != nil {
+	var want []string
+	if err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		want = append(want, path)
+		return err
+	}); err != nil {
 		t.Fatal(err)
 	}
-	if err := f.Close(); err != nil {
-		t.Error(err)
+	sort.Strings(want)
+	for _, concurrency := range []int{0, 1, 4, 16} {
+		got, err := walk(concurrency, func(string, fs.DirEntry) error { return nil })
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("concurrency %d: visited %d paths, want %d", concurrency, len(got), len(want))
+		}
 	}
-	f, err = OpenFile(name, O_WRONLY|O_CREATE|O_TRUNC, 0)
+
+	// SkipDir on a directory skips its contents.
+	skipped := filepath.Join(root, "d1")
+	got, err := walk(4, func(path string, d fs.DirEntry) error {
+		if path == skipped {
+			return fs.SkipDir
+		}
+		return nil
+	})
 	if err != nil {
 		t.Fatal(err)
 	}
-	if fi, err := f.Stat(); err != nil {
-		t.Error(err)
-	} else if fi.Mode()&0222 == 0 {
-		t.Errorf("f.Stat.Mode after OpenFile is %v, should be writable", fi.Mode())
+	for _, p := range got {
+		if strings.HasPrefix(p, skipped+string(PathSeparator)) {
+			t.Errorf("visited %s inside skipped directory", p)
+		}
 	}
-	if err := f.Close(); err != nil {
-		t.Error(err)
+	if len(got) != len(want)-4*(1+3) {
+		t.Errorf("with SkipDir visited %d paths, want %d", len(got), len(want)-4*(1+3))
 	}
-	if fi, err := Stat(name); err != nil {
-		t.Error(err)
-	} else if fi.Mode()&0222 == 0 {
-		t.Errorf("Stat after OpenFile is %v, should be writable", fi.Mode())
+
+	// SkipAll stops the walk without error; other errors are returned.
+	if got, err := walk(4, func(string, fs.DirEntry) error { return fs.SkipAll }); err != nil || len(got) != 1 {
+		t.Errorf("SkipAll: visited %d paths, err %v; want 1, nil", len(got), err)
+	}
+	errStop := errors.New("stop")
+	if _, err := walk(4, func(path string, d fs.DirEntry) error {
+		if !d.IsDir() {
+			return errStop
+		}
+		return nil
+	}); err != errStop {
+		t.Errorf("got error %v, want %v", err, errStop)
+	}
+
+	missing := filepath.Join(root, "missing")
+	if err := WalkDirParallel(missing, 4, func(path string, d fs.DirEntry, err error) error { return err }); !IsNotExist(err) {
+		t.Errorf("walk of missing root: got %v, want not exist", err)
 	}
 }
 
-func TestDirFS(t *testing.T) {
-	// On Windows, we force the MFT to update by reading the actual metadata from GetFileInformationByHandle and then
-	// explicitly setting that. Otherwise it might get out of sync with FindFirstFile. See golang.org/issues/42637.
-	if runtime.GOOS == "windows" {
-		if err := filepath.WalkDir("./testdata/dirfs", func(path string, d fs.DirEntry, err error) error {
-			if err != nil {
+func TestFileTx(t *testing.T) {
+	setup := func(t *testing.T) string {
+		dir := t.TempDir()
+		for name, data := range map[string]string{"a": "a", "b": "b", "c": "c", "d": "d"} {
+			if err := WriteFile(filepath.Join(dir, name), []byte(data), 0666); err != nil {
 				t.Fatal(err)
 			}
-			info, err := d.Info()
-			if err != nil {
-				t.Fatal(err)
-			}
-			stat, err := Stat(path) // This uses GetFileInformationByHandle internally.
+		}
+		return dir
+	}
+	contents := func(t *testing.T, dir string) string {
+		var s []string
+		filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
 			if err != nil {
 				t.Fatal(err)
 			}
-			if stat.ModTime() == info.ModTime() {
-				return nil
-			}
-			if err := Chtimes(path, stat.ModTime(), stat.ModTime()); err != nil {
-				t.Log(err) // We only log, not die, in case the test directory is not writable.
+			rel, _ := filepath.Rel(dir, path)
+			if d.IsDir() {
+				s = append(s, filepath.ToSlash(rel)+"/")
+			} else {
+				b, _ := ReadFile(path)
+				s = append(s, filepath.ToSlash(rel)+"="+string(b))
 			}
 			return nil
-		}); err != nil {
+		})
+		return strings.Join(s, " ")
+	}
+	apply := func(t *testing.T, dir string) *FileTx {
+		tx := NewFileTx()
+		if err := tx.Mkdir(filepath.Join(dir, "new"), 0777); err != nil {
 			t.Fatal(err)
 		}
-	}
-	if err := fstest.TestFS(DirFS("./testdata/dirfs"), "a", "b", "dir/x"); err != nil {
-		t.Fatal(err)
+		if err := tx.Rename(filepath.Join(dir, "a"), filepath.Join(dir, "new", "a")); err != nil {
+			t.Fatal(err)
+		}
+		if err := tx.Remove(filepath.Join(dir, "b")); err != nil {
+			t.Fatal(err)
+		}
+		// Replacing d stages it.
+		if err := tx.Rename(filepath.Join(dir, "c"), filepath.Join(dir, "d")); err != nil {
+			t.Fatal(err)
+		}
+		if err := tx.Remove(filepath.Join(dir, "missing")); !IsNotExist(err) {
+			t.Errorf("Remove of missing file: got %v, want not exist", err)
+		}
+		return tx
 	}
 
-	// Test that Open does not accept backslash as separator.
-	d := DirFS(".")
-	_, err := d.Open(`testdata\dirfs`)
-	if err == nil {
-		t.Fatalf(`Open testdata\dirfs succeeded`)
-	}
+	t.Run("Rollback", func(t *testing.T) {
+		dir := setup(t)
+		before := contents(t, dir)
+		tx := apply(t, dir)
+		if err := tx.Rollback(); err != nil {
+			t.Fatal(err)
+		}
+		if after := contents(t, dir); after != before {
+			t.Errorf("after Rollback: %s\nwant: %s", after, before)
+		}
+		if err := tx.Commit(); err == nil {
+			t.Error("Commit after Rollback succeeded")
+		}
+	})
+	t.Run("Commit", func(t *testing.T) {
+		dir := setup(t)
+		tx := apply(t, dir)
+		if err := tx.Commit(); err != nil {
+			t.Fatal(err)
+		}
+		if got, want := contents(t, dir), "./ d=c new/ new/a=a"; got != want {
+			t.Errorf("after Commit: %s\nwant: %s", got, want)
+		}
+		if err := tx.Rollback(); err == nil {
+			t.Error("Rollback after Commit succeeded")
+		}
+		if err := tx.Mkdir(filepath.Join(dir, "late"), 0777); err == nil {
+			t.Error("Mkdir after Commit succeeded")
+		}
+	})
 }
 
-func TestDirFSPathsValid(t *testing.T) {
-	if runtime.GOOS == "windows" {
-		t.Skipf("skipping on Windows")
+func TestStatCache(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "file")
+	c := NewStatCache(time.Hour)
+	if _, err := c.Stat(name); !IsNotExist(err) {
+		t.Fatalf("Stat of missing file: got %v, want not exist", err)
 	}
-
-	d := t.TempDir()
-	if err := os.WriteFile(filepath.Join(d, "control.txt"), []byte(string("Hello, world!")), 0644); err != nil {
+	if err := WriteFile(name, []byte("abc"), 0666); err != nil {
 		t.Fatal(err)
 	}
-	if err := os.WriteFile(filepath.Join(d, `e:xperi\ment.txt`), []byte(string("Hello, colon and backslash!")), 0644); err != nil {
+	// The cached error is still reported until invalidated.
+	if _, err := c.Stat(name); !IsNotExist(err) {
+		t.Errorf("cached Stat: got %v, want not exist", err)
+	}
+	c.Invalidate(name)
+	fi, err := c.Stat(name)
+	if err != nil || fi.Size() != 3 {
+		t.Fatalf("Stat after Invalidate = %v, %v; want size 3", fi, err)
+	}
+	if err := WriteFile(name, []byte("abcdef"), 0666); err != nil {
 		t.Fatal(err)
 	}
+	if fi, _ := c.Stat(name); fi.Size() != 3 {
+		t.Errorf("cached Stat size = %d, want 3", fi.Size())
+	}
 
-	fsys := os.DirFS(d)
-	err := fs.WalkDir(fsys, ".", func(path string, e fs.DirEntry, err error) error {
-		if fs.ValidPath(e.Name()) {
-			t.Logf("%q ok", e.Name())
-		} else {
-			t.Errorf("%q INVALID", e.Name())
-		}
-		return nil
-	})
-	if err != nil {
+	// Absence is cached for a shorter time than presence.
+	c = NewStatCache(time.Hour)
+	c.SetNotExistTTL(0)
+	missing := name + ".missing"
+	if _, err := c.Stat(missing); !IsNotExist(err) {
+		t.Fatalf("Stat of missing file: got %v, want not exist", err)
+	}
+	if err := WriteFile(missing, nil, 0666); err != nil {
 		t.Fatal(err)
 	}
+	if _, err := c.Stat(missing); err != nil {
+		t.Errorf("Stat of created file with zero not-exist ttl: %v", err)
+	}
+
+	// With no time to live, nothing is served from the cache.
+	c = NewStatCache(0)
+	if fi, err := c.Stat(name); err != nil || fi.Size() != 6 {
+		t.Errorf("Stat = %v, %v; want size 6", fi, err)
+	}
+	if err := WriteFile(name, nil, 0666); err != nil {
+		t.Fatal(err)
+	}
+	if fi, err := c.Stat(name); err != nil || fi.Size() != 0 {
+		t.Errorf("Stat with zero ttl = %v, %v; want size 0", fi, err)
+	}
+
+	var wg sync.WaitGroup
+	c = NewStatCache(time.Minute)
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				if _, err := c.Stat(name); err != nil {
+					t.Error(err)
+					return
+				}
+				c.Invalidate(name)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Expired entries for distinct names do not accumulate.
+	c = NewStatCache(time.Nanosecond)
+	for i := 0; i < 1000; i++ {
+		c.Stat(fmt.Sprint(name, ".missing", i))
+	}
+	if n := StatCacheLen(c); n > 128 {
+		t.Errorf("cache holds %d entries after 1000 expired ones, want at most 128", n)
+	}
 }
 
-func TestReadFileProc(t *testing.T) {
-	// Linux files in /proc report 0 size,
-	// but then if ReadFile reads just a single byte at offset 0,
-	// the read at offset 1 returns EOF instead of more data.
-	// ReadFile has a minimum read size of 512 to work around this,
-	// but test explicitly that it's working.
-	name := "/proc/sys/fs/pipe-max-size"
-	if _, err := Stat(name); err != nil {
-		t.Skip(err)
+func TestAsyncFile(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "log")
+	a, err := OpenAsync(name, O_WRONLY|O_CREATE|O_APPEND, 0644, 16)
+	if err != nil {
+		t.Fatal(err)
 	}
-	data, err := ReadFile(name)
+	var want bytes.Buffer
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				line := fmt.Sprintf("%d:%d\n", i, j)
+				mu.Lock()
+				want.WriteString(line)
+				n, err := a.Write([]byte(line))
+				mu.Unlock()
+				if err != nil || n != len(line) {
+					t.Errorf("Write = %d, %v; want %d, nil", n, err, len(line))
+					return
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+	// A write larger than the buffer is split.
+	big := strings.Repeat("x", 100)
+	want.WriteString(big)
+	if _, err := a.Write([]byte(big)); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Close(); err != nil {
+		t.Fatal(err)
+	}
+	got, err := ReadFile(name)
 	if err != nil {
 		t.Fatal(err)
 	}
-	if len(data) == 0 || data[len(data)-1] != '\n' {
-		t.Fatalf("read %s: not newline-terminated: %q", name, data)
+	if !bytes.Equal(got, want.Bytes()) {
+		t.Errorf("file contains %q, want %q", got, want.Bytes())
+	}
+	if _, err := a.Write([]byte("x")); !errors.Is(err, ErrClosed) {
+		t.Errorf("Write after Close: got %v, want ErrClosed", err)
 	}
 }
 
-func TestWriteStringAlloc(t *testing.T) {
-	if runtime.GOOS == "js" {
-		t.Skip("js allocates a lot during File.WriteString")
+func TestAsyncFileError(t *testing.T) {
+	// Writes to a file opened read-only fail in the background.
+	name := filepath.Join(t.TempDir(), "ro")
+	if err := WriteFile(name, nil, 0644); err != nil {
+		t.Fatal(err)
 	}
-	d := t.TempDir()
-	f, err := Create(filepath.Join(d, "whiteboard.txt"))
+	a, err := OpenAsync(name, O_RDONLY, 0, 0)
 	if err != nil {
 		t.Fatal(err)
 	}
-	defer f.Close()
-	allocs := testing.AllocsPerRun(100, func() {
-		f.WriteString("I will not allocate when passed a string longer than 32 bytes.\n")
-	})
-	if allocs != 0 {
-		t.Errorf("expected 0 allocs for File.WriteString, got %v", allocs)
+	if _, err := a.Write([]byte("lost")); err != nil {
+		t.Fatalf("first Write: %v", err)
+	}
+	flushErr := a.Flush()
+	var pe *PathError
+	if !errors.As(flushErr, &pe) || pe.Op != "write" {
+		t.Fatalf("Flush: got %v, want write error", flushErr)
+	}
+	if _, err := a.Write([]byte("more")); err != flushErr {
+		t.Errorf("Write after failure: got %v, want %v", err, flushErr)
+	}
+	if err := a.Close(); err != flushErr {
+		t.Errorf("Close: got %v, want %v", err, flushErr)
 	}
 }
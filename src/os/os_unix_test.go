@@ -8,9 +8,13 @@
 package os_test
 
 import (
+	"errors"
+	"fmt"
+	"internal/testenv"
 	"io"
 	"os"
 	. "os"
+	osexec "os/exec"
 	"path/filepath"
 	"runtime"
 	"strings"
@@ -321,3 +325,1182 @@ func TestSplitPath(t *testing.T) {
 		}
 	}
 }
+
+func TestWithUmask(t *testing.T) {
+	dir := t.TempDir()
+	old := Umask(022)
+	defer Umask(old)
+
+	name := filepath.Join(dir, "f")
+	WithUmask(077, func() {
+		if err := WriteFile(name, nil, 0666); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if got := Umask(022); got != 022 {
+		t.Errorf("umask after WithUmask = %#o, want %#o", got, 022)
+	}
+	fi, err := Stat(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := fi.Mode().Perm(); perm != 0600 {
+		t.Errorf("mode = %#o, want %#o", perm, 0600)
+	}
+}
+
+func TestCreateExact(t *testing.T) {
+	dir := t.TempDir()
+	old := Umask(077)
+	defer Umask(old)
+
+	name := filepath.Join(dir, "f")
+	for _, perm := range []FileMode{0666, 0604} {
+		f, err := CreateExact(name, perm)
+		if err != nil {
+			t.Fatal(err)
+		}
+		f.Close()
+		fi, err := Stat(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := fi.Mode().Perm(); got != perm {
+			t.Errorf("CreateExact(%#o): mode = %#o", perm, got)
+		}
+	}
+
+	// An existing, more accessible file is restricted and truncated.
+	if err := WriteFile(name, []byte("old"), 0666); err != nil {
+		t.Fatal(err)
+	}
+	if err := Chmod(name, 0666); err != nil {
+		t.Fatal(err)
+	}
+	f, err := CreateExact(name, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fi, err := f.Stat()
+	f.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fi.Mode().Perm() != 0600 || fi.Size() != 0 {
+		t.Errorf("CreateExact of existing file: mode = %#o, size = %d; want 0600, 0", fi.Mode().Perm(), fi.Size())
+	}
+}
+
+func TestLchmod(t *testing.T) {
+	if runtime.GOOS == "aix" || runtime.GOOS == "js" || runtime.GOOS == "solaris" || runtime.GOOS == "illumos" {
+		t.Skipf("Lchmod is not supported on %s", runtime.GOOS)
+	}
+	testenv.MustHaveSymlink(t)
+	dir := t.TempDir()
+	target := filepath.Join(dir, "target")
+	if err := WriteFile(target, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := Chmod(target, 0644); err != nil {
+		t.Fatal(err)
+	}
+	link := filepath.Join(dir, "link")
+	if err := Symlink(target, link); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Lchmod(target, 0600); err != nil {
+		t.Fatalf("Lchmod on a regular file: %v", err)
+	}
+	if fi, err := Stat(target); err != nil {
+		t.Fatal(err)
+	} else if fi.Mode().Perm() != 0600 {
+		t.Errorf("mode after Lchmod = %#o, want %#o", fi.Mode().Perm(), 0600)
+	}
+
+	err := Lchmod(link, 0700)
+	if err != nil && !errors.Is(err, ErrUnsupported) {
+		t.Fatalf("Lchmod on a symlink: %v", err)
+	}
+	if runtime.GOOS == "linux" && err == nil {
+		t.Error("Lchmod on a symlink succeeded on Linux")
+	}
+	if fi, err := Stat(target); err != nil {
+		t.Fatal(err)
+	} else if fi.Mode().Perm() != 0600 {
+		t.Errorf("Lchmod on the link changed the target's mode to %#o", fi.Mode().Perm())
+	}
+}
+
+func TestTrash(t *testing.T) {
+	switch runtime.GOOS {
+	case "android", "ios", "js":
+		t.Skipf("no trash on %s", runtime.GOOS)
+	}
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_DATA_HOME", "")
+	trashFiles := filepath.Join(home, ".local", "share", "Trash", "files")
+	if runtime.GOOS == "darwin" {
+		trashFiles = filepath.Join(home, ".Trash")
+		if err := Mkdir(trashFiles, 0700); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	dir := filepath.Join(home, "work")
+	if err := Mkdir(dir, 0777); err != nil {
+		t.Fatal(err)
+	}
+	name := filepath.Join(dir, "a file.txt")
+	for i := 0; i < 2; i++ {
+		if err := WriteFile(name, []byte{byte('0' + i)}, 0666); err != nil {
+			t.Fatal(err)
+		}
+		if err := Trash(name); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := Lstat(name); !IsNotExist(err) {
+			t.Fatalf("file still present after Trash: %v", err)
+		}
+	}
+	entries, err := ReadDir(trashFiles)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("trash holds %d entries, want 2", len(entries))
+	}
+	for _, e := range entries {
+		t.Logf("trashed as %q", e.Name())
+	}
+
+	if runtime.GOOS != "darwin" {
+		info, err := ReadFile(filepath.Join(home, ".local", "share", "Trash", "info", "a file.txt.trashinfo"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := "[Trash Info]\nPath=" + strings.ReplaceAll(name, " ", "%20") + "\nDeletionDate="
+		if !strings.HasPrefix(string(info), want) {
+			t.Errorf("trashinfo is %q, want prefix %q", info, want)
+		}
+	}
+
+	if err := Trash(name); !IsNotExist(err) {
+		t.Errorf("Trash of missing file: got %v, want not exist", err)
+	}
+}
+
+func TestOpenDefault(t *testing.T) {
+	launcher := "xdg-open"
+	switch runtime.GOOS {
+	case "aix", "android", "ios", "js":
+		t.Skipf("OpenDefault is not supported on %s", runtime.GOOS)
+	case "darwin":
+		launcher = "open"
+	}
+	if _, err := Stat("/bin/sh"); err != nil {
+		t.Skip("no /bin/sh")
+	}
+	dir := t.TempDir()
+	out := filepath.Join(dir, "out")
+	t.Setenv("PATH", dir+string(PathListSeparator)+Getenv("PATH"))
+
+	setLauncher := func(script string) {
+		t.Helper()
+		if err := WriteFile(filepath.Join(dir, launcher), []byte("#!/bin/sh\n"+script+"\n"), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	setLauncher(`echo "$1" >` + out)
+	for _, target := range []string{"https://golang.org/", "-file"} {
+		if err := OpenDefault(target); err != nil {
+			t.Fatalf("OpenDefault(%q): %v", target, err)
+		}
+		got, err := ReadFile(out)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := target
+		if target[0] == '-' {
+			want = "./" + target
+		}
+		if string(got) != want+"\n" {
+			t.Errorf("OpenDefault(%q) passed %q to %s, want %q", target, got, launcher, want)
+		}
+	}
+
+	setLauncher("exit 3")
+	if err := OpenDefault("x"); err == nil || !strings.Contains(err.Error(), "exit status 3") {
+		t.Errorf("OpenDefault with failing launcher: got %v, want exit status 3", err)
+	}
+
+	setLauncher("sleep 3")
+	start := time.Now()
+	if err := OpenDefault("x"); err != nil {
+		t.Errorf("OpenDefault with slow launcher: %v", err)
+	}
+	if d := time.Since(start); d > 5*time.Second {
+		t.Errorf("OpenDefault waited %v for slow launcher", d)
+	}
+}
+
+func TestSdNotify(t *testing.T) {
+	t.Setenv("NOTIFY_SOCKET", "")
+	if ok, err := SdNotify("READY=1"); ok || err != nil {
+		t.Errorf("SdNotify without NOTIFY_SOCKET = %v, %v; want false, nil", ok, err)
+	}
+	if runtime.GOOS != "linux" {
+		return
+	}
+
+	addr := filepath.Join(t.TempDir(), "notify")
+	fd, err := syscall.Socket(syscall.AF_UNIX, syscall.SOCK_DGRAM, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer syscall.Close(fd)
+	if err := syscall.Bind(fd, &syscall.SockaddrUnix{Name: addr}); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("NOTIFY_SOCKET", addr)
+	const state = "READY=1\nSTATUS=serving"
+	if ok, err := SdNotify(state); !ok || err != nil {
+		t.Fatalf("SdNotify = %v, %v; want true, nil", ok, err)
+	}
+	buf := make([]byte, 128)
+	n, _, err := syscall.Recvfrom(fd, buf, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(buf[:n]); got != state {
+		t.Errorf("service manager received %q, want %q", got, state)
+	}
+
+	t.Setenv("NOTIFY_SOCKET", addr+".missing")
+	if ok, err := SdNotify(state); ok || err == nil {
+		t.Errorf("SdNotify to missing socket = %v, %v; want false and an error", ok, err)
+	}
+}
+
+func TestSdWatchdogEnabled(t *testing.T) {
+	pid := fmt.Sprint(Getpid())
+	for _, tt := range []struct {
+		usec, pid string
+		want      time.Duration
+		ok        bool
+	}{
+		{"", "", 0, false},
+		{"30000000", "", 15 * time.Second, true},
+		{"30000000", pid, 15 * time.Second, true},
+		{"30000000", "1", 0, false},
+		{"30000000", "x", 0, false},
+		{"0", "", 0, false},
+		{"-5", "", 0, false},
+		{"99999999999999999999", "", 0, false},
+	} {
+		t.Setenv("WATCHDOG_USEC", tt.usec)
+		t.Setenv("WATCHDOG_PID", tt.pid)
+		if d, ok := SdWatchdogEnabled(); d != tt.want || ok != tt.ok {
+			t.Errorf("WATCHDOG_USEC=%q WATCHDOG_PID=%q: SdWatchdogEnabled() = %v, %v; want %v, %v", tt.usec, tt.pid, d, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestListenFds(t *testing.T) {
+	if Getenv("GO_WANT_HELPER_PROCESS") == "1" {
+		files, err := ListenFds()
+		if err != nil {
+			fmt.Print(err)
+			Exit(0)
+		}
+		for _, f := range files {
+			b, err := io.ReadAll(f)
+			fmt.Printf("%s=%s,%v;", f.Name(), b, err)
+		}
+		for _, env := range []string{"LISTEN_PID", "LISTEN_FDS", "LISTEN_FDNAMES"} {
+			if _, ok := LookupEnv(env); ok {
+				fmt.Printf("%s still set;", env)
+			}
+		}
+		Exit(0)
+	}
+	switch runtime.GOOS {
+	case "aix", "darwin", "dragonfly", "freebsd", "linux", "netbsd", "openbsd", "solaris", "illumos":
+	default:
+		t.Skipf("ListenFds not supported on %s", runtime.GOOS)
+	}
+	testenv.MustHaveExec(t)
+	if _, err := Stat("/bin/sh"); err != nil {
+		t.Skip("no /bin/sh")
+	}
+
+	run := func(pid string, env ...string) string {
+		t.Helper()
+		var extra []*File
+		for _, s := range []string{"a", "b"} {
+			r, w, err := Pipe()
+			if err != nil {
+				t.Fatal(err)
+			}
+			w.WriteString(s)
+			w.Close()
+			defer r.Close()
+			extra = append(extra, r)
+		}
+		// The shell sets LISTEN_PID to its own process ID,
+		// which the test binary inherits through exec.
+		cmd := osexec.Command("/bin/sh", "-c", `LISTEN_PID=`+pid+` exec "$0" -test.run=^TestListenFds$`, Args[0])
+		cmd.Env = append(Environ(), "GO_WANT_HELPER_PROCESS=1")
+		cmd.Env = append(cmd.Env, env...)
+		cmd.ExtraFiles = extra
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("helper process failed: %v\n%s", err, out)
+		}
+		return string(out)
+	}
+
+	for _, tt := range []struct {
+		pid  string
+		env  []string
+		want string
+	}{
+		{"$$", []string{"LISTEN_FDS=2"}, "LISTEN_FD_3=a,<nil>;LISTEN_FD_4=b,<nil>;"},
+		{"$$", []string{"LISTEN_FDS=2", "LISTEN_FDNAMES=http:admin"}, "http=a,<nil>;admin=b,<nil>;"},
+		{"$$", []string{"LISTEN_FDS=1"}, "LISTEN_FD_3=a,<nil>;"},
+		{"1", []string{"LISTEN_FDS=2"}, ""},
+		{"$$", []string{"LISTEN_FDS=2", "LISTEN_FDNAMES=http"}, "os: invalid LISTEN_FDNAMES value http"},
+	} {
+		if got := run(tt.pid, tt.env...); got != tt.want {
+			t.Errorf("LISTEN_PID=%s %q: got %q, want %q", tt.pid, tt.env, got, tt.want)
+		}
+	}
+}
+
+func TestSetsid(t *testing.T) {
+	if Getenv("GO_WANT_HELPER_PROCESS") == "1" {
+		sid, err := Setsid()
+		fmt.Print(sid == Getpid(), err)
+		// The session leader now leads its own process group,
+		// and so cannot create another session.
+		_, err = Setsid()
+		fmt.Print(";", errors.Is(err, syscall.EPERM))
+		Exit(0)
+	}
+	switch runtime.GOOS {
+	case "aix", "js":
+		if _, err := Setsid(); !errors.Is(err, ErrUnsupported) {
+			t.Errorf("Setsid: got %v, want ErrUnsupported", err)
+		}
+		return
+	}
+	testenv.MustHaveExec(t)
+
+	// The test process may or may not be a process group leader,
+	// but a child started by exec is not unless asked to be.
+	cmd := osexec.Command(Args[0], "-test.run=^TestSetsid$")
+	cmd.Env = append(Environ(), "GO_WANT_HELPER_PROCESS=1")
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("helper process failed: %v\n%s", err, out)
+	}
+	if got, want := string(out), "true <nil>;true"; got != want {
+		t.Errorf("Setsid in child: got %q, want %q", got, want)
+	}
+}
+
+func TestControllingTerminal(t *testing.T) {
+	if Getenv("GO_WANT_HELPER_PROCESS") == "1" {
+		// A new session has no controlling terminal to detach.
+		if _, err := Setsid(); err != nil {
+			fmt.Print(err)
+			Exit(0)
+		}
+		fmt.Print(DetachControllingTerminal() != nil)
+		Exit(0)
+	}
+	switch runtime.GOOS {
+	case "aix", "js":
+		if err := DetachControllingTerminal(); !errors.Is(err, ErrUnsupported) {
+			t.Errorf("DetachControllingTerminal: got %v, want ErrUnsupported", err)
+		}
+		return
+	}
+
+	r, w, err := Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	defer w.Close()
+	if err := r.SetControllingTerminal(); !errors.Is(err, syscall.ENOTTY) {
+		t.Errorf("SetControllingTerminal on pipe: got %v, want ENOTTY", err)
+	}
+
+	testenv.MustHaveExec(t)
+	cmd := osexec.Command(Args[0], "-test.run=^TestControllingTerminal$")
+	cmd.Env = append(Environ(), "GO_WANT_HELPER_PROCESS=1")
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("helper process failed: %v\n%s", err, out)
+	}
+	if got, want := string(out), "true"; got != want {
+		t.Errorf("DetachControllingTerminal in new session: got %q, want %q", got, want)
+	}
+}
+
+func TestOpenPty(t *testing.T) {
+	if Getenv("GO_WANT_HELPER_PROCESS") == "1" {
+		if _, err := Setsid(); err != nil {
+			fmt.Print(err)
+			Exit(0)
+		}
+		tty := NewFile(3, "slave")
+		if err := tty.SetControllingTerminal(); err != nil {
+			fmt.Print(err)
+			Exit(0)
+		}
+		// Detaching would hang up the session, so just check
+		// that the terminal is now the controlling terminal.
+		f, err := Open("/dev/tty")
+		if err == nil {
+			f.Close()
+		}
+		fmt.Print(err)
+		Exit(0)
+	}
+	master, slave, name, err := OpenPty()
+	switch runtime.GOOS {
+	case "darwin", "freebsd", "linux":
+		if err != nil {
+			t.Skipf("OpenPty: %v", err)
+		}
+	default:
+		if !errors.Is(err, ErrUnsupported) {
+			t.Errorf("OpenPty: got %v, want ErrUnsupported", err)
+		}
+		return
+	}
+	defer master.Close()
+	defer slave.Close()
+	if slave.Name() != name {
+		t.Errorf("slave.Name() = %q, want %q", slave.Name(), name)
+	}
+
+	if _, err := master.WriteString("hello\n"); err != nil {
+		t.Fatal(err)
+	}
+	buf := make([]byte, 64)
+	n, err := slave.Read(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(buf[:n]); got != "hello\n" {
+		t.Errorf("read from slave: got %q, want %q", got, "hello\n")
+	}
+
+	testenv.MustHaveExec(t)
+	cmd := osexec.Command(Args[0], "-test.run=^TestOpenPty$")
+	cmd.Env = append(Environ(), "GO_WANT_HELPER_PROCESS=1")
+	cmd.ExtraFiles = []*File{slave}
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("helper process failed: %v\n%s", err, out)
+	}
+	if got, want := string(out), "<nil>"; got != want {
+		t.Errorf("controlling terminal in child: got %q, want %q", got, want)
+	}
+}
+
+func TestWinsize(t *testing.T) {
+	r, w, err := Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	defer w.Close()
+	_, _, _, _, err = r.GetWinsize()
+	switch runtime.GOOS {
+	case "aix", "js":
+		if !errors.Is(err, ErrUnsupported) {
+			t.Errorf("GetWinsize: got %v, want ErrUnsupported", err)
+		}
+		return
+	}
+	if !errors.Is(err, syscall.ENOTTY) {
+		t.Errorf("GetWinsize on pipe: got %v, want ENOTTY", err)
+	}
+
+	master, slave, _, err := OpenPty()
+	if err != nil {
+		t.Skipf("OpenPty: %v", err)
+	}
+	defer master.Close()
+	defer slave.Close()
+	if err := master.SetWinsize(24, 80, 640, 480); err != nil {
+		t.Fatal(err)
+	}
+	// The size belongs to the terminal, not to either side of it.
+	rows, cols, xpixel, ypixel, err := slave.GetWinsize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rows != 24 || cols != 80 || xpixel != 640 || ypixel != 480 {
+		t.Errorf("GetWinsize = %d, %d, %d, %d; want 24, 80, 640, 480", rows, cols, xpixel, ypixel)
+	}
+}
+
+func TestSignalfd(t *testing.T) {
+	if Getenv("GO_WANT_HELPER_PROCESS") == "1" {
+		f, err := Signalfd(syscall.SIGTERM)
+		if err != nil {
+			fmt.Print(err)
+			Exit(0)
+		}
+		defer f.Close()
+		// Without the signalfd, SIGTERM would end the process.
+		syscall.Kill(Getpid(), syscall.SIGTERM)
+		f.SetReadDeadline(time.Now().Add(10 * time.Second))
+		info, err := f.ReadSignal()
+		fmt.Print(info.Signal, ";", info.Pid == Getpid(), ";", err)
+		Exit(0)
+	}
+	if runtime.GOOS != "linux" {
+		if _, err := Signalfd(syscall.SIGTERM); !errors.Is(err, ErrUnsupported) {
+			t.Errorf("Signalfd: got %v, want ErrUnsupported", err)
+		}
+		return
+	}
+	if _, err := Signalfd(syscall.SIGKILL); !errors.Is(err, syscall.EINVAL) {
+		t.Errorf("Signalfd(SIGKILL): got %v, want EINVAL", err)
+	}
+
+	// Signalfd blocks the signal for good, so use a child process.
+	testenv.MustHaveExec(t)
+	cmd := osexec.Command(Args[0], "-test.run=^TestSignalfd$")
+	cmd.Env = append(Environ(), "GO_WANT_HELPER_PROCESS=1")
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("helper process failed: %v\n%s", err, out)
+	}
+	if got, want := string(out), syscall.SIGTERM.String()+";true;<nil>"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestTimerfd(t *testing.T) {
+	f, err := Timerfd(0, time.Millisecond)
+	if runtime.GOOS != "linux" {
+		if !errors.Is(err, ErrUnsupported) {
+			t.Errorf("Timerfd: got %v, want ErrUnsupported", err)
+		}
+		return
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	f.SetReadDeadline(time.Now().Add(10 * time.Second))
+	if n, err := f.ReadTimerfd(); err != nil || n != 1 {
+		t.Fatalf("one-shot ReadTimerfd = %d, %v; want 1, nil", n, err)
+	}
+
+	if err := f.ResetTimerfd(time.Millisecond, 0); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(20 * time.Millisecond)
+	if n, err := f.ReadTimerfd(); err != nil || n < 2 {
+		t.Fatalf("periodic ReadTimerfd = %d, %v; want at least 2", n, err)
+	}
+
+	// A disarmed timer never becomes readable.
+	if err := f.ResetTimerfd(0, 0); err != nil {
+		t.Fatal(err)
+	}
+	f.SetReadDeadline(time.Now().Add(20 * time.Millisecond))
+	if _, err := f.ReadTimerfd(); !errors.Is(err, ErrDeadlineExceeded) {
+		t.Errorf("disarmed ReadTimerfd: got %v, want ErrDeadlineExceeded", err)
+	}
+
+	if err := f.ResetTimerfd(-1, 0); !errors.Is(err, ErrInvalid) {
+		t.Errorf("ResetTimerfd(-1, 0): got %v, want ErrInvalid", err)
+	}
+}
+
+func TestEventfd(t *testing.T) {
+	f, err := Eventfd(2, false)
+	if runtime.GOOS != "linux" {
+		if !errors.Is(err, ErrUnsupported) {
+			t.Errorf("Eventfd: got %v, want ErrUnsupported", err)
+		}
+		return
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if err := f.WriteEventfd(3); err != nil {
+		t.Fatal(err)
+	}
+	if n, err := f.ReadEventfd(); err != nil || n != 5 {
+		t.Fatalf("ReadEventfd = %d, %v; want 5, nil", n, err)
+	}
+	f.SetReadDeadline(time.Now().Add(20 * time.Millisecond))
+	if _, err := f.ReadEventfd(); !errors.Is(err, ErrDeadlineExceeded) {
+		t.Errorf("ReadEventfd of zero counter: got %v, want ErrDeadlineExceeded", err)
+	}
+
+	sem, err := Eventfd(0, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sem.Close()
+	go sem.WriteEventfd(2)
+	sem.SetReadDeadline(time.Now().Add(10 * time.Second))
+	for i := 0; i < 2; i++ {
+		if n, err := sem.ReadEventfd(); err != nil || n != 1 {
+			t.Fatalf("semaphore ReadEventfd = %d, %v; want 1, nil", n, err)
+		}
+	}
+}
+
+func TestMemfdSeals(t *testing.T) {
+	f, err := MemfdCreate("test", MFD_ALLOW_SEALING)
+	if runtime.GOOS != "linux" {
+		if !errors.Is(err, ErrUnsupported) {
+			t.Errorf("MemfdCreate: got %v, want ErrUnsupported", err)
+		}
+		return
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if _, err := f.WriteString("sealed"); err != nil {
+		t.Fatal(err)
+	}
+	const seals = SEAL_SHRINK | SEAL_GROW | SEAL_WRITE | SEAL_SEAL
+	if err := f.AddSeals(seals); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := f.GetSeals(); err != nil || got != seals {
+		t.Errorf("GetSeals = %#x, %v; want %#x, nil", got, err, seals)
+	}
+	if _, err := f.WriteAt([]byte("x"), 0); !errors.Is(err, syscall.EPERM) {
+		t.Errorf("write to sealed file: got %v, want EPERM", err)
+	}
+	if err := f.Truncate(0); !errors.Is(err, syscall.EPERM) {
+		t.Errorf("truncate of sealed file: got %v, want EPERM", err)
+	}
+	b := make([]byte, 6)
+	if _, err := f.ReadAt(b, 0); err != nil || string(b) != "sealed" {
+		t.Errorf("ReadAt = %q, %v; want %q", b, err, "sealed")
+	}
+
+	// Without MFD_ALLOW_SEALING the file starts out sealed against seals.
+	g, err := MemfdCreate("test", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer g.Close()
+	if got, err := g.GetSeals(); err != nil || got != SEAL_SEAL {
+		t.Errorf("GetSeals = %#x, %v; want SEAL_SEAL", got, err)
+	}
+	if err := g.AddSeals(SEAL_WRITE); !errors.Is(err, syscall.EPERM) {
+		t.Errorf("AddSeals without MFD_ALLOW_SEALING: got %v, want EPERM", err)
+	}
+}
+
+func TestCopyFileRangeOffsets(t *testing.T) {
+	dir := t.TempDir()
+	src, err := Create(filepath.Join(dir, "src"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer src.Close()
+	dst, err := Create(filepath.Join(dir, "dst"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dst.Close()
+	if _, err := src.WriteString("0123456789"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := dst.WriteString("abcdefghij"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := src.Seek(1, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+
+	n, err := CopyFileRange(dst, 2, src, 4, 3)
+	if runtime.GOOS != "linux" {
+		if !errors.Is(err, ErrUnsupported) {
+			t.Errorf("CopyFileRange: got %v, want ErrUnsupported", err)
+		}
+		return
+	}
+	if errors.Is(err, ErrUnsupported) {
+		t.Skipf("copy_file_range not supported: %v", err)
+	}
+	if err != nil || n != 3 {
+		t.Fatalf("CopyFileRange = %d, %v; want 3, nil", n, err)
+	}
+	// Copying past the end of src copies only what is there.
+	n, err = CopyFileRange(dst, 10, src, 8, 5)
+	if err != nil || n != 2 {
+		t.Fatalf("CopyFileRange at end of file = %d, %v; want 2, nil", n, err)
+	}
+
+	if off, err := src.Seek(0, io.SeekCurrent); err != nil || off != 1 {
+		t.Errorf("src offset = %d, %v; want 1", off, err)
+	}
+	if off, err := dst.Seek(0, io.SeekCurrent); err != nil || off != 10 {
+		t.Errorf("dst offset = %d, %v; want 10", off, err)
+	}
+	b, err := ReadFile(dst.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "ab456fghij89"; string(b) != want {
+		t.Errorf("dst contents = %q, want %q", b, want)
+	}
+}
+
+func TestCloneFile(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "src")
+	dst := filepath.Join(dir, "dst")
+	if err := WriteFile(src, []byte("hello, clone"), 0640); err != nil {
+		t.Fatal(err)
+	}
+
+	err := CloneFile(dst, src)
+	switch runtime.GOOS {
+	case "darwin", "linux":
+	default:
+		if !errors.Is(err, ErrUnsupported) {
+			t.Errorf("CloneFile: got %v, want ErrUnsupported", err)
+		}
+		return
+	}
+	if err := CloneFile(src, src); !errors.Is(err, ErrExist) {
+		t.Errorf("CloneFile to existing file: got %v, want ErrExist", err)
+	}
+	if errors.Is(err, ErrUnsupported) {
+		if _, err := Lstat(dst); !IsNotExist(err) {
+			t.Errorf("failed CloneFile left %s behind: %v", dst, err)
+		}
+		t.Skipf("file system does not support clones: %v", err)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := ReadFile(dst)
+	if err != nil || string(b) != "hello, clone" {
+		t.Errorf("ReadFile(dst) = %q, %v; want %q", b, err, "hello, clone")
+	}
+	// Writing to the clone does not change the original.
+	if err := WriteFile(dst, []byte("changed"), 0); err != nil {
+		t.Fatal(err)
+	}
+	if b, err := ReadFile(src); err != nil || string(b) != "hello, clone" {
+		t.Errorf("ReadFile(src) = %q, %v; want %q", b, err, "hello, clone")
+	}
+}
+
+func TestDedupeRange(t *testing.T) {
+	dir := t.TempDir()
+	data := []byte(strings.Repeat("0123456789abcdef", 4096))
+	files := make([]*File, 3)
+	for i := range files {
+		f, err := Create(filepath.Join(dir, fmt.Sprint("f", i)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+		if _, err := f.Write(data); err != nil {
+			t.Fatal(err)
+		}
+		files[i] = f
+	}
+	// The last file differs from the others.
+	if _, err := files[2].WriteAt([]byte("x"), 100); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := DedupeRange(files[0], 0, 4096, []DedupeTarget{{File: nil}}); err != ErrInvalid {
+		t.Errorf("DedupeRange with nil target: got %v, want ErrInvalid", err)
+	}
+
+	targets := []DedupeTarget{{File: files[1]}, {File: files[2]}}
+	results, err := DedupeRange(files[0], 0, int64(len(data)), targets)
+	if runtime.GOOS != "linux" {
+		if !errors.Is(err, ErrUnsupported) {
+			t.Errorf("DedupeRange: got %v, want ErrUnsupported", err)
+		}
+		return
+	}
+	if errors.Is(err, ErrUnsupported) {
+		t.Skipf("file system does not support deduplication: %v", err)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("got %d results, want 2", len(results))
+	}
+	if r := results[0]; r.Err != nil || r.Differs || r.Bytes <= 0 {
+		t.Errorf("identical target: got %+v, want bytes deduplicated", r)
+	}
+	if r := results[1]; r.Err != nil || !r.Differs {
+		t.Errorf("differing target: got %+v, want Differs", r)
+	}
+}
+
+func TestFileExtents(t *testing.T) {
+	f, err := Create(filepath.Join(t.TempDir(), "sparse"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	const mb = 1 << 20
+	block := []byte(strings.Repeat("x", 4096))
+	if _, err := f.WriteAt(block, 0); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.WriteAt(block, mb); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Truncate(2 * mb); err != nil {
+		t.Fatal(err)
+	}
+
+	extents, err := FileExtents(f)
+	if runtime.GOOS != "linux" {
+		if !errors.Is(err, ErrUnsupported) {
+			t.Errorf("FileExtents: got %v, want ErrUnsupported", err)
+		}
+		return
+	}
+	if errors.Is(err, ErrUnsupported) {
+		t.Skipf("file system does not support FIEMAP: %v", err)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Logf("extents: %+v", extents)
+
+	var next int64
+	for _, e := range extents {
+		if e.Logical != next {
+			t.Fatalf("extent %+v does not start at %d", e, next)
+		}
+		next += e.Length
+	}
+	if next < 2*mb {
+		t.Errorf("extents end at %d, want at least %d", next, 2*mb)
+	}
+	isHole := func(off int64) bool {
+		for _, e := range extents {
+			if off >= e.Logical && off < e.Logical+e.Length {
+				return e.Flags&ExtentHole != 0
+			}
+		}
+		return false
+	}
+	for _, off := range []int64{0, mb} {
+		if isHole(off) {
+			t.Errorf("offset %d is in a hole", off)
+		}
+	}
+	for _, off := range []int64{mb / 2, mb + mb/2} {
+		if !isHole(off) {
+			t.Errorf("offset %d is not in a hole", off)
+		}
+	}
+}
+
+func TestProjectQuota(t *testing.T) {
+	dir := t.TempDir()
+	d, err := Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer d.Close()
+
+	// Project ID 0 is the default, so setting it works wherever
+	// project IDs are supported, even without quotas.
+	err = SetProjectID(d, 0)
+	if runtime.GOOS != "linux" {
+		if !errors.Is(err, ErrUnsupported) {
+			t.Errorf("SetProjectID: got %v, want ErrUnsupported", err)
+		}
+		if _, _, err := ProjectQuota(dir); !errors.Is(err, ErrUnsupported) {
+			t.Errorf("ProjectQuota: got %v, want ErrUnsupported", err)
+		}
+		return
+	}
+	if err != nil && !errors.Is(err, ErrUnsupported) {
+		t.Errorf("SetProjectID: %v", err)
+	}
+
+	used, limit, err := ProjectQuota(dir)
+	if errors.Is(err, ErrUnsupported) || errors.Is(err, ErrPermission) {
+		t.Skipf("project quotas not available: %v", err)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	if used < 0 || limit < -1 {
+		t.Errorf("ProjectQuota = %d, %d; want non-negative usage and limit", used, limit)
+	}
+}
+
+func TestIOUring(t *testing.T) {
+	r, err := NewIOUring(4)
+	if runtime.GOOS != "linux" {
+		if !errors.Is(err, ErrUnsupported) {
+			t.Errorf("NewIOUring: got %v, want ErrUnsupported", err)
+		}
+		return
+	}
+	if errors.Is(err, ErrUnsupported) || errors.Is(err, ErrPermission) {
+		t.Skipf("io_uring not available: %v", err)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	f, err := Create(filepath.Join(t.TempDir(), "ring"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	waitAll := func(n int) map[uint64]IOUringCompletion {
+		t.Helper()
+		results := make(map[uint64]IOUringCompletion)
+		buf := make([]IOUringCompletion, 3)
+		for len(results) < n {
+			m, err := r.Wait(buf)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if m == 0 {
+				t.Fatalf("Wait returned no completions with %d operations in flight", n-len(results))
+			}
+			for _, c := range buf[:m] {
+				results[c.UserData] = c
+			}
+		}
+		return results
+	}
+
+	// Write four chunks in one batch, then read them back.
+	chunks := []string{"zero ", "one ", "two ", "three"}
+	var off int64
+	for i, c := range chunks {
+		if err := r.WriteAt(f, []byte(c), off, uint64(i)); err != nil {
+			t.Fatal(err)
+		}
+		off += int64(len(c))
+	}
+	if n, err := r.Submit(); err != nil || n != len(chunks) {
+		t.Fatalf("Submit = %d, %v; want %d, nil", n, err, len(chunks))
+	}
+	for i, c := range waitAll(len(chunks)) {
+		if c.Err != nil || c.N != len(chunks[i]) {
+			t.Errorf("write %d: got %+v", i, c)
+		}
+	}
+	if err := r.Fsync(f, 0); err != nil {
+		t.Fatal(err)
+	}
+	r.Submit()
+	if c := waitAll(1)[0]; c.Err != nil {
+		t.Errorf("fsync: %v", c.Err)
+	}
+
+	got := make([]byte, off)
+	if err := r.ReadAt(f, got[:5], 0, 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.ReadAt(f, got[5:], 5, 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.ReadAt(f, make([]byte, 1), off, 2); err != nil {
+		t.Fatal(err)
+	}
+	r.Submit()
+	results := waitAll(3)
+	if want := strings.Join(chunks, ""); string(got) != want {
+		t.Errorf("read %q, want %q", got, want)
+	}
+	if c := results[2]; c.Err != io.EOF {
+		t.Errorf("read at end of file: got %+v, want io.EOF", c)
+	}
+
+	// A read from an empty pipe completes only once data is written,
+	// and Wait blocks until then.
+	pr, pw, err := Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pr.Close()
+	defer pw.Close()
+	b := make([]byte, 10)
+	if err := r.ReadAt(pr, b, 0, 7); err != nil {
+		t.Fatal(err)
+	}
+	r.Submit()
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		pw.Write([]byte("ping"))
+	}()
+	if c := waitAll(1)[7]; c.Err != nil || string(b[:c.N]) != "ping" {
+		t.Errorf("read from pipe: got %+v, %q", c, b[:c.N])
+	}
+
+	// Concurrent calls to Wait, and to Close, return each completion
+	// exactly once, and none blocks once nothing is in flight.
+	if err := r.ReadAt(pr, b, 0, 8); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.ReadAt(pr, b[5:], 0, 9); err != nil {
+		t.Fatal(err)
+	}
+	r.Submit()
+	collected := make(chan uint64, 4)
+	done := make(chan error, 3)
+	for i := 0; i < 2; i++ {
+		go func() {
+			buf := make([]IOUringCompletion, 1)
+			for {
+				n, err := r.Wait(buf)
+				if err != nil {
+					done <- err
+					return
+				}
+				if n == 0 {
+					done <- nil
+					return
+				}
+				collected <- buf[0].UserData
+			}
+		}()
+	}
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		pw.Write([]byte("one"))
+		time.Sleep(10 * time.Millisecond)
+		pw.Write([]byte("two"))
+	}()
+	go func() {
+		time.Sleep(5 * time.Millisecond)
+		done <- r.Close()
+	}()
+	for i := 0; i < 3; i++ {
+		select {
+		case err := <-done:
+			if err != nil && err != ErrClosed {
+				t.Errorf("Wait or Close: %v", err)
+			}
+		case <-time.After(10 * time.Second):
+			t.Fatal("Wait or Close did not return")
+		}
+	}
+	// Completions reaped by Close are returned by later calls to Wait.
+	buf := make([]IOUringCompletion, 2)
+	for {
+		n, err := r.Wait(buf)
+		if err == ErrClosed {
+			break
+		}
+		if err != nil || n == 0 {
+			t.Fatalf("Wait after Close = %d, %v; want completions or ErrClosed", n, err)
+		}
+		for _, c := range buf[:n] {
+			collected <- c.UserData
+		}
+	}
+	close(collected)
+	seen := make(map[uint64]bool)
+	for id := range collected {
+		if seen[id] {
+			t.Errorf("completion %d returned twice", id)
+		}
+		seen[id] = true
+	}
+	if !seen[8] || !seen[9] {
+		t.Errorf("got completions %v, want 8 and 9", seen)
+	}
+}
+
+func TestOpenPath(t *testing.T) {
+	dir := t.TempDir()
+	sub := filepath.Join(dir, "sub")
+	if err := Mkdir(sub, 0700); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := OpenPath(sub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	// The handle refers to the directory even after it is renamed.
+	if err := Rename(sub, filepath.Join(dir, "renamed")); err != nil {
+		t.Fatal(err)
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		t.Fatal(err)
+	}
+	fi2, err := Stat(filepath.Join(dir, "renamed"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !fi.IsDir() || !SameFile(fi, fi2) {
+		t.Errorf("Stat of handle = %v, want the renamed directory", fi)
+	}
+	if _, err := OpenPath(sub); !IsNotExist(err) {
+		t.Errorf("OpenPath of missing file: got %v, want not exist error", err)
+	}
+}
+
+func TestCreateLikeOwnerError(t *testing.T) {
+	if Getuid() == 0 {
+		t.Skip("skipping test when running as root")
+	}
+	template := "/"
+	fi, err := Stat(template)
+	if err != nil {
+		t.Skip(err)
+	}
+	if st := fi.Sys().(*syscall.Stat_t); int(st.Uid) == Getuid() {
+		t.Skipf("%s is owned by the current user", template)
+	}
+
+	name := filepath.Join(t.TempDir(), "new")
+	f, err := CreateLikeOwner(name, template)
+	var oe *OwnerError
+	if !errors.As(err, &oe) || oe.Path != name || f == nil {
+		t.Fatalf("CreateLikeOwner with a template owned by another user = %v, %v; want a file and an *OwnerError", f, err)
+	}
+	defer f.Close()
+	if _, err := f.WriteString("data"); err != nil {
+		t.Errorf("writing file returned with an *OwnerError: %v", err)
+	}
+
+	if f, err := CreateLike(name, template); err != nil {
+		t.Errorf("CreateLike with a template owned by another user: %v", err)
+	} else {
+		f.Close()
+	}
+}
@@ -22,6 +22,7 @@ import (
 	"strings"
 	"syscall"
 	"testing"
+	"time"
 	"unicode/utf16"
 	"unsafe"
 )
@@ -1268,3 +1269,117 @@ func TestMkdirDevNull(t *testing.T) {
 		t.Fatalf("error %d is not syscall.ENOTDIR", errno)
 	}
 }
+
+func TestJunction(t *testing.T) {
+	dir := t.TempDir()
+	target := filepath.Join(dir, "target")
+	if err := os.Mkdir(target, 0777); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(target, "file"), []byte("data"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	link := filepath.Join(dir, "link")
+	if err := os.Junction(target, link); err != nil {
+		t.Fatal(err)
+	}
+	fi, err := os.Lstat(link)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fi.Mode()&fs.ModeSymlink == 0 {
+		t.Errorf("Lstat(%q).Mode() = %v, want a symlink", link, fi.Mode())
+	}
+	got, err := os.Readlink(link)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.EqualFold(got, target) {
+		t.Errorf("Readlink(%q) = %q, want %q", link, got, target)
+	}
+	data, err := os.ReadFile(filepath.Join(link, "file"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "data" {
+		t.Errorf("read %q through junction, want %q", data, "data")
+	}
+
+	if err := os.Junction(filepath.Join(target, "file"), filepath.Join(dir, "bad")); err == nil {
+		t.Error("Junction to a regular file succeeded")
+	}
+}
+
+func TestReparseTag(t *testing.T) {
+	dir := t.TempDir()
+	target := filepath.Join(dir, "target")
+	if err := os.Mkdir(target, 0777); err != nil {
+		t.Fatal(err)
+	}
+	link := filepath.Join(dir, "junction")
+	if err := os.Junction(target, link); err != nil {
+		t.Fatal(err)
+	}
+	tag, path, err := os.ReparseTag(link)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tag != os.ReparseTagMountPoint {
+		t.Errorf("tag = %#x, want %#x", tag, os.ReparseTagMountPoint)
+	}
+	if want := `\??\` + target; !strings.EqualFold(path, want) {
+		t.Errorf("path = %q, want %q", path, want)
+	}
+	if _, _, err := os.ReparseTag(target); err == nil {
+		t.Errorf("ReparseTag(%q) on a plain directory succeeded", target)
+	}
+}
+
+func TestRemoveRetrySharingViolation(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(name, nil, 0666); err != nil {
+		t.Fatal(err)
+	}
+	// Files opened by package os do not permit deletion
+	// while they are open.
+	f, err := os.Open(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.RemoveRetry(name, 1, 0); err == nil {
+		f.Close()
+		t.Fatal("RemoveRetry of open file succeeded with one attempt")
+	}
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		f.Close()
+	}()
+	if err := os.RemoveRetry(name, 10, 10*time.Millisecond); err != nil {
+		t.Fatalf("RemoveRetry: %v", err)
+	}
+}
+
+func TestRemoveOnRebootInUse(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(name, nil, 0666); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.Open(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.RemoveOnReboot(name)
+	f.Close()
+	switch {
+	case errors.Is(err, os.ErrRemoveDeferred):
+		// Scheduled for removal; the file remains until then.
+		if _, err := os.Stat(name); err != nil {
+			t.Errorf("Stat after deferred removal: %v", err)
+		}
+	case err == nil:
+		t.Fatal("RemoveOnReboot of open file succeeded")
+	default:
+		// Scheduling requires administrator rights.
+		t.Logf("RemoveOnReboot: %v", err)
+	}
+}
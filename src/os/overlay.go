@@ -0,0 +1,437 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"runtime"
+	"sort"
+	"sync"
+	"time"
+)
+
+// An Overlay is a file system that layers in-memory changes over a
+// directory tree, in the manner of overlayfs. Reads see the tree as
+// modified by the writes made through the Overlay, but the tree itself
+// is never changed. Removing a file or directory that exists in the
+// tree records a whiteout that hides it from later reads.
+//
+// Names are slash-separated paths relative to the root of the tree, as
+// accepted by fs.ValidPath. An Overlay is safe for concurrent use.
+type Overlay struct {
+	base string
+
+	mu       sync.Mutex
+	upper    map[string]*overlayEntry // written through the overlay
+	whiteout map[string]bool          // removed from the base tree
+}
+
+// An overlayEntry is a file or directory in the upper layer.
+// A directory in the upper layer hides everything beneath the
+// same name in the base tree.
+type overlayEntry struct {
+	mode    fs.FileMode
+	modTime time.Time
+	data    []byte // never modified once stored
+}
+
+// NewOverlay returns an Overlay over the directory tree rooted at base.
+func NewOverlay(base string) (*Overlay, error) {
+	fi, err := Stat(base)
+	if err != nil {
+		return nil, err
+	}
+	if !fi.IsDir() {
+		return nil, &PathError{Op: "newoverlay", Path: base, Err: errNotDirectory}
+	}
+	return &Overlay{
+		base:     base,
+		upper:    make(map[string]*overlayEntry),
+		whiteout: make(map[string]bool),
+	}, nil
+}
+
+var (
+	errNotDirectory = errors.New("not a directory")
+	errIsDirectory  = errors.New("is a directory")
+	errDirNotEmpty  = errors.New("directory not empty")
+)
+
+// Open opens the named file or directory for reading.
+// Reading a directory lists its merged contents.
+func (o *Overlay) Open(name string) (fs.File, error) {
+	if err := checkOverlayName("open", name); err != nil {
+		return nil, err
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	fi, err := o.stat(name)
+	if err != nil {
+		return nil, &PathError{Op: "open", Path: name, Err: err}
+	}
+	if fi.IsDir() {
+		entries, err := o.readDir(name)
+		if err != nil {
+			return nil, &PathError{Op: "open", Path: name, Err: err}
+		}
+		return &overlayDir{name: name, info: fi, entries: entries}, nil
+	}
+	if e := o.upper[name]; e != nil {
+		return &overlayFile{name: name, info: fi, data: e.data}, nil
+	}
+	f, err := Open(o.basePath(name))
+	if err != nil {
+		return nil, &PathError{Op: "open", Path: name, Err: underlyingError(err)}
+	}
+	return f, nil
+}
+
+// Stat returns a FileInfo describing the named file or directory.
+func (o *Overlay) Stat(name string) (fs.FileInfo, error) {
+	if err := checkOverlayName("stat", name); err != nil {
+		return nil, err
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	fi, err := o.stat(name)
+	if err != nil {
+		return nil, &PathError{Op: "stat", Path: name, Err: err}
+	}
+	return fi, nil
+}
+
+// ReadFile returns the contents of the named file.
+func (o *Overlay) ReadFile(name string) ([]byte, error) {
+	if err := checkOverlayName("read", name); err != nil {
+		return nil, err
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	fi, err := o.stat(name)
+	if err == nil && fi.IsDir() {
+		err = errIsDirectory
+	}
+	if err != nil {
+		return nil, &PathError{Op: "read", Path: name, Err: err}
+	}
+	if e := o.upper[name]; e != nil {
+		return append([]byte(nil), e.data...), nil
+	}
+	b, err := ReadFile(o.basePath(name))
+	if err != nil {
+		return nil, &PathError{Op: "read", Path: name, Err: underlyingError(err)}
+	}
+	return b, nil
+}
+
+// ReadDir returns the merged contents of the named directory,
+// sorted by file name.
+func (o *Overlay) ReadDir(name string) ([]fs.DirEntry, error) {
+	if err := checkOverlayName("readdir", name); err != nil {
+		return nil, err
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	fi, err := o.stat(name)
+	if err == nil && !fi.IsDir() {
+		err = errNotDirectory
+	}
+	if err == nil {
+		var entries []fs.DirEntry
+		if entries, err = o.readDir(name); err == nil {
+			return entries, nil
+		}
+	}
+	return nil, &PathError{Op: "readdir", Path: name, Err: err}
+}
+
+// WriteFile writes data to the named file in the upper layer, creating
+// it if necessary with permissions perm. An existing file keeps its
+// permissions. The parent directory must exist.
+func (o *Overlay) WriteFile(name string, data []byte, perm FileMode) error {
+	if err := checkOverlayName("write", name); err != nil {
+		return err
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	fi, err := o.stat(name)
+	switch {
+	case err == nil && fi.IsDir():
+		err = errIsDirectory
+	case err == nil:
+		perm = fi.Mode()
+	case IsNotExist(err):
+		err = o.checkParent(name)
+	}
+	if err != nil {
+		return &PathError{Op: "write", Path: name, Err: err}
+	}
+	o.upper[name] = &overlayEntry{
+		mode:    perm & ModePerm,
+		modTime: time.Now(),
+		data:    append([]byte(nil), data...),
+	}
+	delete(o.whiteout, name)
+	return nil
+}
+
+// Mkdir creates a new directory in the upper layer with the specified
+// name and permission bits. The parent directory must exist.
+func (o *Overlay) Mkdir(name string, perm FileMode) error {
+	if err := checkOverlayName("mkdir", name); err != nil {
+		return err
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	_, err := o.stat(name)
+	switch {
+	case err == nil:
+		err = ErrExist
+	case IsNotExist(err):
+		err = o.checkParent(name)
+	}
+	if err != nil {
+		return &PathError{Op: "mkdir", Path: name, Err: err}
+	}
+	o.upper[name] = &overlayEntry{
+		mode:    ModeDir | perm&ModePerm,
+		modTime: time.Now(),
+	}
+	delete(o.whiteout, name)
+	return nil
+}
+
+// Remove removes the named file or empty directory. If the name exists
+// in the base tree, it is hidden by a whiteout.
+func (o *Overlay) Remove(name string) error {
+	if err := checkOverlayName("remove", name); err != nil {
+		return err
+	}
+	if name == "." {
+		return &PathError{Op: "remove", Path: name, Err: ErrInvalid}
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	fi, err := o.stat(name)
+	if err == nil && fi.IsDir() {
+		var entries []fs.DirEntry
+		entries, err = o.readDir(name)
+		if err == nil && len(entries) > 0 {
+			err = errDirNotEmpty
+		}
+	}
+	if err != nil {
+		return &PathError{Op: "remove", Path: name, Err: err}
+	}
+	if !o.hidden(name) {
+		if _, err := Lstat(o.basePath(name)); err == nil {
+			o.whiteout[name] = true
+		}
+	}
+	delete(o.upper, name)
+	return nil
+}
+
+// stat returns information about name in the merged view.
+// o.mu must be held.
+func (o *Overlay) stat(name string) (fs.FileInfo, error) {
+	if e := o.upper[name]; e != nil {
+		return &overlayInfo{name: overlayBase(name), e: e}, nil
+	}
+	if o.hidden(name) {
+		return nil, ErrNotExist
+	}
+	fi, err := Stat(o.basePath(name))
+	if err != nil {
+		return nil, underlyingError(err)
+	}
+	return fi, nil
+}
+
+// readDir returns the merged, sorted contents of the directory name.
+// o.mu must be held.
+func (o *Overlay) readDir(name string) ([]fs.DirEntry, error) {
+	byName := make(map[string]fs.DirEntry)
+	if o.upper[name] == nil && !o.hidden(name) {
+		base, err := ReadDir(o.basePath(name))
+		if err != nil {
+			return nil, underlyingError(err)
+		}
+		for _, de := range base {
+			if !o.whiteout[overlayJoin(name, de.Name())] {
+				byName[de.Name()] = de
+			}
+		}
+	}
+	for n, e := range o.upper {
+		if overlayParent(n) == name {
+			info := &overlayInfo{name: overlayBase(n), e: e}
+			byName[info.name] = fs.FileInfoToDirEntry(info)
+		}
+	}
+	entries := make([]fs.DirEntry, 0, len(byName))
+	for _, de := range byName {
+		entries = append(entries, de)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+	return entries, nil
+}
+
+// hidden reports whether the base tree's copy of name is hidden, either
+// by a whiteout on name or one of its parents or by a parent that was
+// created in the upper layer. o.mu must be held.
+func (o *Overlay) hidden(name string) bool {
+	if o.whiteout[name] {
+		return true
+	}
+	for p := name; p != "."; {
+		p = overlayParent(p)
+		if o.whiteout[p] || o.upper[p] != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// checkParent returns an error unless the parent of name is an
+// existing directory. o.mu must be held.
+func (o *Overlay) checkParent(name string) error {
+	fi, err := o.stat(overlayParent(name))
+	if err != nil {
+		return err
+	}
+	if !fi.IsDir() {
+		return errNotDirectory
+	}
+	return nil
+}
+
+// basePath returns the path of name in the base tree.
+func (o *Overlay) basePath(name string) string {
+	if name == "." {
+		return o.base
+	}
+	return o.base + "/" + name
+}
+
+func checkOverlayName(op, name string) error {
+	if !fs.ValidPath(name) || runtime.GOOS == "windows" && containsAny(name, `\:`) {
+		return &PathError{Op: op, Path: name, Err: ErrInvalid}
+	}
+	return nil
+}
+
+// overlayParent returns the slash-separated parent of name.
+func overlayParent(name string) string {
+	if i := lastIndex(name, '/'); i >= 0 {
+		return name[:i]
+	}
+	return "."
+}
+
+// overlayBase returns the last element of the slash-separated name.
+func overlayBase(name string) string {
+	return name[lastIndex(name, '/')+1:]
+}
+
+func overlayJoin(dir, name string) string {
+	if dir == "." {
+		return name
+	}
+	return dir + "/" + name
+}
+
+// overlayInfo describes a file or directory in the upper layer.
+type overlayInfo struct {
+	name string
+	e    *overlayEntry
+}
+
+func (fi *overlayInfo) Name() string       { return fi.name }
+func (fi *overlayInfo) Size() int64        { return int64(len(fi.e.data)) }
+func (fi *overlayInfo) Mode() FileMode     { return fi.e.mode }
+func (fi *overlayInfo) ModTime() time.Time { return fi.e.modTime }
+func (fi *overlayInfo) IsDir() bool        { return fi.e.mode.IsDir() }
+func (fi *overlayInfo) Sys() interface{}   { return nil }
+
+// overlayFile is an open file from the upper layer.
+type overlayFile struct {
+	name string
+	info fs.FileInfo
+	data []byte
+	off  int64
+}
+
+func (f *overlayFile) Stat() (fs.FileInfo, error) { return f.info, nil }
+func (f *overlayFile) Close() error               { return nil }
+
+func (f *overlayFile) Read(b []byte) (int, error) {
+	if f.off >= int64(len(f.data)) {
+		return 0, io.EOF
+	}
+	n := copy(b, f.data[f.off:])
+	f.off += int64(n)
+	return n, nil
+}
+
+func (f *overlayFile) ReadAt(b []byte, off int64) (int, error) {
+	if off < 0 {
+		return 0, &PathError{Op: "readat", Path: f.name, Err: ErrInvalid}
+	}
+	if off >= int64(len(f.data)) {
+		return 0, io.EOF
+	}
+	n := copy(b, f.data[off:])
+	if n < len(b) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+func (f *overlayFile) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekCurrent:
+		offset += f.off
+	case io.SeekEnd:
+		offset += int64(len(f.data))
+	}
+	if offset < 0 || whence < io.SeekStart || whence > io.SeekEnd {
+		return 0, &PathError{Op: "seek", Path: f.name, Err: ErrInvalid}
+	}
+	f.off = offset
+	return offset, nil
+}
+
+// overlayDir is an open directory, holding its merged contents as of
+// the time it was opened.
+type overlayDir struct {
+	name    string
+	info    fs.FileInfo
+	entries []fs.DirEntry
+	off     int
+}
+
+func (d *overlayDir) Stat() (fs.FileInfo, error) { return d.info, nil }
+func (d *overlayDir) Close() error               { return nil }
+
+func (d *overlayDir) Read([]byte) (int, error) {
+	return 0, &PathError{Op: "read", Path: d.name, Err: errIsDirectory}
+}
+
+func (d *overlayDir) ReadDir(n int) ([]fs.DirEntry, error) {
+	entries := d.entries[d.off:]
+	if n > 0 {
+		if len(entries) == 0 {
+			return nil, io.EOF
+		}
+		if len(entries) > n {
+			entries = entries[:n]
+		}
+	}
+	d.off += len(entries)
+	return entries, nil
+}
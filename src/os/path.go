@@ -5,6 +5,7 @@
 package os
 
 import (
+	"io/fs"
 	"syscall"
 )
 
@@ -16,6 +17,35 @@ import (
 // If path is already a directory, MkdirAll does nothing
 // and returns nil.
 func MkdirAll(path string, perm FileMode) error {
+	return mkdirAll(path, perm, perm, nil)
+}
+
+// MkdirAllCreated is like MkdirAll, but also returns the directories
+// it created, in the order they were created. Directories that already
+// existed are not included. If MkdirAllCreated fails part way through,
+// it returns the directories created before the failure together with
+// the error, so the caller can remove them in reverse order to undo
+// the partial result.
+func MkdirAllCreated(path string, perm FileMode) (created []string, err error) {
+	err = mkdirAll(path, perm, perm, &created)
+	return created, err
+}
+
+// MkdirAllMode is like MkdirAll, but creates the directory named path
+// with permission bits leafPerm and any necessary parents with
+// permission bits intermediatePerm (both before umask).
+// For example, MkdirAllMode(path, 0700, 0755) creates a private
+// directory whose newly created parents are world-readable.
+// Existing directories are left unchanged.
+func MkdirAllMode(path string, leafPerm, intermediatePerm FileMode) error {
+	return mkdirAll(path, leafPerm, intermediatePerm, nil)
+}
+
+// mkdirAll implements MkdirAll, MkdirAllCreated and MkdirAllMode.
+// It creates path with mode perm and any missing parents with mode
+// parentPerm. If created is not nil, the name of each directory
+// created is appended to it.
+func mkdirAll(path string, perm, parentPerm FileMode, created *[]string) error {
 	// Fast path: if we can tell whether path is a directory or file, stop with success or error.
 	dir, err := Stat(path)
 	if err == nil {
@@ -38,7 +68,7 @@ func MkdirAll(path string, perm FileMode) error {
 
 	if j > 1 {
 		// Create parent.
-		err = MkdirAll(fixRootDirectory(path[:j-1]), perm)
+		err = mkdirAll(fixRootDirectory(path[:j-1]), parentPerm, parentPerm, created)
 		if err != nil {
 			return err
 		}
@@ -55,6 +85,9 @@ func MkdirAll(path string, perm FileMode) error {
 		}
 		return err
 	}
+	if created != nil {
+		*created = append(*created, path)
+	}
 	return nil
 }
 
@@ -67,6 +100,31 @@ func RemoveAll(path string) error {
 	return removeAll(path)
 }
 
+// RemoveAllExcept removes root and any children it contains, except
+// for the entries for which keep returns true. A kept directory is kept
+// with all its contents, and the directories containing a kept entry
+// are kept too, so root itself is removed only if nothing is kept.
+//
+// RemoveAllExcept calls keep for root and for each entry beneath it
+// that is not inside a kept directory, with path formed by joining root
+// and the names of the entries. It does not follow symbolic links.
+// Like RemoveAll, it removes everything it can but returns the first
+// error it encounters, and returns nil if root does not exist.
+func RemoveAllExcept(root string, keep func(path string, d DirEntry) bool) error {
+	if endsWithDot(root) {
+		return &PathError{Op: "RemoveAll", Path: root, Err: syscall.EINVAL}
+	}
+	fi, err := Lstat(root)
+	if err != nil {
+		if IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	_, err = removeAllExcept(root, fs.FileInfoToDirEntry(fi), keep)
+	return err
+}
+
 // endsWithDot reports whether the final component of path is ".".
 func endsWithDot(path string) bool {
 	if path == "." {
@@ -77,3 +135,17 @@ func endsWithDot(path string) bool {
 	}
 	return false
 }
+
+// EnableLongPaths makes the functions in this package accept absolute
+// paths longer than the Windows MAX_PATH limit of 260 characters.
+//
+// Package os already does this for every process, so calling
+// EnableLongPaths is never required and it has no effect. On Windows
+// versions that support it, the runtime opts the process in to the
+// system's native long path handling; otherwise functions such as Open,
+// Stat, Mkdir and Remove rewrite long absolute paths, including UNC
+// paths, into the extended-length \\?\ form, resolving . and ..
+// elements first because that form treats them literally. Relative
+// paths are not rewritten and remain subject to MAX_PATH there. On
+// other systems there is no such limit.
+func EnableLongPaths() {}
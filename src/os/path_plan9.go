@@ -14,6 +14,42 @@ func IsPathSeparator(c uint8) bool {
 	return PathSeparator == c
 }
 
+// basename removes trailing slashes and the leading directory name from path name.
+func basename(name string) string {
+	i := len(name) - 1
+	// Remove trailing slashes
+	for ; i > 0 && name[i] == '/'; i-- {
+		name = name[:i]
+	}
+	// Remove leading directory name
+	for i--; i >= 0; i-- {
+		if name[i] == '/' {
+			name = name[i+1:]
+			break
+		}
+	}
+
+	return name
+}
+
+// dirname returns all but the last element of path.
+func dirname(path string) string {
+	i := len(path) - 1
+	for i > 0 && path[i] == '/' { // Skip trailing slashes.
+		i--
+	}
+	for i >= 0 && path[i] != '/' { // Scan backward over element.
+		i--
+	}
+	for i > 0 && path[i] == '/' { // Skip separating slashes.
+		i--
+	}
+	if i < 0 {
+		return "."
+	}
+	return path[:i+1]
+}
+
 func fixRootDirectory(p string) string {
 	return p
 }
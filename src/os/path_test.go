@@ -5,11 +5,15 @@
 package os_test
 
 import (
+	"fmt"
 	"internal/testenv"
 	"os"
 	. "os"
 	"path/filepath"
+	"reflect"
 	"runtime"
+	"strings"
+	"sync"
 	"syscall"
 	"testing"
 )
@@ -126,3 +130,140 @@ func TestMkdirAllAtSlash(t *testing.T) {
 	}
 	RemoveAll("/_go_os_test")
 }
+
+func TestMkdirAllMode(t *testing.T) {
+	tmpDir := t.TempDir()
+	parent := filepath.Join(tmpDir, "a", "b")
+	leaf := filepath.Join(parent, "secrets")
+	if err := MkdirAllMode(leaf, 0700, 0755); err != nil {
+		t.Fatalf("MkdirAllMode %q: %v", leaf, err)
+	}
+
+	// Already exists, should succeed.
+	if err := MkdirAllMode(leaf, 0700, 0755); err != nil {
+		t.Fatalf("MkdirAllMode %q (second time): %v", leaf, err)
+	}
+
+	if runtime.GOOS != "windows" && runtime.GOOS != "plan9" {
+		for _, tt := range []struct {
+			path string
+			want FileMode
+		}{
+			{leaf, 0700},
+			{parent, 0755},
+			{filepath.Dir(parent), 0755},
+		} {
+			fi, err := Stat(tt.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			// Allow for a umask that removes group and other bits.
+			if got := fi.Mode().Perm(); got&^tt.want != 0 || got&0700 != 0700 {
+				t.Errorf("%s has mode %v, want %v", tt.path, got, tt.want)
+			}
+		}
+	}
+
+	fpath := filepath.Join(leaf, "file")
+	if err := WriteFile(fpath, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+	err := MkdirAllMode(fpath, 0700, 0755)
+	if perr, ok := err.(*PathError); !ok || perr.Err != syscall.ENOTDIR {
+		t.Errorf("MkdirAllMode %q = %v, want ENOTDIR", fpath, err)
+	}
+}
+
+func TestMkdirAllCreated(t *testing.T) {
+	tmpDir := t.TempDir()
+	existing := filepath.Join(tmpDir, "existing")
+	if err := Mkdir(existing, 0777); err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(existing, "a", "b", "c")
+	created, err := MkdirAllCreated(path, 0777)
+	if err != nil {
+		t.Fatalf("MkdirAllCreated %q: %v", path, err)
+	}
+	want := []string{
+		filepath.Join(existing, "a"),
+		filepath.Join(existing, "a", "b"),
+		path,
+	}
+	if !reflect.DeepEqual(created, want) {
+		t.Errorf("MkdirAllCreated %q = %q, want %q", path, created, want)
+	}
+
+	// Nothing is created the second time.
+	created, err = MkdirAllCreated(path, 0777)
+	if err != nil || len(created) != 0 {
+		t.Errorf("MkdirAllCreated %q (second time) = %q, %v; want none, nil", path, created, err)
+	}
+
+	// A failure part way through reports the directories created
+	// before it. A name too long for any file system makes the last
+	// Mkdir fail.
+	long := filepath.Join(existing, "p", "q", strings.Repeat("x", 1000))
+	created, err = MkdirAllCreated(long, 0777)
+	if err == nil {
+		t.Fatalf("MkdirAllCreated with an overlong name succeeded")
+	}
+	want = []string{filepath.Join(existing, "p"), filepath.Join(existing, "p", "q")}
+	if !reflect.DeepEqual(created, want) {
+		t.Errorf("MkdirAllCreated %q = %q, %v; want %q and an error", long, created, err, want)
+	}
+
+	// A failure before anything is created reports nothing.
+	fpath := filepath.Join(existing, "file")
+	if err := WriteFile(fpath, nil, 0666); err != nil {
+		t.Fatal(err)
+	}
+	created, err = MkdirAllCreated(filepath.Join(fpath, "sub"), 0777)
+	if err == nil {
+		t.Fatalf("MkdirAllCreated under a file succeeded")
+	}
+	if len(created) != 0 {
+		t.Errorf("created %q on failure, want none", created)
+	}
+}
+
+func TestDirMaker(t *testing.T) {
+	tmpDir := t.TempDir()
+	m := NewDirMaker(0777)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				path := filepath.Join(tmpDir, "a", "b", fmt.Sprint(j%4), fmt.Sprint(i))
+				if err := m.EnsureDir(path); err != nil {
+					t.Errorf("EnsureDir %q: %v", path, err)
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+	for j := 0; j < 4; j++ {
+		for i := 0; i < 8; i++ {
+			path := filepath.Join(tmpDir, "a", "b", fmt.Sprint(j), fmt.Sprint(i))
+			if fi, err := Stat(path); err != nil || !fi.IsDir() {
+				t.Errorf("Stat %q = %v, %v; want directory", path, fi, err)
+			}
+		}
+	}
+
+	// A file in the way is reported.
+	fpath := filepath.Join(tmpDir, "file")
+	if err := WriteFile(fpath, nil, 0666); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.EnsureDir(fpath); err == nil {
+		t.Errorf("EnsureDir of a file succeeded")
+	}
+	if err := m.EnsureDir(filepath.Join(fpath, "sub")); err == nil {
+		t.Errorf("EnsureDir under a file succeeded")
+	}
+}
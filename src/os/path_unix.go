@@ -71,6 +71,12 @@ func splitPath(path string) (string, string) {
 	return dirname, basename
 }
 
+// dirname returns all but the last element of path.
+func dirname(path string) string {
+	dir, _ := splitPath(path)
+	return dir
+}
+
 func fixRootDirectory(p string) string {
 	return p
 }
@@ -165,28 +165,45 @@ func fixLongPath(path string) string {
 	// \\?\c:\windows\foo.txt or \\?\UNC\server\share\foo.txt.
 	// The extended form disables evaluation of . and .. path
 	// elements and disables the interpretation of / as equivalent
-	// to \. The conversion here rewrites / to \ and elides
-	// . elements as well as trailing or duplicate separators. For
-	// simplicity it avoids the conversion entirely for relative
-	// paths or paths containing .. elements. For now,
-	// \\server\share paths are not converted to
-	// \\?\UNC\server\share paths because the rules for doing so
-	// are less well-specified.
-	if len(path) >= 2 && path[:2] == `\\` {
-		// Don't canonicalize UNC paths.
+	// to \. The conversion here rewrites / to \, elides . elements
+	// as well as trailing or duplicate separators, and resolves ..
+	// elements lexically, as the system would for the normal form.
+	// Relative paths are left alone, since their meaning depends
+	// on the current directory of each drive.
+	var prefix string
+	var root int // length of the path's volume name
+	switch {
+	case len(path) >= 4 && IsPathSeparator(path[0]) && IsPathSeparator(path[1]) && (path[2] == '?' || path[2] == '.') && IsPathSeparator(path[3]):
+		// Already in extended or device form.
 		return path
-	}
-	if !isAbs(path) {
-		// Relative path
+	case len(path) >= 2 && IsPathSeparator(path[0]) && IsPathSeparator(path[1]):
+		// \\server\share\foo becomes \\?\UNC\server\share\foo.
+		root = len(volumeName(path))
+		if root <= 2 {
+			return path
+		}
+		prefix = `\\?\UNC`
+		path = path[1:] // keep one separator before server
+		root--
+	case isAbs(path):
+		prefix = `\\?\`
+		root = len(`c:`)
+	default:
 		return path
 	}
 
-	const prefix = `\\?`
-
 	pathbuf := make([]byte, len(prefix)+len(path)+len(`\`))
 	copy(pathbuf, prefix)
+	for i := 0; i < root; i++ {
+		c := path[i]
+		if IsPathSeparator(c) {
+			c = '\\'
+		}
+		pathbuf[len(prefix)+i] = c
+	}
+	vol := len(prefix) + root // .. never goes above the volume root
 	n := len(path)
-	r, w := 0, len(prefix)
+	r, w := root, vol
 	for r < n {
 		switch {
 		case IsPathSeparator(path[r]):
@@ -196,8 +213,14 @@ func fixLongPath(path string) string {
 			// /./
 			r++
 		case r+1 < n && path[r] == '.' && path[r+1] == '.' && (r+2 == n || IsPathSeparator(path[r+2])):
-			// /../ is currently unhandled
-			return path
+			// /../: remove the last element, if any.
+			r += 2
+			for w > vol && pathbuf[w-1] != '\\' {
+				w--
+			}
+			if w > vol {
+				w--
+			}
 		default:
 			pathbuf[w] = '\\'
 			w++
@@ -208,7 +231,7 @@ func fixLongPath(path string) string {
 		}
 	}
 	// A drive's root directory needs a trailing \
-	if w == len(`\\?\c:`) {
+	if w == vol {
 		pathbuf[w] = '\\'
 		w++
 	}
@@ -6,6 +6,7 @@ package os_test
 
 import (
 	"os"
+	"path/filepath"
 	"strings"
 	"syscall"
 	"testing"
@@ -36,7 +37,13 @@ func TestFixLongPath(t *testing.T) {
 		{`\\unc\path`, `\\unc\path`},
 		{`long.txt`, `long.txt`},
 		{`C:long.txt`, `C:long.txt`},
-		{`c:\long\..\bar\baz`, `c:\long\..\bar\baz`},
+		{`c:\long\..\bar\baz`, `\\?\c:\bar\baz`},
+		{`c:\long\..\..\long\baz`, `\\?\c:\long\baz`},
+		{`c:\long\foo\..`, `\\?\c:\long`},
+		{`\\server\share\long\foo`, `\\?\UNC\server\share\long\foo`},
+		{`//server/share/long/../foo`, `\\?\UNC\server\share\foo`},
+		{`\\.\long`, `\\.\long`},
+		{`\long\foo.txt`, `\long\foo.txt`},
 		{`\\?\c:\long\foo.txt`, `\\?\c:\long\foo.txt`},
 		{`\\?\c:\long/foo.txt`, `\\?\c:\long/foo.txt`},
 	} {
@@ -111,3 +118,42 @@ func TestOpenRootSlash(t *testing.T) {
 		dir.Close()
 	}
 }
+
+func TestLongPathOperations(t *testing.T) {
+	dir := t.TempDir()
+	// Build an absolute path of more than 300 characters,
+	// including a .. element that must be resolved.
+	long := dir
+	for len(long) < 300 {
+		long = filepath.Join(long, strings.Repeat("d", 50))
+	}
+	if err := os.MkdirAll(long, 0777); err != nil {
+		t.Fatal(err)
+	}
+	name := long + `\sub\..\file.txt`
+	f, err := os.Create(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+	fi1, err := os.Stat(filepath.Join(long, "file.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	fi2, err := os.Stat(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !os.SameFile(fi1, fi2) {
+		t.Errorf("Stat(%q) and Stat(%q) are not the same file", name, filepath.Join(long, "file.txt"))
+	}
+	if err := os.Mkdir(long+`\newdir`, 0777); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(long + `\newdir`); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(name); err != nil {
+		t.Fatal(err)
+	}
+}
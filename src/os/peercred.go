@@ -0,0 +1,23 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+// PeerCredentials returns the process ID, user ID and group ID of the
+// process at the other end of f, which must be a connected Unix-domain
+// socket. The credentials are those the peer had when the connection
+// was established, so they may be used to restrict which users can talk
+// to a service listening on a Unix socket. If the system does not report
+// the peer's process ID, pid is -1.
+//
+// On Linux the credentials come from SO_PEERCRED; on the BSDs and
+// Darwin from LOCAL_PEERCRED or the equivalent socket option. If f is
+// not a Unix-domain socket, PeerCredentials returns an error. On other
+// systems it returns an error wrapping ErrUnsupported.
+func (f *File) PeerCredentials() (pid, uid, gid int, err error) {
+	if err := f.checkValid("peercred"); err != nil {
+		return -1, -1, -1, err
+	}
+	return f.peerCredentials()
+}
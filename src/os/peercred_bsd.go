@@ -0,0 +1,30 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build darwin || dragonfly || freebsd || netbsd || openbsd
+// +build darwin dragonfly freebsd netbsd openbsd
+
+package os
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+// Implemented in the syscall package.
+//go:linkname getsockopt syscall.getsockopt
+func getsockopt(s int, level int, name int, val unsafe.Pointer, vallen *uint32) error
+
+// getsockoptStruct is like getsockopt but checks that the kernel
+// filled in exactly size bytes.
+func getsockoptStruct(fd, level, name int, val unsafe.Pointer, size uintptr) error {
+	n := uint32(size)
+	if err := getsockopt(fd, level, name, val, &n); err != nil {
+		return err
+	}
+	if uintptr(n) != size {
+		return syscall.EINVAL
+	}
+	return nil
+}
@@ -0,0 +1,24 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+import "unsafe"
+
+const _LOCAL_PEEREID = 3 // at level 0
+
+// unpcbid is struct unpcbid from <sys/un.h>.
+type unpcbid struct {
+	pid  int32
+	euid uint32
+	egid uint32
+}
+
+func peerCred(fd int) (pid, uid, gid int, err error) {
+	var cred unpcbid
+	if err := getsockoptStruct(fd, 0, _LOCAL_PEEREID, unsafe.Pointer(&cred), unsafe.Sizeof(cred)); err != nil {
+		return -1, -1, -1, err
+	}
+	return int(cred.pid), int(cred.euid), int(cred.egid), nil
+}
@@ -0,0 +1,25 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+// sockpeercred is struct sockpeercred from <sys/socket.h>.
+type sockpeercred struct {
+	uid uint32
+	gid uint32
+	pid int32
+}
+
+func peerCred(fd int) (pid, uid, gid int, err error) {
+	var cred sockpeercred
+	if err := getsockoptStruct(fd, syscall.SOL_SOCKET, syscall.SO_PEERCRED, unsafe.Pointer(&cred), unsafe.Sizeof(cred)); err != nil {
+		return -1, -1, -1, err
+	}
+	return int(cred.pid), int(cred.uid), int(cred.gid), nil
+}
@@ -0,0 +1,12 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build aix || (js && wasm) || plan9 || solaris || windows
+// +build aix js,wasm plan9 solaris windows
+
+package os
+
+func (f *File) peerCredentials() (pid, uid, gid int, err error) {
+	return -1, -1, -1, f.wrapErr("peercred", ErrUnsupported)
+}
@@ -0,0 +1,41 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+// +build darwin dragonfly freebsd linux netbsd openbsd
+
+package os
+
+import (
+	"errors"
+	"runtime"
+	"syscall"
+)
+
+var errNotUnixSocket = errors.New("not a Unix-domain socket")
+
+func (f *File) peerCredentials() (pid, uid, gid int, err error) {
+	var e error
+	if err := f.pfd.RawControl(func(fd uintptr) {
+		sa, err := syscall.Getsockname(int(fd))
+		if err != nil {
+			e = err
+			return
+		}
+		// The socket options used by peerCred have unrelated
+		// meanings for other address families.
+		if _, ok := sa.(*syscall.SockaddrUnix); !ok {
+			e = errNotUnixSocket
+			return
+		}
+		pid, uid, gid, e = peerCred(int(fd))
+	}); err != nil {
+		return -1, -1, -1, f.wrapErr("peercred", err)
+	}
+	runtime.KeepAlive(f)
+	if e != nil {
+		return -1, -1, -1, f.wrapErr("peercred", e)
+	}
+	return pid, uid, gid, nil
+}
@@ -0,0 +1,65 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build darwin || dragonfly || freebsd
+// +build darwin dragonfly freebsd
+
+package os
+
+import (
+	"runtime"
+	"syscall"
+	"unsafe"
+)
+
+const (
+	_SOL_LOCAL       = 0
+	_LOCAL_PEERCRED  = 1
+	_LOCAL_PEERPID   = 2 // darwin only
+	_XUCRED_NGROUPS  = 16
+	_XUCRED_VERSION  = 0
+	_SIZEOF_XUCRED   = 4 + 4 + 4 + 4*_XUCRED_NGROUPS // darwin and dragonfly
+	_SIZEOF_XUCRED13 = unsafe.Sizeof(xucred{})       // freebsd, which appends cr_pid
+)
+
+// xucred is struct xucred from <sys/ucred.h>. On FreeBSD 13 and later
+// it ends with a pointer-sized union holding the peer's pid, which
+// older kernels and other systems leave zero.
+type xucred struct {
+	version uint32
+	uid     uint32
+	ngroups int16
+	groups  [_XUCRED_NGROUPS]uint32
+	pid     uintptr
+}
+
+func peerCred(fd int) (pid, uid, gid int, err error) {
+	var cred xucred
+	size := uintptr(_SIZEOF_XUCRED)
+	if runtime.GOOS == "freebsd" {
+		size = _SIZEOF_XUCRED13
+	}
+	n := uint32(size)
+	if err := getsockopt(fd, _SOL_LOCAL, _LOCAL_PEERCRED, unsafe.Pointer(&cred), &n); err != nil {
+		return -1, -1, -1, err
+	}
+	if cred.version != _XUCRED_VERSION || cred.ngroups < 1 {
+		return -1, -1, -1, syscall.EINVAL
+	}
+	pid = -1
+	switch runtime.GOOS {
+	case "darwin", "ios":
+		var p int32
+		if getsockoptStruct(fd, _SOL_LOCAL, _LOCAL_PEERPID, unsafe.Pointer(&p), unsafe.Sizeof(p)) == nil {
+			pid = int(p)
+		}
+	case "freebsd":
+		if uintptr(n) == _SIZEOF_XUCRED13 {
+			if p := *(*int32)(unsafe.Pointer(&cred.pid)); p > 0 {
+				pid = int(p)
+			}
+		}
+	}
+	return pid, int(cred.uid), int(cred.groups[0]), nil
+}
@@ -0,0 +1,118 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+import (
+	"errors"
+	"internal/itoa"
+	"io"
+	"sync"
+)
+
+// ErrAlreadyRunning is returned, wrapped in a *PathError, when another
+// process holds the lock on a PID file or instance lock file.
+var ErrAlreadyRunning = errors.New("another instance is already running")
+
+// pidFiles holds open the PID files written by WritePidFile, so that
+// their locks last for the life of the process.
+var pidFiles struct {
+	sync.Mutex
+	m map[string]*File
+}
+
+// WritePidFile writes the current process ID to the named file,
+// creating it if necessary.
+//
+// WritePidFile also takes an exclusive lock on the file and holds it
+// until the process exits. If another process holds the lock,
+// WritePidFile returns ErrAlreadyRunning, wrapped in a *PathError, and
+// leaves the file unchanged. On systems without file locking,
+// WritePidFile instead fails with ErrAlreadyRunning if the file names
+// a different process that is still running.
+//
+// Calling WritePidFile again with the same name in the same process
+// rewrites the file.
+func WritePidFile(name string) error {
+	pidFiles.Lock()
+	defer pidFiles.Unlock()
+	f := pidFiles.m[name]
+	if f == nil {
+		var err error
+		f, err = OpenFile(name, O_RDWR|O_CREATE, 0644)
+		if err != nil {
+			return err
+		}
+		if err := f.lock(true, false); err != nil {
+			if errors.Is(err, errLockHeld) {
+				f.Close()
+				return &PathError{Op: "writepidfile", Path: name, Err: ErrAlreadyRunning}
+			}
+			if !errors.Is(err, ErrUnsupported) {
+				f.Close()
+				return err
+			}
+			if pid, err := readPid(f); err == nil && pid != Getpid() && processAlive(pid) {
+				f.Close()
+				return &PathError{Op: "writepidfile", Path: name, Err: ErrAlreadyRunning}
+			}
+		}
+	}
+	// Write before truncating, so that a concurrent reader never
+	// sees an empty file.
+	b := []byte(itoa.Itoa(Getpid()) + "\n")
+	_, err := f.WriteAt(b, 0)
+	if err == nil {
+		err = f.Truncate(int64(len(b)))
+	}
+	if err == nil {
+		err = f.Sync()
+	}
+	if err != nil {
+		if pidFiles.m[name] == nil {
+			f.Close()
+		}
+		return err
+	}
+	if pidFiles.m == nil {
+		pidFiles.m = make(map[string]*File)
+	}
+	pidFiles.m[name] = f
+	return nil
+}
+
+// ReadPidFile reads the process ID stored in the named file by
+// WritePidFile and reports whether a process with that ID is running.
+// A file naming a process that has exited is stale and may be replaced.
+//
+// Process IDs are reused, so alive may report true for an unrelated
+// process that happens to have the recorded ID. Use the lock taken by
+// WritePidFile where that matters.
+func ReadPidFile(name string) (pid int, alive bool, err error) {
+	f, err := Open(name)
+	if err != nil {
+		return 0, false, err
+	}
+	defer f.Close()
+	pid, err = readPid(f)
+	if err != nil {
+		return 0, false, err
+	}
+	return pid, processAlive(pid), nil
+}
+
+var errMalformedPid = errors.New("file does not contain a process ID")
+
+// readPid parses the process ID held in f.
+func readPid(f *File) (int, error) {
+	b, err := io.ReadAll(io.NewSectionReader(f, 0, 1<<62))
+	if err != nil {
+		return 0, err
+	}
+	v, ok := parseCounter(b)
+	if !ok || v <= 0 || int64(int(v)) != v {
+		return 0, &PathError{Op: "readpidfile", Path: f.name, Err: errMalformedPid}
+	}
+	return int(v), nil
+}
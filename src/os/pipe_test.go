@@ -11,6 +11,7 @@ package os_test
 import (
 	"bufio"
 	"bytes"
+	"errors"
 	"fmt"
 	"internal/testenv"
 	"io"
@@ -409,6 +410,169 @@ func TestPipeEOF(t *testing.T) {
 	}
 }
 
+func TestPipeCloseHalf(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	defer w.Close()
+
+	if runtime.GOOS == "windows" {
+		if err := w.CloseWrite(); !errors.Is(err, os.ErrUnsupported) {
+			t.Errorf("CloseWrite = %v, want ErrUnsupported", err)
+		}
+		return
+	}
+
+	if err := w.CloseRead(); !errors.Is(err, os.ErrInvalid) {
+		t.Errorf("CloseRead on write end = %v, want ErrInvalid", err)
+	}
+	if err := r.CloseWrite(); !errors.Is(err, os.ErrInvalid) {
+		t.Errorf("CloseWrite on read end = %v, want ErrInvalid", err)
+	}
+
+	if _, err := w.Write([]byte("hello")); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.CloseWrite(); err != nil {
+		t.Fatalf("CloseWrite: %v", err)
+	}
+	b, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != "hello" {
+		t.Errorf("read %q, want %q", b, "hello")
+	}
+	if err := r.CloseRead(); err != nil {
+		t.Errorf("CloseRead: %v", err)
+	}
+}
+
+func TestSocketpair(t *testing.T) {
+	a, b, err := os.Socketpair()
+	if runtime.GOOS == "windows" {
+		if !errors.Is(err, os.ErrUnsupported) {
+			t.Errorf("Socketpair = %v, want ErrUnsupported", err)
+		}
+		return
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer a.Close()
+	defer b.Close()
+
+	buf := make([]byte, 5)
+	for _, p := range [][2]*os.File{{a, b}, {b, a}} {
+		if _, err := p[0].Write([]byte("hello")); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := io.ReadFull(p[1], buf); err != nil {
+			t.Fatal(err)
+		}
+		if string(buf) != "hello" {
+			t.Errorf("%s read %q, want %q", p[1].Name(), buf, "hello")
+		}
+	}
+
+	if err := a.CloseWrite(); err != nil {
+		t.Fatalf("CloseWrite: %v", err)
+	}
+	if n, err := b.Read(buf); n != 0 || err != io.EOF {
+		t.Errorf("Read after CloseWrite = %d, %v; want 0, EOF", n, err)
+	}
+	// The other direction is still open.
+	if _, err := b.Write([]byte("x")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := io.ReadFull(a, buf[:1]); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSendFD(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("SendFD is not supported on Windows")
+	}
+	a, b, err := os.Socketpair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer a.Close()
+	defer b.Close()
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	defer w.Close()
+
+	if err := a.SendFD(w); err != nil {
+		t.Fatalf("SendFD: %v", err)
+	}
+	w2, err := b.RecvFD()
+	if err != nil {
+		t.Fatalf("RecvFD: %v", err)
+	}
+	if _, err := w2.Write([]byte("hello")); err != nil {
+		t.Fatal(err)
+	}
+	w2.Close()
+	w.Close()
+	got, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "hello" {
+		t.Errorf("read %q through received descriptor, want %q", got, "hello")
+	}
+
+	a.Close()
+	if _, err := b.RecvFD(); !errors.Is(err, io.EOF) {
+		t.Errorf("RecvFD after peer close = %v, want EOF", err)
+	}
+}
+
+func TestPeerCredentials(t *testing.T) {
+	switch runtime.GOOS {
+	case "aix", "solaris", "illumos", "windows":
+		t.Skipf("PeerCredentials is not supported on %s", runtime.GOOS)
+	}
+	a, b, err := os.Socketpair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer a.Close()
+	defer b.Close()
+
+	pid, uid, gid, err := a.PeerCredentials()
+	if err != nil {
+		t.Fatalf("PeerCredentials: %v", err)
+	}
+	if pid != -1 && pid != os.Getpid() {
+		t.Errorf("pid = %d, want %d", pid, os.Getpid())
+	}
+	if uid != os.Geteuid() {
+		t.Errorf("uid = %d, want %d", uid, os.Geteuid())
+	}
+	if gid != os.Getegid() {
+		t.Errorf("gid = %d, want %d", gid, os.Getegid())
+	}
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	defer w.Close()
+	if _, _, _, err := r.PeerCredentials(); err == nil {
+		t.Error("PeerCredentials on a pipe succeeded")
+	}
+}
+
 // Issue 24481.
 func TestFdRace(t *testing.T) {
 	r, w, err := os.Pipe()
@@ -0,0 +1,24 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+// OpenPty allocates a pseudo-terminal and returns its master and slave
+// sides, along with the file name of the slave. Data written to the
+// master is input to the terminal, and output written to the slave by
+// a program running on the terminal is read from the master.
+//
+// The slave is typically passed to a child process as its standard
+// input, output and error, using ProcAttr.Files, and made its
+// controlling terminal, as by SetControllingTerminal after Setsid. The
+// parent should close its copy of the slave once the child has started,
+// so that reads from the master fail once the child and its
+// descendants have all closed the terminal. Neither file becomes the
+// controlling terminal of the calling process.
+//
+// OpenPty is supported on Linux, macOS and FreeBSD. On other systems it
+// returns an error wrapping ErrUnsupported.
+func OpenPty() (master *File, slave *File, slaveName string, err error) {
+	return openPty()
+}
@@ -0,0 +1,41 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+import (
+	"internal/syscall/unix"
+	"syscall"
+	"unsafe"
+)
+
+// openPtyMaster opens a new pseudo-terminal master and returns it along
+// with the name of the slave.
+func openPtyMaster() (*File, string, error) {
+	master, err := OpenFile("/dev/ptmx", O_RDWR|syscall.O_NOCTTY, 0)
+	if err != nil {
+		return nil, "", err
+	}
+	var name [128]byte
+	err = ptyIoctl(master, func(fd int) error {
+		if err := unix.Ioctl(fd, syscall.TIOCPTYGRANT, 0); err != nil {
+			return err
+		}
+		if err := unix.Ioctl(fd, syscall.TIOCPTYUNLK, 0); err != nil {
+			return err
+		}
+		return unix.IoctlPtr(fd, syscall.TIOCPTYGNAME, unsafe.Pointer(&name[0]))
+	})
+	if err != nil {
+		master.Close()
+		return nil, "", err
+	}
+	for i, c := range name {
+		if c == 0 {
+			return master, string(name[:i]), nil
+		}
+	}
+	master.Close()
+	return nil, "", &PathError{Op: "ioctl", Path: "/dev/ptmx", Err: syscall.ENAMETOOLONG}
+}
@@ -0,0 +1,32 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+import (
+	"internal/itoa"
+	"internal/syscall/unix"
+	"syscall"
+	"unsafe"
+)
+
+// openPtyMaster opens a new pseudo-terminal master and returns it along
+// with the name of the slave. On FreeBSD grantpt and unlockpt are
+// no-ops, as the slave is ready for use as soon as the master exists.
+func openPtyMaster() (*File, string, error) {
+	fd, _, e := syscall.Syscall(syscall.SYS_POSIX_OPENPT, uintptr(O_RDWR|syscall.O_NOCTTY|syscall.O_CLOEXEC), 0, 0)
+	if e != 0 {
+		return nil, "", NewSyscallError("posix_openpt", e)
+	}
+	master := newFile(fd, "/dev/ptmx", kindOpenFile)
+	var n uint32
+	err := ptyIoctl(master, func(fd int) error {
+		return unix.IoctlPtr(fd, syscall.TIOCGPTN, unsafe.Pointer(&n))
+	})
+	if err != nil {
+		master.Close()
+		return nil, "", err
+	}
+	return master, "/dev/pts/" + itoa.Uitoa(uint(n)), nil
+}
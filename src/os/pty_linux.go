@@ -0,0 +1,37 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+import (
+	"internal/itoa"
+	"internal/syscall/unix"
+	"syscall"
+	"unsafe"
+)
+
+// openPtyMaster opens a new pseudo-terminal master and returns it along
+// with the name of the slave. On Linux grantpt is a no-op, because the
+// devpts file system gives the slave the right owner and mode itself.
+func openPtyMaster() (*File, string, error) {
+	master, err := OpenFile("/dev/ptmx", O_RDWR|syscall.O_NOCTTY, 0)
+	if err != nil {
+		return nil, "", err
+	}
+	var n uint32
+	err = ptyIoctl(master, func(fd int) error {
+		// unlockpt
+		var unlock int32
+		if err := unix.IoctlPtr(fd, syscall.TIOCSPTLCK, unsafe.Pointer(&unlock)); err != nil {
+			return err
+		}
+		// ptsname
+		return unix.IoctlPtr(fd, syscall.TIOCGPTN, unsafe.Pointer(&n))
+	})
+	if err != nil {
+		master.Close()
+		return nil, "", err
+	}
+	return master, "/dev/pts/" + itoa.Uitoa(uint(n)), nil
+}
@@ -0,0 +1,12 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build aix || dragonfly || js || netbsd || openbsd || plan9 || solaris || windows
+// +build aix dragonfly js netbsd openbsd plan9 solaris windows
+
+package os
+
+func openPty() (master *File, slave *File, slaveName string, err error) {
+	return nil, nil, "", ErrUnsupported
+}
@@ -0,0 +1,37 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build darwin || freebsd || linux
+// +build darwin freebsd linux
+
+package os
+
+import "syscall"
+
+func openPty() (master *File, slave *File, slaveName string, err error) {
+	master, slaveName, err = openPtyMaster()
+	if err != nil {
+		return nil, nil, "", err
+	}
+	slave, err = OpenFile(slaveName, O_RDWR|syscall.O_NOCTTY, 0)
+	if err != nil {
+		master.Close()
+		return nil, nil, "", err
+	}
+	return master, slave, slaveName, nil
+}
+
+// ptyIoctl performs the ioctl f on the open file descriptor of master.
+func ptyIoctl(master *File, f func(fd int) error) error {
+	var e error
+	if err := master.pfd.RawControl(func(fd uintptr) {
+		e = f(int(fd))
+	}); err != nil {
+		return master.wrapErr("ioctl", err)
+	}
+	if e != nil {
+		return master.wrapErr("ioctl", e)
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+// ProjectQuota reports the disk usage and limit, in bytes, of the
+// project quota that covers the file or directory at path, which is the
+// quota of the project ID of path. If the project has no block limit,
+// the returned limit is -1.
+//
+// Project quotas are supported by XFS, and by ext4 file systems created
+// with the project and quota features; they account for all the files
+// given a project ID with SetProjectID, wherever they are in the file
+// system. ProjectQuota uses the generic quotactl(2) interface, which
+// both file systems implement, on the device on which path is mounted.
+// Querying the quota of a project usually requires the CAP_SYS_ADMIN
+// capability. If project quotas are not enabled on the file system, or
+// on systems other than Linux, ProjectQuota returns an error wrapping
+// ErrUnsupported.
+func ProjectQuota(path string) (used, limit int64, err error) {
+	return projectQuota(path)
+}
+
+// SetProjectID sets the project ID of the file f, which determines the
+// project quota it is accounted to. If f is a directory, SetProjectID
+// also marks it so that files and directories created in it inherit
+// the project ID; the ID of existing entries is not changed. Changing
+// a project ID requires the CAP_FOWNER capability, or ownership of f
+// when done from the initial user namespace.
+//
+// SetProjectID uses the FS_IOC_FSSETXATTR ioctl. If the file system
+// does not support project IDs, or on systems other than Linux,
+// SetProjectID returns an error wrapping ErrUnsupported.
+func SetProjectID(f *File, projID uint32) error {
+	if err := f.checkValid("ioctl"); err != nil {
+		return err
+	}
+	return f.setProjectID(projID)
+}
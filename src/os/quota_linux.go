@@ -0,0 +1,158 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+import (
+	"internal/itoa"
+	"internal/syscall/unix"
+	"runtime"
+	"syscall"
+	"unsafe"
+)
+
+func projectQuota(path string) (used, limit int64, err error) {
+	f, err := Open(path)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer f.Close()
+	var fsx unix.Fsxattr
+	if err := f.fsxattr(unix.FS_IOC_FSGETXATTR, &fsx); err != nil {
+		return 0, 0, err
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		return 0, 0, err
+	}
+	dev, ok := mountSource(uint64(fi.Sys().(*syscall.Stat_t).Dev))
+	if !ok {
+		// The file system is not mounted from a device, as
+		// quotactl requires.
+		return 0, 0, &PathError{Op: "quotactl", Path: path, Err: ErrUnsupported}
+	}
+
+	var dq unix.Dqblk
+	err = unix.Quotactl(unix.Q_GETQUOTA, unix.PRJQUOTA, dev, int(fsx.Projid), unsafe.Pointer(&dq))
+	if err != nil {
+		switch err {
+		case syscall.ESRCH, syscall.ENOTBLK:
+			// Project quotas are not enabled, or the mount
+			// source is not a block device.
+			err = ErrUnsupported
+		}
+		return 0, 0, &PathError{Op: "quotactl", Path: path, Err: err}
+	}
+	limit = -1
+	if dq.Bhardlimit != 0 {
+		limit = int64(dq.Bhardlimit) * unix.QIF_DQBLKSIZE
+	} else if dq.Bsoftlimit != 0 {
+		limit = int64(dq.Bsoftlimit) * unix.QIF_DQBLKSIZE
+	}
+	return int64(dq.Curspace), limit, nil
+}
+
+func (f *File) setProjectID(projID uint32) error {
+	var fsx unix.Fsxattr
+	if err := f.fsxattr(unix.FS_IOC_FSGETXATTR, &fsx); err != nil {
+		return err
+	}
+	fsx.Projid = projID
+	fi, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	if fi.IsDir() {
+		fsx.Xflags |= unix.FS_XFLAG_PROJINHERIT
+	}
+	return f.fsxattr(unix.FS_IOC_FSSETXATTR, &fsx)
+}
+
+// fsxattr gets or sets the extended attributes of f, as selected by
+// req.
+func (f *File) fsxattr(req uint, fsx *unix.Fsxattr) error {
+	var e error
+	if err := f.pfd.RawControl(func(fd uintptr) {
+		e = unix.IoctlPtr(int(fd), req, unsafe.Pointer(fsx))
+	}); err != nil {
+		return f.wrapErr("ioctl", err)
+	}
+	runtime.KeepAlive(f)
+	if e == syscall.ENOTTY {
+		e = ErrUnsupported
+	}
+	return f.wrapErr("ioctl", e)
+}
+
+// mountSource returns the source, normally a device, from which the
+// file system with device number dev is mounted, as listed in
+// /proc/self/mountinfo.
+func mountSource(dev uint64) (string, bool) {
+	data, err := ReadFile("/proc/self/mountinfo")
+	if err != nil {
+		return "", false
+	}
+	major := uint((dev>>8)&0xfff | (dev>>32)&^0xfff)
+	minor := uint(dev&0xff | (dev>>12)&^0xff)
+	want := itoa.Uitoa(major) + ":" + itoa.Uitoa(minor)
+	for len(data) > 0 {
+		var line []byte
+		line, data = nextLine(data)
+		// 36 35 98:0 /mnt1 /mnt2 rw,noatime master:1 - ext3 /dev/root rw
+		fields := splitSpaces(string(line))
+		if len(fields) < 10 || fields[2] != want {
+			continue
+		}
+		// The optional fields end with a "-", which is followed by
+		// the file system type and the mount source.
+		for i := 6; i+2 < len(fields); i++ {
+			if fields[i] == "-" {
+				return unescapeMountinfo(fields[i+2]), true
+			}
+		}
+	}
+	return "", false
+}
+
+func nextLine(data []byte) (line, rest []byte) {
+	for i, c := range data {
+		if c == '\n' {
+			return data[:i], data[i+1:]
+		}
+	}
+	return data, nil
+}
+
+func splitSpaces(s string) []string {
+	var fields []string
+	start := -1
+	for i := 0; i <= len(s); i++ {
+		if i == len(s) || s[i] == ' ' {
+			if start >= 0 {
+				fields = append(fields, s[start:i])
+				start = -1
+			}
+		} else if start < 0 {
+			start = i
+		}
+	}
+	return fields
+}
+
+// unescapeMountinfo undoes the octal escaping of spaces, tabs, newlines
+// and backslashes in the fields of /proc/self/mountinfo.
+func unescapeMountinfo(s string) string {
+	var b []byte
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+3 < len(s) && isOctal(s[i+1]) && isOctal(s[i+2]) && isOctal(s[i+3]) {
+			b = append(b, (s[i+1]-'0')<<6|(s[i+2]-'0')<<3|(s[i+3]-'0'))
+			i += 3
+			continue
+		}
+		b = append(b, s[i])
+	}
+	return string(b)
+}
+
+func isOctal(c byte) bool { return '0' <= c && c <= '7' }
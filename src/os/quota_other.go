@@ -0,0 +1,16 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !linux
+// +build !linux
+
+package os
+
+func projectQuota(path string) (used, limit int64, err error) {
+	return 0, 0, &PathError{Op: "quotactl", Path: path, Err: ErrUnsupported}
+}
+
+func (f *File) setProjectID(projID uint32) error {
+	return f.wrapErr("ioctl", ErrUnsupported)
+}
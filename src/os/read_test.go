@@ -6,9 +6,16 @@ package os_test
 
 import (
 	"bytes"
+	"errors"
+	"fmt"
+	"io"
 	. "os"
 	"path/filepath"
+	"runtime"
+	"strings"
+	"syscall"
 	"testing"
+	"time"
 )
 
 func checkNamedSize(t *testing.T, path string, size int64) {
@@ -37,6 +44,195 @@ func TestReadFile(t *testing.T) {
 	checkNamedSize(t, filename, int64(len(contents)))
 }
 
+func TestOpenSequential(t *testing.T) {
+	if _, err := OpenSequential("rumpelstilzchen"); !IsNotExist(err) {
+		t.Fatalf("OpenSequential of missing file: got %v, want not-exist error", err)
+	}
+
+	want, err := ReadFile("read_test.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := OpenSequential("read_test.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	got, err := io.ReadAll(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("OpenSequential read %d bytes, want contents of %d bytes", len(got), len(want))
+	}
+	if _, err := f.Write([]byte("x")); err == nil {
+		t.Error("Write to file from OpenSequential succeeded")
+	}
+
+	d, err := OpenSequential(".")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer d.Close()
+	if _, err := d.ReadDir(1); err != nil {
+		t.Errorf("ReadDir of directory from OpenSequential: %v", err)
+	}
+}
+
+func TestOpenNoATime(t *testing.T) {
+	if _, err := OpenNoATime("rumpelstilzchen"); !IsNotExist(err) {
+		t.Fatalf("OpenNoATime of missing file: got %v, want not-exist error", err)
+	}
+
+	want, err := ReadFile("read_test.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := OpenNoATime("read_test.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	got, err := io.ReadAll(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("OpenNoATime read %d bytes, want contents of %d bytes", len(got), len(want))
+	}
+
+	// A file owned by another user can still be opened,
+	// although O_NOATIME is refused for it.
+	if runtime.GOOS == "linux" {
+		if f, err := OpenNoATime("/etc/passwd"); err == nil {
+			f.Close()
+		} else if !IsNotExist(err) {
+			t.Errorf("OpenNoATime(/etc/passwd): %v", err)
+		}
+	}
+}
+
+func TestReadFileLimit(t *testing.T) {
+	filename := "read_test.go"
+	want, err := ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	size := int64(len(want))
+	if got, err := ReadFileLimit(filename, size); err != nil || !bytes.Equal(got, want) {
+		t.Errorf("ReadFileLimit(%s, size) = %d bytes, %v; want %d bytes, nil", filename, len(got), err, size)
+	}
+	if _, err := ReadFileLimit(filename, size-1); !errors.Is(err, ErrFileTooLarge) {
+		t.Errorf("ReadFileLimit(%s, size-1): got %v, want ErrFileTooLarge", filename, err)
+	}
+	if _, err := ReadFileLimit(filename, -1); err == nil {
+		t.Error("ReadFileLimit with negative limit succeeded")
+	}
+
+	// Files in /proc report size 0, so only the read enforces the limit.
+	const proc = "/proc/self/maps"
+	if fi, err := Stat(proc); err == nil && fi.Size() == 0 {
+		data, err := ReadFile(proc)
+		if err != nil || len(data) < 2 {
+			t.Skipf("cannot read %s", proc)
+		}
+		if _, err := ReadFileLimit(proc, 1); !errors.Is(err, ErrFileTooLarge) {
+			t.Errorf("ReadFileLimit(%s, 1): got %v, want ErrFileTooLarge", proc, err)
+		}
+	}
+}
+
+func TestCountLines(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		data string
+		want int64
+	}{
+		{"", 0},
+		{"no newline", 0},
+		{"one\n", 1},
+		{"one\ntwo", 1},
+		{"\n\n\n", 3},
+		{strings.Repeat("line\n", 100000), 100000},
+	}
+	for i, tt := range tests {
+		name := filepath.Join(dir, fmt.Sprint(i))
+		if err := WriteFile(name, []byte(tt.data), 0666); err != nil {
+			t.Fatal(err)
+		}
+		n, err := CountLines(name)
+		if err != nil || n != tt.want {
+			t.Errorf("CountLines of %.20q = %d, %v; want %d, nil", tt.data, n, err, tt.want)
+		}
+	}
+	if _, err := CountLines(filepath.Join(dir, "missing")); !IsNotExist(err) {
+		t.Errorf("CountLines of missing file: got %v, want not-exist error", err)
+	}
+}
+
+func TestLineEnding(t *testing.T) {
+	dir := t.TempDir()
+	const sample = 1 << 10
+	tests := []struct {
+		data       string
+		sampleSize int
+		want       string
+	}{
+		{"", sample, ""},
+		{"no newline", sample, ""},
+		{"a\nb\n", sample, "\n"},
+		{"a\r\nb\r\n", sample, "\r\n"},
+		{"a\rb\r", sample, "\r"},
+		{"a\r\nb\n", sample, "mixed"},
+		{"a\rb\n", sample, "mixed"},
+		// Only the start of the file is examined.
+		{"a\n" + strings.Repeat("x", sample) + "\r\n", sample, "\n"},
+		// A \r\n straddling the end of the sample is seen as such.
+		{strings.Repeat("x", sample-1) + "\r\n", sample, "\r\n"},
+		// With no sample size, the whole file is examined.
+		{"a\n" + strings.Repeat("x", 100<<10) + "\r\n", 0, "mixed"},
+	}
+	for i, tt := range tests {
+		name := filepath.Join(dir, fmt.Sprint(i))
+		if err := WriteFile(name, []byte(tt.data), 0666); err != nil {
+			t.Fatal(err)
+		}
+		got, err := LineEnding(name, tt.sampleSize)
+		if err != nil || got != tt.want {
+			t.Errorf("LineEnding(%.20q, %d) = %q, %v; want %q, nil", tt.data, tt.sampleSize, got, err, tt.want)
+		}
+	}
+	if _, err := LineEnding(filepath.Join(dir, "missing"), sample); !IsNotExist(err) {
+		t.Errorf("LineEnding of missing file: got %v, want not-exist error", err)
+	}
+}
+
+func TestReadFileNoBOM(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		data, want, enc string
+	}{
+		{"", "", ""},
+		{"plain", "plain", ""},
+		{"\xef\xbb\xbfh\xc3\xa9", "h\xc3\xa9", "UTF-8"},
+		{"\xff\xfeh\x00\xe9\x00", "h\xc3\xa9", "UTF-16LE"},
+		{"\xfe\xff\x00h\x00\xe9", "h\xc3\xa9", "UTF-16BE"},
+		{"\xff\xfe\x3d\xd8\x00\xde", "\U0001F600", "UTF-16LE"}, // surrogate pair
+		{"\xff\xfe\x3d\xd8h\x00", "\uFFFDh", "UTF-16LE"},       // unpaired surrogate
+		{"\xff\xfeh\x00i", "h\uFFFD", "UTF-16LE"},              // odd length
+	}
+	for i, tt := range tests {
+		name := filepath.Join(dir, fmt.Sprint(i))
+		if err := WriteFile(name, []byte(tt.data), 0666); err != nil {
+			t.Fatal(err)
+		}
+		data, enc, err := ReadFileNoBOM(name)
+		if err != nil || string(data) != tt.want || enc != tt.enc {
+			t.Errorf("ReadFileNoBOM of %q = %q, %q, %v; want %q, %q, nil", tt.data, data, enc, err, tt.want, tt.enc)
+		}
+	}
+}
+
 func TestWriteFile(t *testing.T) {
 	f, err := CreateTemp("", "ioutil-test")
 	if err != nil {
@@ -95,6 +291,151 @@ func TestReadOnlyWriteFile(t *testing.T) {
 	}
 }
 
+func TestWriteFileAll(t *testing.T) {
+	dir := t.TempDir()
+	name := filepath.Join(dir, "a", "b", "file")
+	for _, data := range []string{"hello", "bye"} {
+		if err := WriteFileAll(name, []byte(data), 0666); err != nil {
+			t.Fatalf("WriteFileAll: %v", err)
+		}
+		got, err := ReadFile(name)
+		if err != nil || string(got) != data {
+			t.Fatalf("ReadFile = %q, %v; want %q", got, err, data)
+		}
+	}
+
+	// A file in place of a parent directory is reported.
+	err := WriteFileAll(filepath.Join(name, "c", "file"), nil, 0666)
+	var pe *PathError
+	if !errors.As(err, &pe) || !errors.Is(err, syscall.ENOTDIR) {
+		t.Errorf("WriteFileAll under a file: got %v, want ENOTDIR", err)
+	}
+}
+
+func TestWriteFileVectored(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "file")
+	// More buffers than a single writev accepts, including empty ones.
+	var bufs [][]byte
+	var want []byte
+	for i := 0; i < 1500; i++ {
+		b := []byte(fmt.Sprint(i, ","))
+		if i%100 == 0 {
+			b = nil
+		}
+		bufs = append(bufs, b)
+		want = append(want, b...)
+	}
+	if err := WriteFile(name, []byte("old contents, to be truncated"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteFileVectored(name, bufs, 0644); err != nil {
+		t.Fatalf("WriteFileVectored: %v", err)
+	}
+	got, err := ReadFile(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("ReadFile = %q, want %q", got, want)
+	}
+	if len(bufs[1]) == 0 {
+		t.Error("WriteFileVectored modified bufs")
+	}
+}
+
+func TestMultiWriteFile(t *testing.T) {
+	dir := t.TempDir()
+	a := filepath.Join(dir, "a")
+	b := filepath.Join(dir, "b")
+	bad := filepath.Join(dir, "missing", "c")
+	err := MultiWriteFile([]string{a, bad, b}, []byte("data"), 0644)
+	var me *MultiWriteError
+	if !errors.As(err, &me) || len(me.Errs) != 1 {
+		t.Fatalf("MultiWriteFile: got %v, want a MultiWriteError with one error", err)
+	}
+	var pe *PathError
+	if !errors.As(me.Errs[0], &pe) || pe.Path != bad || !errors.Is(err, ErrNotExist) {
+		t.Errorf("MultiWriteFile error = %v, want not exist error for %s", me.Errs[0], bad)
+	}
+	for _, name := range []string{a, b} {
+		if got, err := ReadFile(name); err != nil || string(got) != "data" {
+			t.Errorf("ReadFile(%s) = %q, %v; want %q", name, got, err, "data")
+		}
+	}
+}
+
+func TestMultiWriter(t *testing.T) {
+	dir := t.TempDir()
+	a, err := Create(filepath.Join(dir, "a"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer a.Close()
+	b, err := Create(filepath.Join(dir, "b"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer b.Close()
+	ro, err := Open(a.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ro.Close()
+
+	w := MultiWriter(a, b)
+	for _, s := range []string{"hello, ", "world"} {
+		if n, err := io.WriteString(w, s); err != nil || n != len(s) {
+			t.Fatalf("Write = %d, %v; want %d, nil", n, err, len(s))
+		}
+	}
+	for _, f := range []*File{a, b} {
+		if got, err := ReadFile(f.Name()); err != nil || string(got) != "hello, world" {
+			t.Errorf("ReadFile(%s) = %q, %v; want %q", f.Name(), got, err, "hello, world")
+		}
+	}
+
+	// A file that cannot be written does not stop the others.
+	w = MultiWriter(ro, b)
+	n, err := w.Write([]byte("!"))
+	var me *MultiWriteError
+	if !errors.As(err, &me) || len(me.Errs) != 1 || n != 0 {
+		t.Fatalf("Write to read-only file = %d, %v; want 0 and a MultiWriteError", n, err)
+	}
+	var pe *PathError
+	if !errors.As(me.Errs[0], &pe) || pe.Path != ro.Name() {
+		t.Errorf("Write error = %v, want a *PathError for %s", me.Errs[0], ro.Name())
+	}
+	if got, err := ReadFile(b.Name()); err != nil || string(got) != "hello, world!" {
+		t.Errorf("ReadFile(%s) = %q, %v; want %q", b.Name(), got, err, "hello, world!")
+	}
+
+	// A file that failed is not written to again, even if it could be.
+	pr, pw, err := Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pr.Close()
+	defer pw.Close()
+	if err := pw.SetWriteDeadline(time.Now().Add(-time.Hour)); err != nil {
+		t.Skipf("SetWriteDeadline: %v", err)
+	}
+	w = MultiWriter(pw, b)
+	if n, err := w.Write([]byte("x")); !errors.As(err, &me) || !errors.Is(me.Errs[0], ErrDeadlineExceeded) || n != 0 {
+		t.Fatalf("Write past deadline = %d, %v; want 0 and a deadline error", n, err)
+	}
+	pw.SetWriteDeadline(time.Time{})
+	if n, err := w.Write([]byte("y")); !errors.As(err, &me) || !errors.Is(me.Errs[0], ErrDeadlineExceeded) || n != 0 {
+		t.Errorf("Write after failure = %d, %v; want 0 and the earlier error", n, err)
+	}
+	pw.Close()
+	if got, err := io.ReadAll(pr); err != nil || len(got) != 0 {
+		t.Errorf("pipe received %q, %v; want nothing", got, err)
+	}
+	if got, err := ReadFile(b.Name()); err != nil || string(got) != "hello, world!xy" {
+		t.Errorf("ReadFile(%s) = %q, %v; want %q", b.Name(), got, err, "hello, world!xy")
+	}
+}
+
 func TestReadDir(t *testing.T) {
 	dirname := "rumpelstilzchen"
 	_, err := ReadDir(dirname)
@@ -0,0 +1,24 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+import "errors"
+
+// ErrFileTooLarge is returned, wrapped in a *PathError, by ReadFileLimit
+// when a file holds more than the permitted number of bytes.
+var ErrFileTooLarge = errors.New("file too large")
+
+// ReadFileLimit is like ReadFile but fails with ErrFileTooLarge,
+// wrapped in a *PathError, if the named file holds more than max bytes.
+// The limit is checked against the size reported by the file system
+// before reading and is also enforced while reading, so files that grow
+// or that misreport their size, such as those in Linux's /proc, are
+// never read beyond max+1 bytes.
+func ReadFileLimit(name string, max int64) ([]byte, error) {
+	if max < 0 {
+		return nil, &PathError{Op: "read", Path: name, Err: ErrInvalid}
+	}
+	return readFile(name, max)
+}
@@ -100,6 +100,16 @@ func removeAllFrom(parent *File, base string) error {
 			if IsNotExist(err) {
 				return nil
 			}
+			// If the directory was replaced, say by a symbolic link
+			// that openFdAt refused to follow, remove its replacement.
+			statErr := unix.Fstatat(parentFd, base, &statInfo, unix.AT_SYMLINK_NOFOLLOW)
+			if statErr == nil && statInfo.Mode&syscall.S_IFMT != syscall.S_IFDIR {
+				err = unix.Unlinkat(parentFd, base, 0)
+				if err == nil || IsNotExist(err) {
+					return nil
+				}
+				return &PathError{Op: "unlinkat", Path: base, Err: err}
+			}
 			recurseErr = &PathError{Op: "openfdat", Path: base, Err: err}
 			break
 		}
@@ -163,16 +173,90 @@ func removeAllFrom(parent *File, base string) error {
 	return &PathError{Op: "unlinkat", Path: base, Err: unlinkError}
 }
 
+// removeAllExcept implements RemoveAllExcept for path, reporting
+// whether path was removed. As in removeAll, the tree is removed
+// relative to the descriptors of its directories, so that a directory
+// replaced by a symbolic link is not followed.
+func removeAllExcept(path string, d DirEntry, keep func(string, DirEntry) bool) (removed bool, err error) {
+	if keep(path, d) {
+		return false, nil
+	}
+	parentDir, base := splitPath(path)
+	parent, err := Open(parentDir)
+	if IsNotExist(err) {
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	defer parent.Close()
+	return removeAllExceptFrom(int(parent.Fd()), base, path, d, keep, false)
+}
+
+// removeAllExceptFrom removes the entry base of the directory
+// parentFd, whose path is path, except as keep directs. If checkKeep
+// is false, keep has already been called for the entry.
+func removeAllExceptFrom(parentFd int, base, path string, d DirEntry, keep func(string, DirEntry) bool, checkKeep bool) (removed bool, err error) {
+	if checkKeep && keep(path, d) {
+		return false, nil
+	}
+	flags := 0
+	if d.IsDir() {
+		file, err := openFdAt(parentFd, base)
+		if err != nil {
+			if IsNotExist(err) {
+				return true, nil
+			}
+			return false, &PathError{Op: "openfdat", Path: path, Err: err}
+		}
+		// Name the directory by its path, which DirEntry.Info uses.
+		file.name = path
+		// All the entries are read before any is removed, so
+		// removing them cannot cause any to be skipped.
+		entries, rerr := file.ReadDir(-1)
+		if IsNotExist(rerr) {
+			file.Close()
+			return true, nil
+		}
+		err = rerr
+		all := rerr == nil
+		fd := int(file.Fd())
+		for _, e := range entries {
+			r, err1 := removeAllExceptFrom(fd, e.Name(), joinPath(path, e.Name()), e, keep, true)
+			if err == nil {
+				err = err1
+			}
+			all = all && r
+		}
+		file.Close()
+		if !all {
+			return false, err
+		}
+		flags = unix.AT_REMOVEDIR
+	}
+	if err1 := unix.Unlinkat(parentFd, base, flags); err1 != nil && !IsNotExist(err1) {
+		if err == nil {
+			err = &PathError{Op: "unlinkat", Path: path, Err: err1}
+		}
+		return false, err
+	}
+	return true, err
+}
+
 // openFdAt opens path relative to the directory in fd.
 // Other than that this should act like openFileNolog.
 // This acts like openFileNolog rather than OpenFile because
 // we are going to (try to) remove the file.
 // The contents of this file are not relevant for test caching.
+//
+// openFdAt does not follow a symbolic link in name, so that a directory
+// replaced by a link while it is being removed cannot lead RemoveAll
+// outside the tree.
 func openFdAt(dirfd int, name string) (*File, error) {
 	var r int
 	for {
 		var e error
-		r, e = unix.Openat(dirfd, name, O_RDONLY|syscall.O_CLOEXEC, 0)
+		r, e = unix.Openat(dirfd, name, O_RDONLY|syscall.O_CLOEXEC|syscall.O_NOFOLLOW, 0)
 		if e == nil {
 			break
 		}
@@ -141,3 +141,36 @@ func removeAll(path string) error {
 	}
 	return err
 }
+
+// removeAllExcept implements RemoveAllExcept for path, reporting
+// whether path was removed.
+func removeAllExcept(path string, d DirEntry, keep func(string, DirEntry) bool) (removed bool, err error) {
+	if keep(path, d) {
+		return false, nil
+	}
+	if d.IsDir() {
+		entries, rerr := ReadDir(path)
+		if IsNotExist(rerr) {
+			return true, nil
+		}
+		err = rerr
+		all := rerr == nil
+		for _, e := range entries {
+			r, err1 := removeAllExcept(joinPath(path, e.Name()), e, keep)
+			if err == nil {
+				err = err1
+			}
+			all = all && r
+		}
+		if !all {
+			return false, err
+		}
+	}
+	if err1 := Remove(path); err1 != nil && !IsNotExist(err1) {
+		if err == nil {
+			err = err1
+		}
+		return false, err
+	}
+	return true, err
+}
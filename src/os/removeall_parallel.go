@@ -0,0 +1,158 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+import (
+	"sync"
+	"sync/atomic"
+	"syscall"
+)
+
+// RemoveAllParallel is like RemoveAll but removes the entries of each
+// directory concurrently, using a pool of concurrency goroutines that
+// each issue one file system operation at a time. On file systems where
+// each removal has a high latency, such as network file systems, this
+// is much faster than RemoveAll. A directory is removed only once all
+// its entries have been. A concurrency less than 1 is treated as 1.
+//
+// Like RemoveAll, RemoveAllParallel does not follow symbolic links,
+// including ones that replace a directory while it is being removed.
+//
+// RemoveAllParallel removes everything it can but returns the first
+// error it encounters. If the path does not exist, it returns nil.
+// If there is an error, it will be of type *PathError.
+func RemoveAllParallel(path string, concurrency int) error {
+	if path == "" {
+		// Match RemoveAll.
+		return nil
+	}
+	if endsWithDot(path) {
+		return &PathError{Op: "RemoveAll", Path: path, Err: syscall.EINVAL}
+	}
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	return removeAllParallel(path, concurrency)
+}
+
+// A parallelRemover removes a tree with a fixed pool of workers. Each
+// task removes one directory entry; removing a directory adds a task
+// for each of its entries, and the directory itself is removed by
+// whichever worker finishes its last entry.
+type parallelRemover struct {
+	mu    sync.Mutex
+	cond  sync.Cond
+	tasks []removeTask // LIFO, so that the tree is removed depth first
+	done  bool
+	err   error // first error
+}
+
+type removeTask struct {
+	dir  *removeDir
+	name string
+}
+
+// A removeDir is a directory whose entries are being removed.
+type removeDir struct {
+	parent  *removeDir // nil for the directory containing the path
+	name    string     // name in parent
+	path    string     // for errors; "" if name is the path itself
+	f       *File      // the open directory, if the platform uses one
+	fd      int        // the descriptor of f
+	pending int32      // entries not yet removed, accessed atomically
+}
+
+func (d *removeDir) join(name string) string {
+	if d.path == "" {
+		return name
+	}
+	return d.path + string(PathSeparator) + name
+}
+
+// run removes name from root using n workers, and returns the first
+// error.
+func (r *parallelRemover) run(root *removeDir, name string, n int) error {
+	r.cond.L = &r.mu
+	root.pending = 1
+	r.tasks = append(r.tasks, removeTask{root, name})
+	var wg sync.WaitGroup
+	wg.Add(n)
+	for i := 0; i < n; i++ {
+		go func() {
+			defer wg.Done()
+			for {
+				t, ok := r.next()
+				if !ok {
+					return
+				}
+				r.removeEntry(t.dir, t.name)
+			}
+		}()
+	}
+	wg.Wait()
+	return r.err
+}
+
+// next returns the next task, waiting for one if necessary. It returns
+// false once the whole tree has been removed.
+func (r *parallelRemover) next() (removeTask, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for len(r.tasks) == 0 && !r.done {
+		r.cond.Wait()
+	}
+	if r.done {
+		return removeTask{}, false
+	}
+	t := r.tasks[len(r.tasks)-1]
+	r.tasks = r.tasks[:len(r.tasks)-1]
+	return t, true
+}
+
+// addEntries queues the removal of the entries names of d, and then of
+// d itself.
+func (r *parallelRemover) addEntries(d *removeDir, names []string) {
+	if len(names) == 0 {
+		r.dirEmptied(d)
+		return
+	}
+	atomic.StoreInt32(&d.pending, int32(len(names)))
+	r.mu.Lock()
+	for _, name := range names {
+		r.tasks = append(r.tasks, removeTask{d, name})
+	}
+	r.cond.Broadcast()
+	r.mu.Unlock()
+}
+
+// entryDone records that an entry of d has been removed, or could not
+// be, and removes d once it has no entries left.
+func (r *parallelRemover) entryDone(d *removeDir) {
+	if atomic.AddInt32(&d.pending, -1) != 0 {
+		return
+	}
+	if d.parent != nil {
+		r.dirEmptied(d)
+		return
+	}
+	r.mu.Lock()
+	r.done = true
+	r.cond.Broadcast()
+	r.mu.Unlock()
+}
+
+// dirEmptied removes d, whose entries have all been handled.
+func (r *parallelRemover) dirEmptied(d *removeDir) {
+	r.removeDir(d)
+	r.entryDone(d.parent)
+}
+
+func (r *parallelRemover) setErr(err error) {
+	r.mu.Lock()
+	if r.err == nil {
+		r.err = err
+	}
+	r.mu.Unlock()
+}
@@ -0,0 +1,110 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build aix || darwin || dragonfly || freebsd || linux || netbsd || openbsd || solaris
+// +build aix darwin dragonfly freebsd linux netbsd openbsd solaris
+
+package os
+
+import (
+	"internal/syscall/unix"
+	"syscall"
+)
+
+func removeAllParallel(path string, concurrency int) error {
+	// Simple case: if Remove works, we're done.
+	err := Remove(path)
+	if err == nil || IsNotExist(err) {
+		return nil
+	}
+
+	// As in removeAll, the tree is removed relative to the descriptor
+	// of its parent, so that no symbolic link in it is followed.
+	parentDir, base := splitPath(path)
+	parent, err := Open(parentDir)
+	if IsNotExist(err) {
+		// If parent does not exist, base cannot exist. Fail silently
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	root := &removeDir{path: parentDir, f: parent, fd: int(parent.Fd())}
+	var r parallelRemover
+	err = r.run(root, base, concurrency)
+	parent.Close()
+	return err
+}
+
+// removeEntry removes the entry name of d, descending into it if it is
+// a directory. It mirrors removeAllFrom.
+func (r *parallelRemover) removeEntry(d *removeDir, name string) {
+	dirfd := d.fd
+	err := unix.Unlinkat(dirfd, name, 0)
+	if err == nil || IsNotExist(err) {
+		r.entryDone(d)
+		return
+	}
+	if err != syscall.EISDIR && err != syscall.EPERM && err != syscall.EACCES {
+		r.setErr(&PathError{Op: "unlinkat", Path: d.join(name), Err: err})
+		r.entryDone(d)
+		return
+	}
+
+	// Is this a directory we need to recurse into?
+	var st syscall.Stat_t
+	if serr := unix.Fstatat(dirfd, name, &st, unix.AT_SYMLINK_NOFOLLOW); serr != nil {
+		if !IsNotExist(serr) {
+			r.setErr(&PathError{Op: "fstatat", Path: d.join(name), Err: serr})
+		}
+		r.entryDone(d)
+		return
+	}
+	if st.Mode&syscall.S_IFMT != syscall.S_IFDIR {
+		// Not a directory; report the error from unix.Unlinkat.
+		r.setErr(&PathError{Op: "unlinkat", Path: d.join(name), Err: err})
+		r.entryDone(d)
+		return
+	}
+
+	file, err := openFdAt(dirfd, name)
+	if err != nil {
+		if !IsNotExist(err) {
+			// If the directory was replaced, say by a symbolic link
+			// that openFdAt refused to follow, remove its replacement.
+			serr := unix.Fstatat(dirfd, name, &st, unix.AT_SYMLINK_NOFOLLOW)
+			if serr == nil && st.Mode&syscall.S_IFMT != syscall.S_IFDIR {
+				err = unix.Unlinkat(dirfd, name, 0)
+				if err != nil && !IsNotExist(err) {
+					r.setErr(&PathError{Op: "unlinkat", Path: d.join(name), Err: err})
+				}
+			} else {
+				r.setErr(&PathError{Op: "openfdat", Path: d.join(name), Err: err})
+			}
+		}
+		r.entryDone(d)
+		return
+	}
+	// All the names are read before any is removed, so removing them
+	// cannot cause any to be skipped. See issue 20841.
+	names, err := file.Readdirnames(-1)
+	if err != nil {
+		file.Close()
+		if !IsNotExist(err) {
+			r.setErr(&PathError{Op: "readdirnames", Path: d.join(name), Err: err})
+		}
+		r.entryDone(d)
+		return
+	}
+	r.addEntries(&removeDir{parent: d, name: name, path: d.join(name), f: file, fd: int(file.Fd())}, names)
+}
+
+// removeDir closes d and removes it from its parent.
+func (r *parallelRemover) removeDir(d *removeDir) {
+	d.f.Close()
+	err := unix.Unlinkat(d.parent.fd, d.name, unix.AT_REMOVEDIR)
+	if err != nil && !IsNotExist(err) {
+		r.setErr(&PathError{Op: "unlinkat", Path: d.path, Err: err})
+	}
+}
@@ -0,0 +1,69 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !aix && !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd && !solaris
+// +build !aix,!darwin,!dragonfly,!freebsd,!linux,!netbsd,!openbsd,!solaris
+
+package os
+
+import "syscall"
+
+func removeAllParallel(path string, concurrency int) error {
+	var r parallelRemover
+	return r.run(&removeDir{}, path, concurrency)
+}
+
+// removeEntry removes the entry name of d, descending into it if it is
+// a directory. It mirrors removeAll.
+func (r *parallelRemover) removeEntry(d *removeDir, name string) {
+	path := d.join(name)
+	// Simple case: if Remove works, we're done.
+	err := Remove(path)
+	if err == nil || IsNotExist(err) {
+		r.entryDone(d)
+		return
+	}
+
+	// Otherwise, is this a directory we need to recurse into?
+	dir, serr := Lstat(path)
+	if serr != nil {
+		if perr, ok := serr.(*PathError); !ok || !IsNotExist(perr.Err) && perr.Err != syscall.ENOTDIR {
+			r.setErr(serr)
+		}
+		r.entryDone(d)
+		return
+	}
+	if !dir.IsDir() {
+		// Not a directory; report the error from Remove.
+		r.setErr(err)
+		r.entryDone(d)
+		return
+	}
+
+	f, err := Open(path)
+	if err != nil {
+		if !IsNotExist(err) {
+			r.setErr(err)
+		}
+		r.entryDone(d)
+		return
+	}
+	names, err := f.Readdirnames(-1)
+	f.Close()
+	if err != nil {
+		if !IsNotExist(err) {
+			r.setErr(err)
+		}
+		r.entryDone(d)
+		return
+	}
+	r.addEntries(&removeDir{parent: d, name: name, path: path}, names)
+}
+
+// removeDir removes d.
+func (r *parallelRemover) removeDir(d *removeDir) {
+	if err := Remove(d.path); err != nil && !IsNotExist(err) {
+		r.setErr(err)
+	}
+}
@@ -6,6 +6,7 @@ package os_test
 
 import (
 	"fmt"
+	"internal/testenv"
 	"os"
 	. "os"
 	"path/filepath"
@@ -474,3 +475,166 @@ func TestRemoveAllWithMoreErrorThanReqSize(t *testing.T) {
 		t.Fatalf("RemoveAll(<read-only directory>) unexpectedly removed %d read-only files from that directory", 1025-len(names))
 	}
 }
+
+func TestRemoveAllParallel(t *testing.T) {
+	tmpDir := t.TempDir()
+	path := filepath.Join(tmpDir, "_TestRemoveAllParallel_")
+
+	// Make a tree three levels deep, with files and directories
+	// at each level.
+	var mk func(dir string, depth int)
+	mk = func(dir string, depth int) {
+		if err := Mkdir(dir, 0777); err != nil {
+			t.Fatal(err)
+		}
+		for i := 0; i < 5; i++ {
+			if err := WriteFile(filepath.Join(dir, fmt.Sprintf("file%d", i)), nil, 0666); err != nil {
+				t.Fatal(err)
+			}
+			if depth > 0 {
+				mk(filepath.Join(dir, fmt.Sprintf("dir%d", i)), depth-1)
+			}
+		}
+	}
+	mk(path, 3)
+
+	for _, concurrency := range []int{0, 1, 8} {
+		if err := RemoveAllParallel(filepath.Join(path, "dir0"), concurrency); err != nil {
+			t.Fatalf("RemoveAllParallel(dir0, %d): %v", concurrency, err)
+		}
+		if _, err := Lstat(filepath.Join(path, "dir0")); !IsNotExist(err) {
+			t.Fatalf("Lstat after RemoveAllParallel(dir0, %d): %v", concurrency, err)
+		}
+		mk(filepath.Join(path, "dir0"), 2)
+	}
+
+	// A deep chain of directories is removed by the same few workers.
+	deep := filepath.Join(path, "deep")
+	for i := 0; i < 64; i++ {
+		deep = filepath.Join(deep, "d")
+	}
+	if err := MkdirAll(deep, 0777); err != nil {
+		t.Fatal(err)
+	}
+	if err := RemoveAllParallel(filepath.Join(path, "deep"), 2); err != nil {
+		t.Fatalf("RemoveAllParallel of deep tree: %v", err)
+	}
+
+	// A symbolic link to a directory outside the tree is removed
+	// without being followed.
+	outside := filepath.Join(tmpDir, "outside")
+	if err := Mkdir(outside, 0777); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteFile(filepath.Join(outside, "keep"), nil, 0666); err != nil {
+		t.Fatal(err)
+	}
+	haveSymlink := Symlink(outside, filepath.Join(path, "dir1", "link")) == nil
+
+	if err := RemoveAllParallel(path, 8); err != nil {
+		t.Fatalf("RemoveAllParallel %q: %s", path, err)
+	}
+	if _, err := Lstat(path); err == nil {
+		t.Fatalf("Lstat %q succeeded after RemoveAllParallel", path)
+	}
+	if _, err := Lstat(filepath.Join(outside, "keep")); haveSymlink && err != nil {
+		t.Errorf("RemoveAllParallel followed a symbolic link: %v", err)
+	}
+	if err := RemoveAllParallel(path, 8); err != nil {
+		t.Errorf("RemoveAllParallel of missing path: %v", err)
+	}
+	if err := RemoveAllParallel(tmpDir+string(PathSeparator)+".", 8); err == nil {
+		t.Error("RemoveAllParallel of dot succeeded")
+	}
+}
+
+func TestRemoveAllExcept(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "root")
+	for _, name := range []string{
+		"a", "b/keep", "b/drop", "c/d/e/keep", "c/d/drop", "c/drop", "git/x/y", "empty/",
+	} {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if strings.HasSuffix(name, "/") {
+			if err := MkdirAll(path, 0777); err != nil {
+				t.Fatal(err)
+			}
+			continue
+		}
+		if err := MkdirAll(filepath.Dir(path), 0777); err != nil {
+			t.Fatal(err)
+		}
+		if err := WriteFile(path, nil, 0666); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var inKept bool
+	keep := func(path string, d DirEntry) bool {
+		if strings.Contains(path, "git"+string(PathSeparator)) {
+			inKept = true
+		}
+		return d.Name() == "keep" || d.Name() == "git"
+	}
+	if err := RemoveAllExcept(root, keep); err != nil {
+		t.Fatal(err)
+	}
+	if inKept {
+		t.Error("keep was called inside a kept directory")
+	}
+
+	var left []string
+	filepath.WalkDir(root, func(path string, d DirEntry, err error) error {
+		if err != nil {
+			t.Fatal(err)
+		}
+		rel, _ := filepath.Rel(root, path)
+		left = append(left, filepath.ToSlash(rel))
+		return nil
+	})
+	want := []string{".", "b", "b/keep", "c", "c/d", "c/d/e", "c/d/e/keep", "git", "git/x", "git/x/y"}
+	if strings.Join(left, " ") != strings.Join(want, " ") {
+		t.Errorf("left %v, want %v", left, want)
+	}
+
+	// With nothing kept, root itself goes.
+	if err := RemoveAllExcept(root, func(string, DirEntry) bool { return false }); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Lstat(root); !IsNotExist(err) {
+		t.Errorf("root not removed: %v", err)
+	}
+	if err := RemoveAllExcept(root, keep); err != nil {
+		t.Errorf("RemoveAllExcept of missing root: %v", err)
+	}
+
+	// A directory replaced by a symbolic link after keep is called
+	// for it is not followed.
+	if !testenv.HasSymlink() {
+		return
+	}
+	outside := filepath.Join(t.TempDir(), "outside")
+	if err := MkdirAll(outside, 0777); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteFile(filepath.Join(outside, "precious"), nil, 0666); err != nil {
+		t.Fatal(err)
+	}
+	swap := filepath.Join(root, "swap")
+	if err := MkdirAll(swap, 0777); err != nil {
+		t.Fatal(err)
+	}
+	RemoveAllExcept(root, func(path string, d DirEntry) bool {
+		if path == swap {
+			if err := Rename(swap, swap+".old"); err != nil {
+				t.Fatal(err)
+			}
+			if err := Symlink(outside, swap); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return false
+	})
+	if _, err := Lstat(filepath.Join(outside, "precious")); err != nil {
+		t.Errorf("RemoveAllExcept followed a symbolic link: %v", err)
+	}
+}
@@ -0,0 +1,54 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+import (
+	"errors"
+	"time"
+)
+
+// RemoveRetry is like Remove, but retries up to attempts times in all
+// if the removal fails with an error that is likely to be transient.
+// It waits for backoff before the second attempt, doubling the wait
+// before each attempt after that. It returns the error from the last
+// attempt. An attempts value less than 1 is treated as 1.
+//
+// On Windows, a file cannot be removed while another process, such as
+// a virus scanner or search indexer, briefly has it open, and Remove
+// fails with a sharing violation, lock violation or access denied
+// error; RemoveRetry retries those. Errors such as ErrNotExist are
+// never retried. On other systems there are no such transient errors,
+// so RemoveRetry calls Remove once.
+func RemoveRetry(name string, attempts int, backoff time.Duration) error {
+	err := Remove(name)
+	for i := 1; i < attempts && err != nil && isTransientRemoveError(err); i++ {
+		time.Sleep(backoff)
+		backoff *= 2
+		err = Remove(name)
+	}
+	return err
+}
+
+// ErrRemoveDeferred is returned, wrapped in a *PathError, by
+// RemoveOnReboot when a file could not be removed immediately and
+// has instead been scheduled for removal when the system restarts.
+var ErrRemoveDeferred = errors.New("removal deferred until reboot")
+
+// RemoveOnReboot removes the named file or (empty) directory like
+// Remove. On Windows, if the file cannot be removed because it is in
+// use, as a running executable replacing itself is, RemoveOnReboot
+// instead asks the system to remove it the next time it starts, using
+// MoveFileEx with MOVEFILE_DELAY_UNTIL_REBOOT, and returns an error
+// wrapping ErrRemoveDeferred. Scheduling the removal requires
+// administrator rights; if it fails, RemoveOnReboot returns the error
+// from the attempt to remove the file. On other systems, files in use
+// can be removed, so RemoveOnReboot is the same as Remove.
+func RemoveOnReboot(name string) error {
+	err := Remove(name)
+	if err != nil && isTransientRemoveError(err) && removeOnReboot(name) == nil {
+		return &PathError{Op: "remove", Path: name, Err: ErrRemoveDeferred}
+	}
+	return err
+}
@@ -0,0 +1,16 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !windows
+// +build !windows
+
+package os
+
+func isTransientRemoveError(err error) bool {
+	return false
+}
+
+func removeOnReboot(name string) error {
+	return ErrUnsupported
+}
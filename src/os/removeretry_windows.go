@@ -0,0 +1,30 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+import (
+	"internal/syscall/windows"
+	"syscall"
+)
+
+// isTransientRemoveError reports whether err, returned by Remove, may
+// be caused by another process having the file open, so that removing
+// it again a little later may succeed.
+func isTransientRemoveError(err error) bool {
+	switch underlyingError(err) {
+	case windows.ERROR_SHARING_VIOLATION, windows.ERROR_LOCK_VIOLATION, syscall.ERROR_ACCESS_DENIED:
+		return true
+	}
+	return false
+}
+
+// removeOnReboot schedules name to be removed when the system restarts.
+func removeOnReboot(name string) error {
+	p, err := syscall.UTF16PtrFromString(fixLongPath(name))
+	if err != nil {
+		return err
+	}
+	return windows.MoveFileEx(p, nil, windows.MOVEFILE_DELAY_UNTIL_REBOOT)
+}
@@ -0,0 +1,39 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+// Reopen opens the file f again, returning a new, independent File
+// with the same name, opened with flag as for OpenFile. This can be
+// used, for example, to obtain a read-only handle on a file that f has
+// open for writing. Reopen never creates a file: the O_CREATE and
+// O_EXCL flags are ignored.
+//
+// On Linux, Reopen opens the file through /proc/self/fd, so the new
+// File refers to exactly the same file as f even if it has since been
+// renamed or removed. Elsewhere, or if /proc is not mounted, Reopen
+// opens f.Name() again, which may by then name a different file, or
+// none.
+func (f *File) Reopen(flag int) (*File, error) {
+	if err := f.checkValid("reopen"); err != nil {
+		return nil, err
+	}
+	return f.reopen(flag &^ (O_CREATE | O_EXCL))
+}
+
+// reopenByName implements Reopen by opening f's name again.
+func (f *File) reopenByName(flag int) (*File, error) {
+	// Fail, as f's other methods do, if f has been closed.
+	if _, err := f.Stat(); err != nil {
+		return nil, &PathError{Op: "reopen", Path: f.name, Err: underlyingError(err)}
+	}
+	nf, err := OpenFile(f.name, flag, 0)
+	if err != nil {
+		if pe, ok := err.(*PathError); ok {
+			pe.Op = "reopen"
+		}
+		return nil, err
+	}
+	return nf, nil
+}
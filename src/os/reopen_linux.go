@@ -0,0 +1,31 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+import (
+	"internal/itoa"
+	"syscall"
+)
+
+func (f *File) reopen(flag int) (*File, error) {
+	var nf *File
+	var err error
+	if cerr := f.pfd.RawControl(func(fd uintptr) {
+		nf, err = OpenFile("/proc/self/fd/"+itoa.Uitoa(uint(fd)), flag, 0)
+	}); cerr != nil {
+		return nil, &PathError{Op: "reopen", Path: f.name, Err: cerr}
+	}
+	if err != nil {
+		if underlyingErrorIs(err, syscall.ENOENT) {
+			if _, serr := Stat("/proc/self/fd"); serr != nil {
+				// No /proc.
+				return f.reopenByName(flag)
+			}
+		}
+		return nil, &PathError{Op: "reopen", Path: f.name, Err: underlyingError(err)}
+	}
+	nf.name = f.name
+	return nf, nil
+}
@@ -0,0 +1,12 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !windows
+// +build !windows
+
+package os
+
+func reparseTag(name string) (uint32, string, error) {
+	return 0, "", &PathError{Op: "reparsetag", Path: name, Err: ErrUnsupported}
+}
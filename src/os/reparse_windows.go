@@ -0,0 +1,67 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+import (
+	"internal/syscall/windows"
+	"syscall"
+	"unsafe"
+)
+
+func reparseTag(name string) (uint32, string, error) {
+	h, err := openSymlink(fixLongPath(name))
+	if err != nil {
+		return 0, "", &PathError{Op: "reparsetag", Path: name, Err: err}
+	}
+	defer syscall.CloseHandle(h)
+
+	buf := make([]byte, syscall.MAXIMUM_REPARSE_DATA_BUFFER_SIZE)
+	var n uint32
+	err = syscall.DeviceIoControl(h, syscall.FSCTL_GET_REPARSE_POINT, nil, 0, &buf[0], uint32(len(buf)), &n, nil)
+	if err != nil {
+		return 0, "", &PathError{Op: "reparsetag", Path: name, Err: err}
+	}
+
+	rdb := (*windows.REPARSE_DATA_BUFFER)(unsafe.Pointer(&buf[0]))
+	var s string
+	switch rdb.ReparseTag {
+	case ReparseTagSymlink:
+		s = (*windows.SymbolicLinkReparseBuffer)(unsafe.Pointer(&rdb.DUMMYUNIONNAME)).Path()
+	case ReparseTagMountPoint:
+		s = (*windows.MountPointReparseBuffer)(unsafe.Pointer(&rdb.DUMMYUNIONNAME)).Path()
+	case ReparseTagAppExecLink:
+		s = appExecLinkTarget(buf[unsafe.Offsetof(rdb.DUMMYUNIONNAME):n])
+	}
+	return rdb.ReparseTag, s, nil
+}
+
+// appExecLinkTarget returns the target executable recorded in the
+// data of an IO_REPARSE_TAG_APPEXECLINK reparse point. The data is a
+// version number followed by NUL-terminated UTF-16 strings: the
+// package ID, the application user model ID and the target path.
+func appExecLinkTarget(b []byte) string {
+	if len(b) < 4 {
+		return ""
+	}
+	b = b[4:]
+	u := make([]uint16, len(b)/2)
+	for i := range u {
+		u[i] = uint16(b[2*i]) | uint16(b[2*i+1])<<8
+	}
+	for field := 0; field < 3; field++ {
+		i := 0
+		for i < len(u) && u[i] != 0 {
+			i++
+		}
+		if field == 2 {
+			return syscall.UTF16ToString(u[:i])
+		}
+		if i == len(u) {
+			break
+		}
+		u = u[i+1:]
+	}
+	return ""
+}
@@ -0,0 +1,432 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+import (
+	"internal/itoa"
+	"io"
+	"sync"
+	"time"
+)
+
+// A RotatingFile is a file, such as a log, that is appended to and
+// that is rotated when it grows too large or, if opened with
+// OpenRotatingTime, at regular intervals. To rotate the file, it is
+// renamed to a backup name and a new, empty file is started.
+//
+// Each Write is written whole to a single file, rotating first if it
+// would take the file past its maximum size, so lines written with
+// single calls to Write are never split between files. A RotatingFile
+// is safe for concurrent use; writes made while the file is rotated
+// wait for the rotation to finish.
+type RotatingFile struct {
+	mu         sync.Mutex
+	name       string
+	maxSize    int64
+	maxBackups int
+	f          *File // nil if the file could not be reopened
+	size       int64
+	closed     bool
+
+	// For OpenRotatingTime.
+	interval  time.Duration
+	maxAge    time.Duration
+	period    time.Time  // start of the interval the file belongs to
+	cleanupMu sync.Mutex // held while removing old backups
+
+	// For SetCompression.
+	compressExt string
+	compress    func(dst io.Writer, src io.Reader) error
+	compressing sync.WaitGroup            // backups being compressed
+	jobs        map[*compressJob]struct{} // backups being compressed; guarded by mu
+}
+
+// A compressJob is a backup being compressed. While it is compressed,
+// the backup is kept under a temporary name, so that rotations can go
+// on renaming the other backups.
+type compressJob struct {
+	src     string // the backup's temporary name
+	index   int    // for OpenRotating, the backup's number
+	name    string // for OpenRotatingTime, the backup's name
+	dropped bool   // the backup has aged out
+}
+
+// OpenRotating opens the named file for appending, creating it with
+// mode 0666 (before umask) if necessary, and returns a RotatingFile
+// that rotates it when a write would make it larger than maxSize
+// bytes. The file is renamed to name.1, any earlier backups are
+// renamed from name.1 to name.2 and so on, and the oldest are removed,
+// so that at most maxBackups old files are kept. A maxSize of zero or
+// less disables rotation, and a maxBackups of zero or less keeps no
+// backups, so that rotation simply empties the file.
+func OpenRotating(name string, maxSize int64, maxBackups int) (*RotatingFile, error) {
+	r := &RotatingFile{name: name, maxSize: maxSize, maxBackups: maxBackups}
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// SetCompression arranges for each backup made when r is rotated to
+// be compressed in the background, so that writes are not delayed.
+// The backup's contents are passed to compress, which writes the
+// compressed form, and the result replaces the backup under its name
+// with ext appended. Package os cannot itself depend on compress/gzip,
+// so a program wanting gzip-compressed backups passes a function that
+// uses it:
+//
+//	r.SetCompression(".gz", func(dst io.Writer, src io.Reader) error {
+//		zw := gzip.NewWriter(dst)
+//		if _, err := io.Copy(zw, src); err != nil {
+//			return err
+//		}
+//		return zw.Close()
+//	})
+//
+// Compressed backups count toward the number of backups and are aged
+// out like uncompressed ones. While a backup is compressed it is kept
+// under a temporary name beginning with a dot, in the same directory,
+// so that rotations need not wait for it; Close does wait for it. If
+// compression fails, the backup is left uncompressed; if the program
+// exits before it finishes, the backup is left under the temporary
+// name. Passing a nil compress turns compression off.
+func (r *RotatingFile) SetCompression(ext string, compress func(dst io.Writer, src io.Reader) error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.compressExt = ext
+	r.compress = compress
+}
+
+// backUp renames r's file to the backup name, or, if r is set to
+// compress backups, to a temporary name from which it is compressed
+// in the background. For OpenRotating, index is the number of the
+// backup; for OpenRotatingTime it is zero. r.mu must be held.
+func (r *RotatingFile) backUp(index int, name string) error {
+	if r.compress == nil {
+		return Rename(r.name, name)
+	}
+	// Reserve a temporary name and move the file over it.
+	tmp, err := CreateTemp(dirname(r.name), "."+basename(r.name)+"*.rotated")
+	if err != nil {
+		return err
+	}
+	tmp.Close()
+	if err := Rename(r.name, tmp.Name()); err != nil {
+		Remove(tmp.Name())
+		return err
+	}
+	j := &compressJob{src: tmp.Name(), index: index}
+	if index == 0 {
+		j.name = name
+	}
+	if r.jobs == nil {
+		r.jobs = make(map[*compressJob]struct{})
+	}
+	r.jobs[j] = struct{}{}
+	r.compressing.Add(1)
+	go r.compressBackup(j, r.compressExt, r.compress)
+	return nil
+}
+
+// compressBackup compresses the backup of j and puts it in place
+// under its current name with ext appended, or, if compression fails,
+// puts the backup in place uncompressed.
+func (r *RotatingFile) compressBackup(j *compressJob, ext string, compress func(io.Writer, io.Reader) error) {
+	defer r.compressing.Done()
+	tmp, err := compressFile(j.src, compress)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.jobs, j)
+	name := j.name
+	if name == "" {
+		name = r.backupName(j.index)
+	}
+	switch {
+	case j.dropped:
+		Remove(j.src)
+		if err == nil {
+			Remove(tmp)
+		}
+	case err == nil && Rename(tmp, name+ext) == nil:
+		Remove(j.src)
+	default:
+		if err == nil {
+			Remove(tmp)
+		}
+		Rename(j.src, name)
+	}
+}
+
+// compressFile writes the contents of the file name, compressed by
+// compress, to a new temporary file, and returns the temporary file's
+// name.
+func compressFile(name string, compress func(io.Writer, io.Reader) error) (string, error) {
+	src, err := Open(name)
+	if err != nil {
+		return "", err
+	}
+	defer src.Close()
+	fi, err := src.Stat()
+	if err != nil {
+		return "", err
+	}
+	tmp, err := CreateTemp(dirname(name), basename(name)+"*.tmp")
+	if err != nil {
+		return "", err
+	}
+	err = compress(tmp, src)
+	if err == nil {
+		err = tmp.Chmod(fi.Mode().Perm())
+	}
+	if err1 := tmp.Close(); err == nil {
+		err = err1
+	}
+	if err != nil {
+		Remove(tmp.Name())
+		return "", err
+	}
+	return tmp.Name(), nil
+}
+
+// open opens r's file for appending and records its size.
+func (r *RotatingFile) open() error {
+	f, err := OpenFile(r.name, O_WRONLY|O_APPEND|O_CREATE, 0666)
+	if err != nil {
+		return err
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	r.f = f
+	r.size = fi.Size()
+	if r.interval > 0 {
+		r.period = fi.ModTime().Truncate(r.interval)
+	}
+	return nil
+}
+
+// OpenRotatingTime opens the named file for appending, creating it
+// with mode 0666 (before umask) if necessary, and returns a
+// RotatingFile that rotates it once per interval. The intervals are
+// counted from the zero time, so an interval of 24 hours rotates the
+// file at midnight UTC. The file is rotated on the first write after
+// an interval ends, as is a file opened by OpenRotatingTime that was
+// last modified in an earlier interval. Each backup is named after the
+// start of the interval it covers, as in name-2006-01-02 for intervals
+// of whole days or name-2006-01-02T15-04-05 otherwise, in UTC.
+//
+// After each rotation, backups last modified more than maxAge ago are
+// removed in the background, without delaying writes. A maxAge of zero
+// or less keeps all backups.
+func OpenRotatingTime(name string, interval, maxAge time.Duration) (*RotatingFile, error) {
+	if interval <= 0 {
+		return nil, &PathError{Op: "open", Path: name, Err: ErrInvalid}
+	}
+	r := &RotatingFile{name: name, interval: interval, maxAge: maxAge}
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Write appends b to the file, rotating it first if the file is not
+// empty and writing b would take it past the maximum size. It returns
+// the number of bytes written and an error, if any.
+func (r *RotatingFile) Write(b []byte) (n int, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		return 0, &PathError{Op: "write", Path: r.name, Err: ErrClosed}
+	}
+	if r.f == nil {
+		// A previous rotation failed to open the new file.
+		if err := r.open(); err != nil {
+			return 0, err
+		}
+	}
+	if r.interval > 0 {
+		if now := time.Now(); !now.Before(r.period.Add(r.interval)) {
+			if err := r.rotateTime(now); err != nil {
+				return 0, err
+			}
+		}
+	} else if r.maxSize > 0 && r.size > 0 && r.size+int64(len(b)) > r.maxSize {
+		if err := r.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err = r.f.Write(b)
+	r.size += int64(n)
+	return n, err
+}
+
+// rotate closes the current file, shifts the backups and opens a new
+// file. r.mu must be held.
+func (r *RotatingFile) rotate() error {
+	// Close first: on Windows an open file cannot be renamed.
+	err := r.f.Close()
+	r.f = nil
+	if err != nil {
+		return err
+	}
+	if r.maxBackups <= 0 {
+		if err := Remove(r.name); err != nil && !IsNotExist(err) {
+			return err
+		}
+		return r.open()
+	}
+	// Backups may be compressed or not, so handle both names.
+	exts := []string{""}
+	if r.compressExt != "" {
+		exts = append(exts, r.compressExt)
+	}
+	for _, ext := range exts {
+		if err := Remove(r.backupName(r.maxBackups) + ext); err != nil && !IsNotExist(err) {
+			return err
+		}
+		for i := r.maxBackups - 1; i >= 1; i-- {
+			if err := Rename(r.backupName(i)+ext, r.backupName(i+1)+ext); err != nil && !IsNotExist(err) {
+				return err
+			}
+		}
+	}
+	// Backups being compressed are not on disk under their names,
+	// so they are shifted here.
+	for j := range r.jobs {
+		if j.name == "" {
+			j.index++
+			j.dropped = j.index > r.maxBackups
+		}
+	}
+	if err := r.backUp(1, r.backupName(1)); err != nil && !IsNotExist(err) {
+		return err
+	}
+	return r.open()
+}
+
+// rotateTime renames the current file after its interval and opens a
+// new file for the interval containing now. r.mu must be held.
+func (r *RotatingFile) rotateTime(now time.Time) error {
+	if r.size == 0 {
+		// Nothing to keep.
+		r.period = now.Truncate(r.interval)
+		return nil
+	}
+	err := r.f.Close()
+	r.f = nil
+	if err != nil {
+		return err
+	}
+	// If the clock has gone backward, the name may be taken.
+	stamp := r.name + "-" + r.period.UTC().Format(r.layout())
+	backup := stamp
+	for i := 1; r.taken(backup); i++ {
+		backup = stamp + "." + itoa.Itoa(i)
+	}
+	if err := r.backUp(0, backup); err != nil && !IsNotExist(err) {
+		return err
+	}
+	if err := r.open(); err != nil {
+		return err
+	}
+	r.period = now.Truncate(r.interval)
+	if r.maxAge > 0 {
+		go r.removeOld(now.Add(-r.maxAge), r.compressExt)
+	}
+	return nil
+}
+
+// taken reports whether the backup name is in use, compressed or not.
+// r.mu must be held.
+func (r *RotatingFile) taken(backup string) bool {
+	for j := range r.jobs {
+		if j.name == backup {
+			return true
+		}
+	}
+	if _, err := Lstat(backup); !IsNotExist(err) {
+		return true
+	}
+	if r.compressExt != "" {
+		if _, err := Lstat(backup + r.compressExt); !IsNotExist(err) {
+			return true
+		}
+	}
+	return false
+}
+
+// layout returns the time layout used in the names of r's backups.
+func (r *RotatingFile) layout() string {
+	if r.interval%(24*time.Hour) == 0 {
+		return "2006-01-02"
+	}
+	return "2006-01-02T15-04-05"
+}
+
+// removeOld removes r's time-named backups last modified before
+// cutoff, including those compressed with extension ext.
+func (r *RotatingFile) removeOld(cutoff time.Time, ext string) {
+	r.cleanupMu.Lock()
+	defer r.cleanupMu.Unlock()
+	dir, base := dirname(r.name), basename(r.name)
+	entries, _ := ReadDir(dir)
+	for _, e := range entries {
+		if !r.isTimeBackup(base, e.Name(), ext) {
+			continue
+		}
+		if fi, err := e.Info(); err == nil && fi.Mode().IsRegular() && fi.ModTime().Before(cutoff) {
+			Remove(joinPath(dir, e.Name()))
+		}
+	}
+}
+
+// isTimeBackup reports whether name is the name of a backup of the
+// file base made by rotateTime, possibly compressed with extension ext.
+func (r *RotatingFile) isTimeBackup(base, name, ext string) bool {
+	if len(name) <= len(base)+1 || name[:len(base)+1] != base+"-" {
+		return false
+	}
+	stamp := name[len(base)+1:]
+	layout := r.layout()
+	if len(stamp) < len(layout) {
+		return false
+	}
+	if _, err := time.Parse(layout, stamp[:len(layout)]); err != nil {
+		return false
+	}
+	// Allow a numeric suffix and the compression extension.
+	rest := stamp[len(layout):]
+	if ext != "" && len(rest) >= len(ext) && rest[len(rest)-len(ext):] == ext {
+		rest = rest[:len(rest)-len(ext)]
+	}
+	return rest == "" || rest[0] == '.' && isDigits(rest[1:])
+}
+
+// backupName returns the name of the i'th most recent backup.
+func (r *RotatingFile) backupName(i int) string {
+	return r.name + "." + itoa.Itoa(i)
+}
+
+// Close closes the file, and waits for backups to be compressed.
+func (r *RotatingFile) Close() error {
+	r.mu.Lock()
+	if r.closed {
+		r.mu.Unlock()
+		return &PathError{Op: "close", Path: r.name, Err: ErrClosed}
+	}
+	r.closed = true
+	var err error
+	if r.f != nil {
+		err = r.f.Close()
+		r.f = nil
+	}
+	r.mu.Unlock()
+	// Compression finishes by taking r.mu, so wait without it.
+	r.compressing.Wait()
+	return err
+}
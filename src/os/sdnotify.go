@@ -0,0 +1,65 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+import "time"
+
+// SdNotify sends state to the service manager using the systemd
+// notification protocol, as sd_notify(3) does. state holds one or more
+// newline-separated assignments such as "READY=1" when a service of
+// Type=notify has finished starting, "STATUS=..." to describe what it
+// is doing, or "WATCHDOG=1" to keep the watchdog from restarting it.
+//
+// The notification is sent as a datagram to the Unix-domain socket
+// named by the NOTIFY_SOCKET environment variable. If that variable is
+// unset or empty, the process is not running under a service manager
+// that wants notifications, and SdNotify returns false and a nil error.
+// It returns true if the notification was sent.
+//
+// SdNotify is only supported on Linux; elsewhere it returns false and
+// a nil error.
+func SdNotify(state string) (bool, error) {
+	return sdNotify(state)
+}
+
+// SdWatchdogEnabled reports whether the service manager expects this
+// process to send keep-alive notifications, and if so, how often. It
+// reads the WATCHDOG_USEC environment variable, set by systemd to the
+// watchdog timeout when WatchdogSec= is configured, and returns half
+// the timeout as the interval at which to call SdNotify("WATCHDOG=1").
+// If WATCHDOG_PID is set and names another process, the variables were
+// meant for that process, perhaps a parent, and SdWatchdogEnabled
+// returns 0, false. It also returns 0, false if WATCHDOG_USEC is unset
+// or is not a positive integer.
+func SdWatchdogEnabled() (time.Duration, bool) {
+	if pid := Getenv("WATCHDOG_PID"); pid != "" {
+		if n, ok := parseDecimal(pid); !ok || n != Getpid() {
+			return 0, false
+		}
+	}
+	usec, ok := parseDecimal(Getenv("WATCHDOG_USEC"))
+	if !ok || usec == 0 || int64(usec) > int64(1<<63-1)/int64(time.Microsecond) {
+		return 0, false
+	}
+	return time.Duration(usec) * time.Microsecond / 2, true
+}
+
+// parseDecimal parses s as a non-negative decimal integer,
+// reporting false if s is empty, malformed or out of range.
+func parseDecimal(s string) (int, bool) {
+	if !isDigits(s) {
+		return 0, false
+	}
+	const max = int(^uint(0) >> 1)
+	n := 0
+	for i := 0; i < len(s); i++ {
+		d := int(s[i] - '0')
+		if n > (max-d)/10 {
+			return 0, false
+		}
+		n = n*10 + d
+	}
+	return n, true
+}
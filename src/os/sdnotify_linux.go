@@ -0,0 +1,29 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+import "syscall"
+
+func sdNotify(state string) (bool, error) {
+	addr := Getenv("NOTIFY_SOCKET")
+	if addr == "" {
+		return false, nil
+	}
+	// The address is a file system path or, if it begins
+	// with '@', a name in the abstract namespace, which
+	// syscall.SockaddrUnix handles.
+	if addr[0] != '/' && addr[0] != '@' {
+		return false, &PathError{Op: "sdnotify", Path: addr, Err: ErrInvalid}
+	}
+	fd, err := syscall.Socket(syscall.AF_UNIX, syscall.SOCK_DGRAM|syscall.SOCK_CLOEXEC, 0)
+	if err != nil {
+		return false, NewSyscallError("socket", err)
+	}
+	defer syscall.Close(fd)
+	if err := syscall.Sendto(fd, []byte(state), 0, &syscall.SockaddrUnix{Name: addr}); err != nil {
+		return false, &PathError{Op: "sdnotify", Path: addr, Err: err}
+	}
+	return true, nil
+}
@@ -0,0 +1,79 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+import "io/fs"
+
+// OpenInPath looks for a file called name in each of the directories
+// in dirs, in order, and opens the first one found for reading, as
+// Open does. It returns the File and the directory in which it was
+// found. An empty string in dirs means the current directory. Entries
+// that are directories, or that cannot be opened, are skipped, so name
+// may be a relative path such as "conf/app.json" but should name a file.
+//
+// If no directory yields a file, OpenInPath returns a *PathError that
+// lists the directories searched and that wraps ErrNotExist.
+func OpenInPath(name string, dirs []string) (*File, string, error) {
+	for _, dir := range dirs {
+		f, err := Open(searchPathJoin(dir, name))
+		if err != nil {
+			continue
+		}
+		if fi, err := f.Stat(); err != nil || fi.IsDir() {
+			f.Close()
+			continue
+		}
+		return f, dir, nil
+	}
+	return nil, "", &PathError{Op: "open", Path: name, Err: &searchPathError{dirs}}
+}
+
+// FindInPath is like OpenInPath but, rather than opening the file,
+// returns its path, formed by joining the directory in which it was
+// found and name. It uses Stat to test each candidate, so it does not
+// check that the file can be opened.
+func FindInPath(name string, dirs []string) (string, error) {
+	for _, dir := range dirs {
+		path := searchPathJoin(dir, name)
+		if fi, err := Stat(path); err == nil && !fi.IsDir() {
+			return path, nil
+		}
+	}
+	return "", &PathError{Op: "find", Path: name, Err: &searchPathError{dirs}}
+}
+
+func searchPathJoin(dir, name string) string {
+	if dir == "" {
+		dir = "."
+	}
+	return joinPath(dir, name)
+}
+
+// searchPathError records the directories searched by OpenInPath or
+// FindInPath without finding the file.
+type searchPathError struct {
+	dirs []string
+}
+
+func (e *searchPathError) Error() string {
+	if len(e.dirs) == 0 {
+		return "not found: no directories to search"
+	}
+	s := "not found in "
+	for i, dir := range e.dirs {
+		if i > 0 {
+			s += ", "
+		}
+		if dir == "" {
+			dir = "."
+		}
+		s += dir
+	}
+	return s
+}
+
+func (e *searchPathError) Is(target error) bool {
+	return target == fs.ErrNotExist
+}
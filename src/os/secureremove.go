@@ -0,0 +1,77 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+import "errors"
+
+// SecureRemove overwrites the contents of the named file with random
+// data passes times, flushing each pass to stable storage with Sync,
+// and then removes the file. It overwrites the file's full logical
+// length, including any holes in a sparse file. The file must be a
+// regular file; SecureRemove does not follow symbolic links.
+//
+// SecureRemove is a best-effort measure. On solid-state drives, on
+// copy-on-write or journaling file systems, and on file systems with
+// snapshots or backups, the overwritten data may survive elsewhere on
+// the device, so SecureRemove does not guarantee that the original
+// contents cannot be recovered. It is mainly useful on conventional
+// magnetic disks with file systems that update data in place.
+//
+// If overwriting fails, the file is not removed.
+func SecureRemove(name string, passes int) error {
+	if passes < 1 {
+		return &PathError{Op: "secureremove", Path: name, Err: ErrInvalid}
+	}
+	f, err := openForOverwrite(name)
+	if err != nil {
+		return err
+	}
+	// Check the type of the file that was opened, rather than that
+	// of name, in case the file was replaced.
+	fi, err := f.Stat()
+	if err == nil && !fi.Mode().IsRegular() {
+		err = &PathError{Op: "secureremove", Path: name, Err: errors.New("not a regular file")}
+	}
+	if err == nil {
+		err = overwrite(f, fi.Size(), passes)
+	}
+	if err1 := f.Close(); err1 != nil && err == nil {
+		err = err1
+	}
+	if err != nil {
+		return err
+	}
+	return Remove(name)
+}
+
+// overwrite fills the first size bytes of f with random data passes
+// times.
+func overwrite(f *File, size int64, passes int) error {
+	buf := make([]byte, 32*1024)
+	for i := 0; i < passes; i++ {
+		for off := int64(0); off < size; {
+			b := buf
+			if rem := size - off; rem < int64(len(b)) {
+				b = b[:rem]
+			}
+			for j := 0; j < len(b); j += 4 {
+				r := fastrand()
+				for k := j; k < j+4 && k < len(b); k++ {
+					b[k] = byte(r)
+					r >>= 8
+				}
+			}
+			n, err := f.WriteAt(b, off)
+			if err != nil {
+				return err
+			}
+			off += int64(n)
+		}
+		if err := f.Sync(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
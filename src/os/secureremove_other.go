@@ -0,0 +1,24 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !aix && !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd && !solaris
+// +build !aix,!darwin,!dragonfly,!freebsd,!linux,!netbsd,!openbsd,!solaris
+
+package os
+
+import "errors"
+
+// openForOverwrite opens the named file for writing. There is no way to
+// refuse to follow a symbolic link when opening the file, so a symbolic
+// link is rejected beforehand.
+func openForOverwrite(name string) (*File, error) {
+	fi, err := Lstat(name)
+	if err != nil {
+		return nil, err
+	}
+	if fi.Mode()&ModeSymlink != 0 {
+		return nil, &PathError{Op: "secureremove", Path: name, Err: errors.New("not a regular file")}
+	}
+	return OpenFile(name, O_WRONLY, 0)
+}
@@ -0,0 +1,17 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build aix || darwin || dragonfly || freebsd || linux || netbsd || openbsd || solaris
+// +build aix darwin dragonfly freebsd linux netbsd openbsd solaris
+
+package os
+
+import "syscall"
+
+// openForOverwrite opens the named file for writing without following
+// a symbolic link. O_NONBLOCK keeps the open from blocking if the file
+// is a FIFO; the caller rejects anything but a regular file.
+func openForOverwrite(name string) (*File, error) {
+	return OpenFile(name, O_WRONLY|syscall.O_NOFOLLOW|syscall.O_NONBLOCK, 0)
+}
@@ -0,0 +1,34 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+// SendFD sends the open file fd over f, which must be a connected
+// Unix-domain socket such as one returned by Socketpair, using an
+// SCM_RIGHTS control message. The receiving process obtains its own
+// descriptor for the same open file by calling RecvFD; fd itself remains
+// open and owned by the caller.
+//
+// On Windows, Plan 9 and js/wasm, SendFD returns an error wrapping
+// ErrUnsupported.
+func (f *File) SendFD(fd *File) error {
+	if err := f.checkValid("sendfd"); err != nil {
+		return err
+	}
+	return f.sendFD(fd)
+}
+
+// RecvFD receives a file sent by SendFD over f, which must be a connected
+// Unix-domain socket. The returned File is owned by the caller, who is
+// responsible for closing it. If the peer has closed its end, RecvFD
+// returns an error wrapping io.EOF.
+//
+// On Windows, Plan 9 and js/wasm, RecvFD returns an error wrapping
+// ErrUnsupported.
+func (f *File) RecvFD() (*File, error) {
+	if err := f.checkValid("recvfd"); err != nil {
+		return nil, err
+	}
+	return f.recvFD()
+}
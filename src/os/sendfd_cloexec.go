@@ -0,0 +1,14 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build dragonfly || freebsd || linux || netbsd || openbsd
+// +build dragonfly freebsd linux netbsd openbsd
+
+package os
+
+import "internal/syscall/unix"
+
+// recvmsgFlags is passed to recvmsg by recvFD. The kernel marks the
+// received file descriptors close-on-exec as it installs them.
+const recvmsgFlags = unix.MSG_CMSG_CLOEXEC
@@ -0,0 +1,13 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build aix || darwin || solaris
+// +build aix darwin solaris
+
+package os
+
+// recvmsgFlags is passed to recvmsg by recvFD. These systems cannot
+// mark the received file descriptors close-on-exec as they are
+// installed, so recvFD holds syscall.ForkLock until it has done so.
+const recvmsgFlags = 0
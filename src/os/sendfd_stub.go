@@ -0,0 +1,16 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build (js && wasm) || plan9 || windows
+// +build js,wasm plan9 windows
+
+package os
+
+func (f *File) sendFD(fd *File) error {
+	return f.wrapErr("sendfd", ErrUnsupported)
+}
+
+func (f *File) recvFD() (*File, error) {
+	return nil, f.wrapErr("recvfd", ErrUnsupported)
+}
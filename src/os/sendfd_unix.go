@@ -0,0 +1,103 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build aix || darwin || dragonfly || freebsd || linux || netbsd || openbsd || solaris
+// +build aix darwin dragonfly freebsd linux netbsd openbsd solaris
+
+package os
+
+import (
+	"errors"
+	"internal/itoa"
+	"io"
+	"runtime"
+	"syscall"
+)
+
+func (f *File) sendFD(fd *File) error {
+	if err := fd.checkValid("sendfd"); err != nil {
+		return err
+	}
+	var werr error
+	err := fd.pfd.RawControl(func(sysfd uintptr) {
+		// At least one byte of ordinary data must accompany
+		// the control message.
+		_, _, werr = f.pfd.WriteMsg([]byte{0}, syscall.UnixRights(int(sysfd)), nil)
+	})
+	runtime.KeepAlive(fd)
+	if err == nil {
+		err = werr
+	}
+	return f.wrapErr("sendfd", err)
+}
+
+func (f *File) recvFD() (*File, error) {
+	var b [1]byte
+	oob := make([]byte, syscall.CmsgSpace(4))
+	var n, oobn, flags int
+	var fds []int
+	var rerr error
+	err := f.pfd.RawRead(func(sysfd uintptr) bool {
+		if recvmsgFlags == 0 {
+			// See ../syscall/exec.go for description of lock.
+			syscall.ForkLock.RLock()
+			defer syscall.ForkLock.RUnlock()
+		}
+		for {
+			n, oobn, flags, _, rerr = syscall.Recvmsg(int(sysfd), b[:], oob, recvmsgFlags)
+			if rerr != syscall.EINTR {
+				break
+			}
+		}
+		if rerr == syscall.EAGAIN {
+			return false
+		}
+		if rerr == nil {
+			fds, rerr = parseRights(oob[:oobn])
+		}
+		if recvmsgFlags == 0 {
+			for _, fd := range fds {
+				syscall.CloseOnExec(fd)
+			}
+		}
+		return true
+	})
+	runtime.KeepAlive(f)
+	if err == nil {
+		err = rerr
+	}
+	if err != nil {
+		for _, fd := range fds {
+			syscall.Close(fd)
+		}
+		return nil, f.wrapErr("recvfd", err)
+	}
+	if n == 0 && oobn == 0 {
+		return nil, f.wrapErr("recvfd", io.EOF)
+	}
+	if flags&syscall.MSG_CTRUNC != 0 || len(fds) != 1 {
+		for _, fd := range fds {
+			syscall.Close(fd)
+		}
+		return nil, f.wrapErr("recvfd", errors.New("did not receive exactly one file descriptor"))
+	}
+	return NewFile(uintptr(fds[0]), "/dev/fd/"+itoa.Itoa(fds[0])), nil
+}
+
+// parseRights returns the file descriptors carried by the socket
+// control messages in oob.
+func parseRights(oob []byte) ([]int, error) {
+	msgs, err := syscall.ParseSocketControlMessage(oob)
+	if err != nil {
+		return nil, err
+	}
+	var fds []int
+	for i := range msgs {
+		rights, err := syscall.ParseUnixRights(&msgs[i])
+		if err == nil {
+			fds = append(fds, rights...)
+		}
+	}
+	return fds, nil
+}
@@ -0,0 +1,29 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+// Setsid creates a new session with the calling process as its leader
+// and returns the session ID, which is the process ID. The process
+// becomes the leader of a new process group in the session and has no
+// controlling terminal, which is the key step in detaching a daemon
+// from the terminal that started it.
+//
+// Setsid fails with EPERM if the calling process is already a process
+// group leader. A process started from an interactive shell usually is,
+// so the traditional daemonization sequence first starts a copy of the
+// program, using StartProcess, and calls Setsid in the child, which is
+// not a group leader. Most services should not daemonize themselves at
+// all but run in the foreground under a service manager such as
+// systemd, which handles sessions, logging and restarts.
+//
+// On Windows, Plan 9, AIX and js/wasm, Setsid returns an error
+// wrapping ErrUnsupported. Any error is of type *SyscallError.
+func Setsid() (sid int, err error) {
+	sid, err = setsid()
+	if err != nil {
+		return -1, NewSyscallError("setsid", err)
+	}
+	return sid, nil
+}
@@ -0,0 +1,12 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build aix || js || plan9 || windows
+// +build aix js plan9 windows
+
+package os
+
+func setsid() (int, error) {
+	return -1, ErrUnsupported
+}
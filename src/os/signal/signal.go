@@ -332,3 +332,42 @@ func (c *signalCtx) String() string {
 	buf = append(buf, ')')
 	return string(buf)
 }
+
+// SignalPipe arranges for the listed signals, or all incoming signals if
+// none are listed, to be written to a pipe, and returns the read end of
+// the pipe and a function that undoes the arrangement. Each signal that
+// arrives is written as a single byte, its signal number on Unix
+// systems, so the read end becomes readable whenever a signal is
+// pending. This suits programs that wait for events on file descriptors
+// rather than on channels.
+//
+// On Unix systems the signal handler itself writes to the pipe, so no
+// goroutine needs to run for the read end to become readable. If the
+// pipe is full, signals that arrive are dropped until the reader catches
+// up. At most 16 signal pipes can be in use at once. On other systems
+// the signals are delivered, as by Notify, to a channel that buffers 32
+// signals, and a goroutine copies them from the channel to the pipe.
+//
+// The returned function stops the writing of signals, as Stop does, and
+// closes both ends of the pipe. It should be called exactly once.
+func SignalPipe(sig ...os.Signal) (*os.File, func() error, error) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		return nil, nil, err
+	}
+	stop, err := relayToPipe(w, sig)
+	if err != nil {
+		r.Close()
+		w.Close()
+		return nil, nil, err
+	}
+	cleanup := func() error {
+		stop()
+		werr := w.Close()
+		if err := r.Close(); err != nil {
+			return err
+		}
+		return werr
+	}
+	return r, cleanup, nil
+}
@@ -47,6 +47,14 @@ func signum(sig os.Signal) int {
 	}
 }
 
+// pipeByte returns the byte that SignalPipe writes for sig. Looking up
+// the number of a note may add it to sigtab, so handlers must be held.
+func pipeByte(sig os.Signal) byte {
+	handlers.Lock()
+	defer handlers.Unlock()
+	return byte(signum(sig))
+}
+
 func enableSignal(sig int) {
 	signal_enable(uint32(sig))
 }
@@ -854,3 +854,53 @@ func TestNotifyContextStringer(t *testing.T) {
 		t.Errorf("c.String() = %q, want %q", got, want)
 	}
 }
+
+func TestSignalPipe(t *testing.T) {
+	r, cleanup, err := SignalPipe(syscall.SIGWINCH)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 2; i++ {
+		syscall.Kill(syscall.Getpid(), syscall.SIGWINCH)
+		r.SetReadDeadline(time.Now().Add(settleTime))
+		var b [1]byte
+		if _, err := r.Read(b[:]); err != nil {
+			t.Fatalf("reading signal pipe: %v", err)
+		}
+		if got := syscall.Signal(b[0]); got != syscall.SIGWINCH {
+			t.Errorf("signal pipe byte = %v, want %v", got, syscall.SIGWINCH)
+		}
+	}
+
+	if err := cleanup(); err != nil {
+		t.Errorf("cleanup: %v", err)
+	}
+	if _, err := r.Read(make([]byte, 1)); err == nil {
+		t.Error("read from signal pipe succeeded after cleanup")
+	}
+}
+
+func TestSignalPipeMultiple(t *testing.T) {
+	var rs [2]*os.File
+	for i := range rs {
+		r, cleanup, err := SignalPipe(syscall.SIGWINCH)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer cleanup()
+		rs[i] = r
+	}
+
+	syscall.Kill(syscall.Getpid(), syscall.SIGWINCH)
+	for i, r := range rs {
+		r.SetReadDeadline(time.Now().Add(settleTime))
+		var b [1]byte
+		if _, err := r.Read(b[:]); err != nil {
+			t.Fatalf("reading signal pipe %d: %v", i, err)
+		}
+		if got := syscall.Signal(b[0]); got != syscall.SIGWINCH {
+			t.Errorf("signal pipe %d byte = %v, want %v", i, got, syscall.SIGWINCH)
+		}
+	}
+}
@@ -46,6 +46,11 @@ func signum(sig os.Signal) int {
 	}
 }
 
+// pipeByte returns the byte that SignalPipe writes for sig.
+func pipeByte(sig os.Signal) byte {
+	return byte(signum(sig))
+}
+
 func enableSignal(sig int) {
 	signal_enable(uint32(sig))
 }
@@ -0,0 +1,34 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build (js && wasm) || plan9 || windows
+// +build js,wasm plan9 windows
+
+package signal
+
+import "os"
+
+// relayToPipe arranges for the signals to be written to w, and returns
+// a function that undoes the arrangement. These systems do not deliver
+// signals to a handler that can write to a pipe, so the signals are
+// relayed by a goroutine.
+func relayToPipe(w *os.File, sig []os.Signal) (func(), error) {
+	c := make(chan os.Signal, 32)
+	Notify(c, sig...)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		var b [1]byte
+		for s := range c {
+			b[0] = pipeByte(s)
+			// A failed write means the pipe is being closed.
+			w.Write(b[:])
+		}
+	}()
+	return func() {
+		Stop(c)
+		close(c)
+		<-done
+	}, nil
+}
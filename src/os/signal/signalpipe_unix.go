@@ -0,0 +1,70 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build aix || darwin || dragonfly || freebsd || linux || netbsd || openbsd || solaris
+// +build aix darwin dragonfly freebsd linux netbsd openbsd solaris
+
+package signal
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+// Defined by the runtime package.
+func signal_pipe_add(fd uintptr) int
+func signal_pipe_enable(slot int, sig uint32)
+func signal_pipe_remove(slot int)
+
+// relayToPipe arranges for the signal handler to write the signals to w,
+// and returns a function that undoes the arrangement.
+func relayToPipe(w *os.File, sig []os.Signal) (func(), error) {
+	rc, err := w.SyscallConn()
+	if err != nil {
+		return nil, err
+	}
+	var fd uintptr
+	var serr error
+	if err := rc.Control(func(s uintptr) {
+		fd = s
+		// The signal handler must never block writing to the pipe.
+		serr = syscall.SetNonblock(int(s), true)
+	}); err != nil {
+		return nil, err
+	}
+	if serr != nil {
+		return nil, os.NewSyscallError("setnonblock", serr)
+	}
+
+	handlers.Lock()
+	slot := signal_pipe_add(fd)
+	if slot < 0 {
+		handlers.Unlock()
+		return nil, errors.New("os/signal: too many signal pipes")
+	}
+	if len(sig) == 0 {
+		for n := 0; n < numSig; n++ {
+			signal_pipe_enable(slot, uint32(n))
+		}
+	} else {
+		for _, s := range sig {
+			if n := signum(s); n >= 0 {
+				signal_pipe_enable(slot, uint32(n))
+			}
+		}
+	}
+	handlers.Unlock()
+
+	// Nothing receives from c. Registering it keeps the signals
+	// enabled for as long as the pipe is.
+	c := make(chan os.Signal)
+	Notify(c, sig...)
+	return func() {
+		Stop(c)
+		handlers.Lock()
+		signal_pipe_remove(slot)
+		handlers.Unlock()
+	}, nil
+}
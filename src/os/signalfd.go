@@ -0,0 +1,45 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+// SignalInfo describes a signal read from a signalfd by ReadSignal.
+type SignalInfo struct {
+	Signal Signal // the signal received
+	Pid    int    // process ID of the sender, if sent by a process
+	Uid    int    // real user ID of the sender, if sent by a process
+}
+
+// Signalfd returns a file from which the listed signals can be read
+// using ReadSignal. The file works with the runtime poller, so reads
+// block only the calling goroutine and honor SetReadDeadline, which
+// lets a program wait for signals alongside other file descriptors.
+//
+// A signalfd only receives signals that are blocked in every thread,
+// so Signalfd blocks the listed signals in all threads of the process,
+// including threads started later, before creating the file. They stay
+// blocked for the life of the process, even after the file is closed:
+// while blocked they are neither handled by the Go runtime, so that
+// os/signal.Notify does not see them even if it is called for them
+// afterwards, nor do they take their default action, so that SIGTERM,
+// for example, no longer ends the program.
+// Child processes do not inherit the blocking. Signals the runtime
+// depends on, such as SIGSEGV and SIGPROF, cannot be blocked; for
+// those, and in programs that use cgo, Signalfd fails with EINVAL.
+//
+// Signalfd is only supported on Linux. On other systems it returns an
+// error wrapping ErrUnsupported; os/signal.SignalPipe is a portable
+// alternative.
+func Signalfd(sigs ...Signal) (*File, error) {
+	return signalfd(sigs)
+}
+
+// ReadSignal reads the next signal from f, which must have been
+// returned by Signalfd.
+func (f *File) ReadSignal() (SignalInfo, error) {
+	if err := f.checkValid("read"); err != nil {
+		return SignalInfo{}, err
+	}
+	return f.readSignal()
+}
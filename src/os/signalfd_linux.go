@@ -0,0 +1,67 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+import (
+	"internal/syscall/unix"
+	"syscall"
+	"unsafe"
+)
+
+// runtime_sigblock is implemented in package runtime.
+func runtime_sigblock(sigs []uint32) bool
+
+// signalfdSiginfo is struct signalfd_siginfo, one of which is read
+// from a signalfd for each signal.
+type signalfdSiginfo struct {
+	signo uint32
+	errno int32
+	code  int32
+	pid   uint32
+	uid   uint32
+	_     [108]byte
+}
+
+func signalfd(sigs []Signal) (*File, error) {
+	if len(sigs) == 0 {
+		return nil, NewSyscallError("signalfd", syscall.EINVAL)
+	}
+	nums := make([]int, len(sigs))
+	block := make([]uint32, len(sigs))
+	for i, sig := range sigs {
+		s, ok := sig.(syscall.Signal)
+		if !ok || s <= 0 {
+			return nil, NewSyscallError("signalfd", syscall.EINVAL)
+		}
+		nums[i] = int(s)
+		block[i] = uint32(s)
+	}
+	if !runtime_sigblock(block) {
+		return nil, NewSyscallError("sigprocmask", syscall.EINVAL)
+	}
+	fd, err := unix.Signalfd(nums, syscall.O_NONBLOCK|syscall.O_CLOEXEC)
+	if err != nil {
+		return nil, NewSyscallError("signalfd", err)
+	}
+	return newFile(uintptr(fd), "signalfd", kindNonBlock), nil
+}
+
+func (f *File) readSignal() (SignalInfo, error) {
+	var si signalfdSiginfo
+	b := (*[unsafe.Sizeof(si)]byte)(unsafe.Pointer(&si))[:]
+	n, err := f.read(b)
+	if err != nil {
+		return SignalInfo{}, f.wrapErr("read", err)
+	}
+	if n != len(b) {
+		// A signalfd returns only whole structures.
+		return SignalInfo{}, f.wrapErr("read", syscall.EINVAL)
+	}
+	return SignalInfo{
+		Signal: syscall.Signal(si.signo),
+		Pid:    int(si.pid),
+		Uid:    int(si.uid),
+	}, nil
+}
@@ -0,0 +1,64 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os_test
+
+import (
+	"fmt"
+	"internal/testenv"
+	. "os"
+	osexec "os/exec"
+	"os/signal"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// TestSignalfdNotify checks that signals blocked by Signalfd stay
+// blocked, and so are read from the signalfd rather than delivered to
+// signal.Notify, even when Notify and Stop are called afterwards.
+func TestSignalfdNotify(t *testing.T) {
+	if Getenv("GO_WANT_HELPER_PROCESS") == "1" {
+		f, err := Signalfd(syscall.SIGUSR1)
+		if err != nil {
+			fmt.Print(err)
+			Exit(0)
+		}
+		c := make(chan Signal, 10)
+		signal.Notify(c, syscall.SIGUSR1)
+		other := make(chan Signal, 1)
+		signal.Notify(other, syscall.SIGHUP)
+		signal.Stop(other)
+		for i := 0; i < 5; i++ {
+			syscall.Kill(Getpid(), syscall.SIGUSR1)
+			// Give a thread that wrongly has the signal unblocked
+			// time to receive it.
+			time.Sleep(time.Millisecond)
+			f.SetReadDeadline(time.Now().Add(time.Second))
+			if _, err := f.ReadSignal(); err != nil {
+				fmt.Print("ReadSignal: ", err)
+				Exit(0)
+			}
+		}
+		select {
+		case sig := <-c:
+			fmt.Print("Notify received ", sig)
+		case <-time.After(10 * time.Millisecond):
+			fmt.Print("ok")
+		}
+		Exit(0)
+	}
+
+	// Signalfd blocks the signal for good, so use a child process.
+	testenv.MustHaveExec(t)
+	cmd := osexec.Command(Args[0], "-test.run=^TestSignalfdNotify$")
+	cmd.Env = append(Environ(), "GO_WANT_HELPER_PROCESS=1")
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("helper process failed: %v\n%s", err, out)
+	}
+	if string(out) != "ok" {
+		t.Errorf("helper process: %s", out)
+	}
+}
@@ -0,0 +1,16 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !linux
+// +build !linux
+
+package os
+
+func signalfd(sigs []Signal) (*File, error) {
+	return nil, NewSyscallError("signalfd", ErrUnsupported)
+}
+
+func (f *File) readSignal() (SignalInfo, error) {
+	return SignalInfo{}, f.wrapErr("read", ErrUnsupported)
+}
@@ -0,0 +1,17 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+// Socketpair returns a connected pair of Files. Unlike Pipe, both ends
+// are bidirectional: bytes written to a can be read from b and bytes
+// written to b can be read from a. On Unix systems the pair is a
+// Unix-domain stream socket pair created by socketpair(2); either end
+// may be passed to a child process, for example in exec.Cmd.ExtraFiles.
+//
+// On Windows, Plan 9 and js/wasm, Socketpair returns an error wrapping
+// ErrUnsupported.
+func Socketpair() (a *File, b *File, err error) {
+	return socketpair()
+}
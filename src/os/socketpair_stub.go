@@ -0,0 +1,12 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build (js && wasm) || plan9 || windows
+// +build js,wasm plan9 windows
+
+package os
+
+func socketpair() (a *File, b *File, err error) {
+	return nil, nil, NewSyscallError("socketpair", ErrUnsupported)
+}
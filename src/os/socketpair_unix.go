@@ -0,0 +1,25 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build aix || darwin || dragonfly || freebsd || linux || netbsd || openbsd || solaris
+// +build aix darwin dragonfly freebsd linux netbsd openbsd solaris
+
+package os
+
+import "syscall"
+
+func socketpair() (a *File, b *File, err error) {
+	// See ../syscall/exec.go for description of lock.
+	syscall.ForkLock.RLock()
+	fd, e := syscall.Socketpair(syscall.AF_UNIX, syscall.SOCK_STREAM, 0)
+	if e != nil {
+		syscall.ForkLock.RUnlock()
+		return nil, nil, NewSyscallError("socketpair", e)
+	}
+	syscall.CloseOnExec(fd[0])
+	syscall.CloseOnExec(fd[1])
+	syscall.ForkLock.RUnlock()
+
+	return newFile(uintptr(fd[0]), "socketpair:0", kindPipe), newFile(uintptr(fd[1]), "socketpair:1", kindPipe), nil
+}
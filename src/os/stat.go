@@ -4,7 +4,11 @@
 
 package os
 
-import "internal/testlog"
+import (
+	"internal/testlog"
+	"io"
+	"sync"
+)
 
 // Stat returns a FileInfo describing the named file.
 // If there is an error, it will be of type *PathError.
@@ -21,3 +25,65 @@ func Lstat(name string) (FileInfo, error) {
 	testlog.Stat(name)
 	return lstatNolog(name)
 }
+
+// StatMany calls Stat for each of names, up to concurrency at a time,
+// which on high-latency storage is much faster than calling Stat for
+// each in turn. The results are returned in slices matching names, so
+// that infos[i] and errs[i] are the results of Stat(names[i]). A
+// concurrency less than 1 is treated as 1.
+func StatMany(names []string, concurrency int) (infos []FileInfo, errs []error) {
+	infos = make([]FileInfo, len(names))
+	errs = make([]error, len(names))
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	if concurrency > len(names) {
+		concurrency = len(names)
+	}
+	next := make(chan int)
+	var wg sync.WaitGroup
+	wg.Add(concurrency)
+	for i := 0; i < concurrency; i++ {
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				infos[i], errs[i] = Stat(names[i])
+			}
+		}()
+	}
+	for i := range names {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+	return infos, errs
+}
+
+// IsEmpty reports whether the named file is empty. A regular file is
+// empty if its size is zero; a directory is empty if it has no entries,
+// which IsEmpty determines by reading at most one entry, however large
+// the directory. IsEmpty follows symbolic links. For other kinds of
+// file, whose size does not reliably reflect their contents, IsEmpty
+// returns an error wrapping ErrUnsupported.
+func IsEmpty(name string) (bool, error) {
+	fi, err := Stat(name)
+	if err != nil {
+		return false, err
+	}
+	switch {
+	case fi.Mode().IsRegular():
+		return fi.Size() == 0, nil
+	case fi.IsDir():
+		f, err := Open(name)
+		if err != nil {
+			return false, err
+		}
+		defer f.Close()
+		_, err = f.Readdirnames(1)
+		if err == io.EOF {
+			return true, nil
+		}
+		return false, err
+	}
+	return false, &PathError{Op: "isempty", Path: name, Err: ErrUnsupported}
+}
@@ -45,7 +45,7 @@ func stTimespecToTime(ts syscall.StTimespec_t) time.Time {
 	return time.Unix(int64(ts.Sec), int64(ts.Nsec))
 }
 
-// For testing.
+// atime returns the access time recorded in fi.
 func atime(fi FileInfo) time.Time {
 	return stTimespecToTime(fi.Sys().(*syscall.Stat_t).Atim)
 }
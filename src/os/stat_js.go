@@ -48,7 +48,7 @@ func timespecToTime(sec, nsec int64) time.Time {
 	return time.Unix(sec, nsec)
 }
 
-// For testing.
+// atime returns the access time recorded in fi.
 func atime(fi FileInfo) time.Time {
 	st := fi.Sys().(*syscall.Stat_t)
 	return timespecToTime(st.Atime, st.AtimeNsec)
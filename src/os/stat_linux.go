@@ -45,7 +45,7 @@ func timespecToTime(ts syscall.Timespec) time.Time {
 	return time.Unix(int64(ts.Sec), int64(ts.Nsec))
 }
 
-// For testing.
+// atime returns the access time recorded in fi.
 func atime(fi FileInfo) time.Time {
 	return timespecToTime(fi.Sys().(*syscall.Stat_t).Atim)
 }
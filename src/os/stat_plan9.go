@@ -104,7 +104,22 @@ func lstatNolog(name string) (FileInfo, error) {
 	return statNolog(name)
 }
 
-// For testing.
+// atime returns the access time recorded in fi.
 func atime(fi FileInfo) time.Time {
 	return time.Unix(int64(fi.Sys().(*syscall.Dir).Atime), 0)
 }
+
+// owner returns the user and group IDs recorded in fi.
+// Plan 9 files are owned by name, not number, so ok is always false.
+func owner(fi FileInfo) (uid, gid int, ok bool) {
+	return -1, -1, false
+}
+
+// volumeID returns an identifier for the file system holding fi.
+func volumeID(fi FileInfo) (uint64, bool) {
+	d, ok := fi.Sys().(*syscall.Dir)
+	if !ok {
+		return 0, false
+	}
+	return uint64(d.Type)<<32 | uint64(d.Dev), true
+}
@@ -5,6 +5,8 @@
 package os_test
 
 import (
+	"errors"
+	"fmt"
 	"internal/testenv"
 	"io/fs"
 	"os"
@@ -290,3 +292,78 @@ func TestSymlinkWithTrailingSlash(t *testing.T) {
 		t.Errorf("os.Stat(%q) and os.Stat(%q) are not the same file", dir, dirlinkWithSlash)
 	}
 }
+
+func TestStatMany(t *testing.T) {
+	dir := t.TempDir()
+	var names []string
+	for i := 0; i < 20; i++ {
+		name := filepath.Join(dir, fmt.Sprintf("f%d", i))
+		if i%3 != 0 {
+			if err := os.WriteFile(name, make([]byte, i), 0666); err != nil {
+				t.Fatal(err)
+			}
+		}
+		names = append(names, name)
+	}
+	for _, concurrency := range []int{0, 1, 4, 50} {
+		infos, errs := os.StatMany(names, concurrency)
+		if len(infos) != len(names) || len(errs) != len(names) {
+			t.Fatalf("StatMany returned %d infos, %d errors for %d names", len(infos), len(errs), len(names))
+		}
+		for i := range names {
+			if i%3 == 0 {
+				if !os.IsNotExist(errs[i]) || infos[i] != nil {
+					t.Errorf("concurrency %d: %s: got %v, %v; want not exist", concurrency, names[i], infos[i], errs[i])
+				}
+				continue
+			}
+			if errs[i] != nil || infos[i].Size() != int64(i) {
+				t.Errorf("concurrency %d: %s: got %v, %v; want size %d", concurrency, names[i], infos[i], errs[i], i)
+			}
+		}
+	}
+	if infos, errs := os.StatMany(nil, 4); len(infos) != 0 || len(errs) != 0 {
+		t.Errorf("StatMany(nil) = %v, %v", infos, errs)
+	}
+}
+
+func TestIsEmpty(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "file")
+	sub := filepath.Join(dir, "sub")
+	check := func(name string, want bool) {
+		t.Helper()
+		empty, err := os.IsEmpty(name)
+		if err != nil || empty != want {
+			t.Errorf("IsEmpty(%s) = %v, %v; want %v, nil", name, empty, err, want)
+		}
+	}
+
+	if err := os.WriteFile(file, nil, 0666); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Mkdir(sub, 0777); err != nil {
+		t.Fatal(err)
+	}
+	check(file, true)
+	check(sub, true)
+
+	if err := os.WriteFile(file, []byte("x"), 0666); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(sub, "f"), nil, 0666); err != nil {
+		t.Fatal(err)
+	}
+	check(file, false)
+	check(sub, false)
+	check(dir, false)
+
+	if _, err := os.IsEmpty(filepath.Join(dir, "missing")); !os.IsNotExist(err) {
+		t.Errorf("IsEmpty of missing file: got %v, want not-exist error", err)
+	}
+	if runtime.GOOS == "linux" {
+		if _, err := os.IsEmpty("/dev/null"); !errors.Is(err, os.ErrUnsupported) {
+			t.Errorf("IsEmpty(/dev/null): got %v, want ErrUnsupported", err)
+		}
+	}
+}
@@ -51,3 +51,21 @@ func lstatNolog(name string) (FileInfo, error) {
 	fillFileStatFromSys(&fs, name)
 	return &fs, nil
 }
+
+// owner returns the user and group IDs recorded in fi.
+func owner(fi FileInfo) (uid, gid int, ok bool) {
+	st, ok := fi.Sys().(*syscall.Stat_t)
+	if !ok {
+		return -1, -1, false
+	}
+	return int(st.Uid), int(st.Gid), true
+}
+
+// volumeID returns an identifier for the file system holding fi.
+func volumeID(fi FileInfo) (uint64, bool) {
+	st, ok := fi.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 0, false
+	}
+	return uint64(st.Dev), true
+}
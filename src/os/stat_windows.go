@@ -114,3 +114,18 @@ func lstatNolog(name string) (FileInfo, error) {
 	attrs |= syscall.FILE_FLAG_OPEN_REPARSE_POINT
 	return stat("Lstat", name, attrs)
 }
+
+// owner returns the user and group IDs recorded in fi.
+// Windows files have no numeric owner, so ok is always false.
+func owner(fi FileInfo) (uid, gid int, ok bool) {
+	return -1, -1, false
+}
+
+// volumeID returns an identifier for the file system holding fi.
+func volumeID(fi FileInfo) (uint64, bool) {
+	fs, ok := fi.(*fileStat)
+	if !ok || fs.loadFileId() != nil {
+		return 0, false
+	}
+	return uint64(fs.vol), true
+}
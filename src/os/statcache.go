@@ -0,0 +1,117 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+import (
+	"sync"
+	"time"
+)
+
+// A StatCache remembers the results of Stat for a limited time, to
+// save system calls in programs that examine the same files repeatedly,
+// such as build tools checking modification times.
+//
+// Successful results are kept for the time to live given to
+// NewStatCache. Results reporting that a file does not exist are kept
+// too, so that repeated checks for a missing file are also cheap, but
+// for a shorter time, set by SetNotExistTTL. Other errors are not
+// cached.
+//
+// The cache does not observe changes to the file system. A program
+// that changes a file must call Invalidate for it, or Stat will go on
+// reporting the old information until the entry expires. In particular,
+// a file created after its absence was cached is not seen until then.
+// Changes made by other processes are seen only after expiry.
+//
+// Expired entries are discarded whenever the cache has grown to twice
+// its size after the previous sweep, so the cache holds at most about
+// twice as many entries as there are names statted within the time to
+// live, however many distinct names are statted in all.
+//
+// A StatCache is safe for concurrent use.
+type StatCache struct {
+	mu          sync.Mutex
+	ttl         time.Duration
+	notExistTTL time.Duration
+	m           map[string]statCacheEntry
+	sweepAt     int // size of m at which to discard expired entries
+}
+
+// minStatCacheSweep is the smallest size of a StatCache that is swept.
+const minStatCacheSweep = 64
+
+type statCacheEntry struct {
+	fi      FileInfo
+	err     error
+	expires time.Time
+}
+
+// NewStatCache returns a StatCache that keeps successful results for
+// ttl and not-exist results for a quarter of ttl.
+func NewStatCache(ttl time.Duration) *StatCache {
+	return &StatCache{
+		ttl:         ttl,
+		notExistTTL: ttl / 4,
+		m:           make(map[string]statCacheEntry),
+		sweepAt:     minStatCacheSweep,
+	}
+}
+
+// SetNotExistTTL sets the time for which c keeps results reporting
+// that a file does not exist. It applies to results cached afterward.
+func (c *StatCache) SetNotExistTTL(ttl time.Duration) {
+	c.mu.Lock()
+	c.notExistTTL = ttl
+	c.mu.Unlock()
+}
+
+// Stat returns the result of Stat(name), from the cache if an
+// unexpired result is held there.
+func (c *StatCache) Stat(name string) (FileInfo, error) {
+	now := time.Now()
+	c.mu.Lock()
+	e, ok := c.m[name]
+	c.mu.Unlock()
+	if ok && now.Before(e.expires) {
+		return e.fi, e.err
+	}
+	fi, err := Stat(name)
+	c.mu.Lock()
+	if len(c.m) >= c.sweepAt {
+		c.sweep(now)
+	}
+	switch {
+	case err == nil:
+		c.m[name] = statCacheEntry{fi: fi, expires: now.Add(c.ttl)}
+	case IsNotExist(err):
+		c.m[name] = statCacheEntry{err: err, expires: now.Add(c.notExistTTL)}
+	default:
+		delete(c.m, name)
+	}
+	c.mu.Unlock()
+	return fi, err
+}
+
+// sweep discards the expired entries of c, and sets the size at which
+// to do so next. c.mu must be held.
+func (c *StatCache) sweep(now time.Time) {
+	for name, e := range c.m {
+		if !now.Before(e.expires) {
+			delete(c.m, name)
+		}
+	}
+	c.sweepAt = 2 * len(c.m)
+	if c.sweepAt < minStatCacheSweep {
+		c.sweepAt = minStatCacheSweep
+	}
+}
+
+// Invalidate discards any cached result for name, so that the next
+// Stat of name consults the file system.
+func (c *StatCache) Invalidate(name string) {
+	c.mu.Lock()
+	delete(c.m, name)
+	c.mu.Unlock()
+}
@@ -0,0 +1,302 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+import (
+	"io"
+	"io/fs"
+)
+
+// SyncOptions controls the behavior of SyncDir.
+type SyncOptions struct {
+	// Checksum makes SyncDir compare the contents of files that have
+	// the same size, rather than their modification times, to decide
+	// whether they differ.
+	Checksum bool
+
+	// Delete makes SyncDir remove files and directories in the
+	// destination that are not present in the source.
+	Delete bool
+}
+
+// SyncStats reports the work done by SyncDir.
+type SyncStats struct {
+	Copied  int   // files and symbolic links copied
+	Skipped int   // files and symbolic links already up to date
+	Deleted int   // entries removed from the destination
+	Bytes   int64 // bytes of file data copied
+}
+
+// SyncDir makes the directory tree dst a copy of the directory tree
+// src, copying only the files that are missing from dst or differ from
+// their counterparts in src. Files are considered to differ if their
+// sizes or modification times differ, or, if opts.Checksum is set,
+// their sizes or contents. Copied files take the permission bits and
+// modification time of the source, so that unchanged files are
+// skipped by the next SyncDir.
+//
+// Symbolic links in src are copied as links, not followed. Files in
+// dst are never written through a symbolic link: a link in dst where
+// src has a directory or a file is replaced. Files other than regular
+// files, directories and symbolic links are ignored.
+//
+// If opts.Delete is set, entries in dst with no counterpart in src are
+// removed. Removal never reaches outside dst, and never removes src or
+// a directory containing it, so src may lie within dst.
+//
+// SyncDir stops at the first error, returning it with the statistics
+// of the work done so far.
+func SyncDir(dst, src string, opts SyncOptions) (SyncStats, error) {
+	s := &dirSyncer{opts: opts}
+	fi, err := Stat(src)
+	if err != nil {
+		return s.stats, err
+	}
+	if !fi.IsDir() {
+		return s.stats, &PathError{Op: "syncdir", Path: src, Err: errNotDirectory}
+	}
+	if err := Mkdir(dst, fi.Mode().Perm()); err != nil && !IsExist(err) {
+		return s.stats, err
+	}
+	if s.dstRoot, err = Stat(dst); err != nil {
+		return s.stats, err
+	}
+	if !s.dstRoot.IsDir() {
+		return s.stats, &PathError{Op: "syncdir", Path: dst, Err: errNotDirectory}
+	}
+	if opts.Delete {
+		if s.srcDirs, err = ancestors(src, fi); err != nil {
+			return s.stats, err
+		}
+	}
+	err = s.syncDir(dst, src)
+	return s.stats, err
+}
+
+type dirSyncer struct {
+	opts    SyncOptions
+	stats   SyncStats
+	dstRoot fs.FileInfo   // to avoid copying dst into itself
+	srcDirs []fs.FileInfo // src and its ancestors, which are never deleted
+}
+
+// ancestors returns the directory dir, whose FileInfo is fi, and the
+// directories containing it up to the root.
+func ancestors(dir string, fi fs.FileInfo) ([]fs.FileInfo, error) {
+	dirs := []fs.FileInfo{fi}
+	for {
+		dir = joinPath(dir, "..")
+		parent, err := Stat(dir)
+		if err != nil {
+			return nil, err
+		}
+		if SameFile(parent, dirs[len(dirs)-1]) {
+			return dirs, nil
+		}
+		dirs = append(dirs, parent)
+	}
+}
+
+// isSrcDir reports whether fi is src or one of its ancestors.
+func (s *dirSyncer) isSrcDir(fi fs.FileInfo) bool {
+	for _, d := range s.srcDirs {
+		if SameFile(fi, d) {
+			return true
+		}
+	}
+	return false
+}
+
+// syncDir syncs the contents of the existing directory dst with src.
+func (s *dirSyncer) syncDir(dst, src string) error {
+	entries, err := ReadDir(src)
+	if err != nil {
+		return err
+	}
+	inSrc := make(map[string]bool, len(entries))
+	for _, e := range entries {
+		inSrc[e.Name()] = true
+		d, sp := joinPath(dst, e.Name()), joinPath(src, e.Name())
+		switch e.Type() {
+		case ModeDir:
+			fi, err := e.Info()
+			if err != nil {
+				return err
+			}
+			if SameFile(fi, s.dstRoot) {
+				continue
+			}
+			if err := s.makeDir(d, fi.Mode().Perm()); err != nil {
+				return err
+			}
+			if err := s.syncDir(d, sp); err != nil {
+				return err
+			}
+		case ModeSymlink:
+			if err := s.syncSymlink(d, sp); err != nil {
+				return err
+			}
+		case 0:
+			if err := s.syncFile(d, sp); err != nil {
+				return err
+			}
+		}
+	}
+	if !s.opts.Delete {
+		return nil
+	}
+	entries, err = ReadDir(dst)
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if inSrc[e.Name()] {
+			continue
+		}
+		name := joinPath(dst, e.Name())
+		// Do not remove src if it lies within dst.
+		fi, err := Lstat(name)
+		if err != nil {
+			return err
+		}
+		if fi.IsDir() && s.isSrcDir(fi) {
+			continue
+		}
+		// RemoveAll does not follow symbolic links,
+		// so this cannot remove anything outside dst.
+		if err := RemoveAll(name); err != nil {
+			return err
+		}
+		s.stats.Deleted++
+	}
+	return nil
+}
+
+// makeDir ensures that name is a directory, replacing anything else
+// in its place, including a symbolic link to a directory.
+func (s *dirSyncer) makeDir(name string, perm FileMode) error {
+	fi, err := Lstat(name)
+	if err == nil && fi.IsDir() {
+		return nil
+	}
+	if err == nil {
+		if err := Remove(name); err != nil {
+			return err
+		}
+	} else if !IsNotExist(err) {
+		return err
+	}
+	return Mkdir(name, perm)
+}
+
+func (s *dirSyncer) syncSymlink(dst, src string) error {
+	target, err := Readlink(src)
+	if err != nil {
+		return err
+	}
+	if fi, err := Lstat(dst); err == nil {
+		if fi.Mode()&ModeSymlink != 0 {
+			if t, err := Readlink(dst); err == nil && t == target {
+				s.stats.Skipped++
+				return nil
+			}
+		}
+		if err := RemoveAll(dst); err != nil {
+			return err
+		}
+	}
+	if err := Symlink(target, dst); err != nil {
+		return err
+	}
+	s.stats.Copied++
+	return nil
+}
+
+func (s *dirSyncer) syncFile(dst, src string) error {
+	sfi, err := Lstat(src)
+	if err != nil {
+		return err
+	}
+	dfi, err := Lstat(dst)
+	switch {
+	case err == nil && dfi.Mode().IsRegular():
+		same, err := s.sameFile(dst, src, dfi, sfi)
+		if err != nil {
+			return err
+		}
+		if same {
+			s.stats.Skipped++
+			return nil
+		}
+		// Replace rather than overwrite the file, which may not be
+		// writable.
+		if err := Remove(dst); err != nil {
+			return err
+		}
+	case err == nil:
+		// Never write through a symbolic link, or over a directory.
+		if err := RemoveAll(dst); err != nil {
+			return err
+		}
+	case !IsNotExist(err):
+		return err
+	}
+	if err := CopyFileProgress(dst, src, nil, 0); err != nil {
+		return err
+	}
+	if err := Chmod(dst, sfi.Mode().Perm()); err != nil {
+		return err
+	}
+	if err := Chtimes(dst, sfi.ModTime(), sfi.ModTime()); err != nil {
+		return err
+	}
+	s.stats.Copied++
+	s.stats.Bytes += sfi.Size()
+	return nil
+}
+
+// sameFile reports whether the regular files dst and src are the same
+// for the purposes of SyncDir.
+func (s *dirSyncer) sameFile(dst, src string, dfi, sfi fs.FileInfo) (bool, error) {
+	if dfi.Size() != sfi.Size() {
+		return false, nil
+	}
+	if !s.opts.Checksum {
+		return dfi.ModTime().Equal(sfi.ModTime()), nil
+	}
+	return sameContents(dst, src)
+}
+
+// sameContents reports whether the files a and b hold the same bytes.
+func sameContents(a, b string) (bool, error) {
+	fa, err := Open(a)
+	if err != nil {
+		return false, err
+	}
+	defer fa.Close()
+	fb, err := Open(b)
+	if err != nil {
+		return false, err
+	}
+	defer fb.Close()
+	bufa := make([]byte, 32*1024)
+	bufb := make([]byte, len(bufa))
+	for {
+		na, erra := io.ReadFull(fa, bufa)
+		nb, errb := io.ReadFull(fb, bufb)
+		if na != nb || string(bufa[:na]) != string(bufb[:nb]) {
+			return false, nil
+		}
+		if erra == io.EOF || erra == io.ErrUnexpectedEOF {
+			return errb == io.EOF || errb == io.ErrUnexpectedEOF, nil
+		}
+		if erra != nil {
+			return false, erra
+		}
+		if errb != nil {
+			return false, errb
+		}
+	}
+}
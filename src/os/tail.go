@@ -0,0 +1,175 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+import (
+	"context"
+	"internal/bytealg"
+	"io"
+	"time"
+)
+
+// OpenTail opens the named file for reading, like Open, and seeks to
+// its end, so that reads return only data appended after the call.
+func OpenTail(name string) (*File, error) {
+	f, err := Open(name)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := f.Seek(0, io.SeekEnd); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return f, nil
+}
+
+// tailPollInterval is how often Tail checks the file for changes.
+var tailPollInterval = 250 * time.Millisecond
+
+// Tail follows the named file as tail -F does, sending each line
+// appended to it after the call on the returned channel. Each line
+// includes its terminating newline; an incomplete final line is held
+// back until it is completed.
+//
+// Tail polls the file for changes. If the file shrinks, it is assumed
+// to have been truncated and is read again from the start. If the name
+// comes to refer to a different file, as when a log is rotated, Tail
+// reads the rest of the old file and then follows the new one from its
+// start; an incomplete final line of the old file is sent as it is.
+//
+// The channel is closed when ctx is done or if reading the file fails.
+// The returned function waits for the channel to be closed and returns
+// the error that stopped Tail: ctx.Err() if ctx is done, or the error
+// from reading the file.
+func Tail(ctx context.Context, name string) (<-chan []byte, func() error, error) {
+	f, err := OpenTail(name)
+	if err != nil {
+		return nil, nil, err
+	}
+	c := make(chan []byte)
+	t := &tailer{ctx: ctx, f: f, c: c, buf: make([]byte, 32<<10), done: make(chan struct{})}
+	go t.run()
+	return c, t.wait, nil
+}
+
+// A tailer holds the state of a call to Tail.
+type tailer struct {
+	ctx     context.Context
+	f       *File
+	c       chan<- []byte
+	buf     []byte
+	partial []byte // incomplete line read so far
+
+	done chan struct{} // closed with c
+	err  error         // why Tail stopped; set before done is closed
+}
+
+func (t *tailer) wait() error {
+	<-t.done
+	return t.err
+}
+
+func (t *tailer) run() {
+	defer close(t.done)
+	defer close(t.c)
+	defer func() { t.f.Close() }()
+	t.err = t.follow()
+}
+
+// follow sends the lines appended to the file until ctx is done or
+// reading the file fails, and returns the error that stopped it.
+func (t *tailer) follow() error {
+	ticker := time.NewTicker(tailPollInterval)
+	defer ticker.Stop()
+	for {
+		if err := t.readAll(); err != nil {
+			return err
+		}
+		fi, err := t.f.Stat()
+		if err != nil {
+			return err
+		}
+		if pos, err := t.f.Seek(0, io.SeekCurrent); err == nil && fi.Size() < pos {
+			// Truncated: start again from the beginning.
+			if err := t.flush(); err != nil {
+				return err
+			}
+			if _, err := t.f.Seek(0, io.SeekStart); err != nil {
+				return err
+			}
+			continue
+		}
+		if nfi, err := Stat(t.f.name); err == nil && !SameFile(fi, nfi) {
+			// Rotated: finish the old file and switch to the new one.
+			// If the new file cannot be opened yet, keep trying.
+			if nf, err := Open(t.f.name); err == nil {
+				err := t.readAll()
+				if err == nil {
+					err = t.flush()
+				}
+				t.f.Close()
+				t.f = nf
+				if err != nil {
+					return err
+				}
+				continue
+			}
+		}
+		select {
+		case <-t.ctx.Done():
+			return t.ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// readAll reads t.f to its current end, sending the complete lines.
+func (t *tailer) readAll() error {
+	for {
+		n, err := t.f.Read(t.buf)
+		data := t.buf[:n]
+		for len(data) > 0 {
+			i := bytealg.IndexByte(data, '\n')
+			if i < 0 {
+				t.partial = append(t.partial, data...)
+				break
+			}
+			// Copy, as t.buf and t.partial are reused.
+			line := make([]byte, 0, len(t.partial)+i+1)
+			line = append(line, t.partial...)
+			line = append(line, data[:i+1]...)
+			t.partial = t.partial[:0]
+			if err := t.send(line); err != nil {
+				return err
+			}
+			data = data[i+1:]
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// flush sends any incomplete line.
+func (t *tailer) flush() error {
+	if len(t.partial) == 0 {
+		return nil
+	}
+	line := t.partial
+	t.partial = nil
+	return t.send(line)
+}
+
+func (t *tailer) send(line []byte) error {
+	select {
+	case t.c <- line:
+		return nil
+	case <-t.ctx.Done():
+		return t.ctx.Err()
+	}
+}
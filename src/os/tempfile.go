@@ -57,10 +57,8 @@ var errPatternHasSeparator = errors.New("pattern contains path separator")
 // prefixAndSuffix splits pattern by the last wildcard "*", if applicable,
 // returning prefix as the part before "*" and suffix as the part after "*".
 func prefixAndSuffix(pattern string) (prefix, suffix string, err error) {
-	for i := 0; i < len(pattern); i++ {
-		if IsPathSeparator(pattern[i]) {
-			return "", "", errPatternHasSeparator
-		}
+	if containsSeparator(pattern) {
+		return "", "", errPatternHasSeparator
 	}
 	if pos := lastIndex(pattern, '*'); pos != -1 {
 		prefix, suffix = pattern[:pos], pattern[pos+1:]
@@ -110,6 +108,84 @@ func MkdirTemp(dir, pattern string) (string, error) {
 	}
 }
 
+// TempDirFor returns the directory in which to create a temporary file
+// that will later be renamed to finalPath. The returned directory is the
+// one that will contain finalPath, so the temporary file is on the same
+// file system and the final Rename is atomic and cannot fail because the
+// source and destination are on different devices.
+//
+// The result is meant to be passed as the dir argument of CreateTemp
+// or MkdirTemp. TempDirFor does not access the file system.
+func TempDirFor(finalPath string) string {
+	return dirname(finalPath)
+}
+
+// AvailableName returns a name, based on name, for a file in dir that
+// does not currently exist. If name itself is free it is returned
+// unchanged; otherwise AvailableName inserts " (N)" before the
+// extension, for the smallest N from 1 up that gives a free name, so
+// that "file.txt" becomes "file (1).txt", "file (2).txt" and so on, as
+// web browsers name downloads. The extension starts at the final dot,
+// or at the dot of a preceding part listed in multiExts, so that with
+// multiExts ".tar", "src.tar.gz" becomes "src (1).tar.gz"; a leading
+// dot, as in ".profile", does not begin an extension. The result is a
+// name within dir, not a path.
+//
+// The result is only advisory: another process may create a file with
+// the same name before the caller does. To claim a name atomically,
+// create the file with O_CREATE|O_EXCL and call AvailableName again if
+// that fails with ErrExist.
+func AvailableName(dir, name string, multiExts ...string) (string, error) {
+	if name == "" || name == "." || name == ".." || containsSeparator(name) {
+		return "", &PathError{Op: "availablename", Path: name, Err: ErrInvalid}
+	}
+	if dir == "" {
+		dir = "."
+	}
+	base, ext := splitExt(name, multiExts)
+	for i := 0; ; i++ {
+		try := name
+		if i > 0 {
+			try = base + " (" + itoa.Itoa(i) + ")" + ext
+		}
+		_, err := Lstat(joinPath(dir, try))
+		if IsNotExist(err) {
+			return try, nil
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+}
+
+// splitExt splits name before its extension, as described
+// by AvailableName.
+func splitExt(name string, multiExts []string) (base, ext string) {
+	i := lastIndex(name, '.')
+	if i <= 0 {
+		return name, ""
+	}
+	if j := lastIndex(name[:i], '.'); j > 0 {
+		for _, m := range multiExts {
+			if name[j:i] == m {
+				i = j
+				break
+			}
+		}
+	}
+	return name[:i], name[i:]
+}
+
+// containsSeparator reports whether s contains a path separator.
+func containsSeparator(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if IsPathSeparator(s[i]) {
+			return true
+		}
+	}
+	return false
+}
+
 func joinPath(dir, name string) string {
 	if len(dir) > 0 && IsPathSeparator(dir[len(dir)-1]) {
 		return dir + name
@@ -126,3 +202,16 @@ func lastIndex(s string, sep byte) int {
 	}
 	return -1
 }
+
+// isDigits reports whether s is a non-empty string of decimal digits.
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -191,3 +191,67 @@ func TestMkdirTempBadPattern(t *testing.T) {
 		})
 	}
 }
+
+func TestTempDirFor(t *testing.T) {
+	sep := string(PathSeparator)
+	tests := []struct {
+		final, want string
+	}{
+		{"file", "."},
+		{"dir" + sep + "file", "dir"},
+		{"a" + sep + "b" + sep + "file", "a" + sep + "b"},
+	}
+	for _, tt := range tests {
+		if got := TempDirFor(tt.final); got != tt.want {
+			t.Errorf("TempDirFor(%q) = %q, want %q", tt.final, got, tt.want)
+		}
+	}
+
+	dir := t.TempDir()
+	final := filepath.Join(dir, "final")
+	f, err := CreateTemp(TempDirFor(final), "tmp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+	if filepath.Dir(f.Name()) != dir {
+		t.Errorf("temporary file %q is not in %q", f.Name(), dir)
+	}
+	if err := Rename(f.Name(), final); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAvailableName(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"file.txt", "file (1).txt", "src.tar.gz", "src.cpio.xz", "noext", ".profile", "a.b.c"} {
+		if err := WriteFile(filepath.Join(dir, name), nil, 0666); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tests := []struct {
+		name      string
+		multiExts []string
+		want      string
+	}{
+		{"new.txt", nil, "new.txt"},
+		{"file.txt", nil, "file (2).txt"},
+		{"src.tar.gz", nil, "src.tar (1).gz"},
+		{"src.tar.gz", []string{".tar"}, "src (1).tar.gz"},
+		{"src.cpio.xz", []string{".tar", ".cpio"}, "src (1).cpio.xz"},
+		{"noext", nil, "noext (1)"},
+		{".profile", nil, ".profile (1)"},
+		{"a.b.c", []string{".tar"}, "a.b (1).c"},
+	}
+	for _, tt := range tests {
+		got, err := AvailableName(dir, tt.name, tt.multiExts...)
+		if err != nil || got != tt.want {
+			t.Errorf("AvailableName(%q, %q) = %q, %v; want %q, nil", tt.name, tt.multiExts, got, err, tt.want)
+		}
+	}
+	for _, name := range []string{"", "..", "a" + string(PathSeparator) + "b"} {
+		if _, err := AvailableName(dir, name); !errors.Is(err, ErrInvalid) {
+			t.Errorf("AvailableName(%q): got %v, want ErrInvalid", name, err)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+// ExpandTilde expands a leading ~ in path, as a Unix shell does. A
+// path consisting of ~ alone, or beginning with ~ and a separator, has
+// the ~ replaced by the current user's home directory, as returned by
+// UserHomeDir. A path beginning with ~name has ~name replaced by the
+// home directory of the user with that name. Other paths, including
+// those with a ~ elsewhere, are returned unchanged.
+//
+// On Unix systems other users' home directories are looked up in
+// /etc/passwd, so users known only to a directory service such as LDAP
+// are not found; os/user can find those. On other systems expanding
+// ~name returns an error wrapping ErrUnsupported.
+func ExpandTilde(path string) (string, error) {
+	if path == "" || path[0] != '~' {
+		return path, nil
+	}
+	i := 1
+	for i < len(path) && !IsPathSeparator(path[i]) {
+		i++
+	}
+	var home string
+	var err error
+	if i == 1 {
+		home, err = UserHomeDir()
+	} else {
+		home, err = lookupHomeDir(path[1:i])
+	}
+	if err != nil {
+		return "", &PathError{Op: "expandtilde", Path: path, Err: err}
+	}
+	return home + path[i:], nil
+}
@@ -0,0 +1,48 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build aix || darwin || dragonfly || freebsd || (js && wasm) || linux || netbsd || openbsd || solaris
+// +build aix darwin dragonfly freebsd js,wasm linux netbsd openbsd solaris
+
+package os
+
+import (
+	"errors"
+	"internal/bytealg"
+)
+
+var errUnknownUser = errors.New("unknown user")
+
+// lookupHomeDir returns the home directory of the named user,
+// according to /etc/passwd.
+func lookupHomeDir(name string) (string, error) {
+	data, err := ReadFile("/etc/passwd")
+	if err != nil {
+		return "", err
+	}
+	for len(data) > 0 {
+		line := data
+		if i := bytealg.IndexByte(data, '\n'); i >= 0 {
+			line, data = data[:i], data[i+1:]
+		} else {
+			data = nil
+		}
+		// name:password:uid:gid:gecos:home:shell
+		var fields [7][]byte
+		n := 0
+		for n < len(fields)-1 {
+			i := bytealg.IndexByte(line, ':')
+			if i < 0 {
+				break
+			}
+			fields[n], line = line[:i], line[i+1:]
+			n++
+		}
+		fields[n] = line
+		if n >= 5 && string(fields[0]) == name {
+			return string(fields[5]), nil
+		}
+	}
+	return "", errUnknownUser
+}
@@ -0,0 +1,50 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+import "time"
+
+// Timerfd returns a file that becomes readable when a timer expires,
+// for programs that wait for timers alongside other file descriptors.
+// The timer first expires after initial, or after interval if initial
+// is zero, and then every interval; an interval of zero makes a
+// one-shot timer, and a timer with both durations zero is disarmed
+// until ResetTimerfd is called. The timer runs on a clock that is not
+// affected by changes to the system time. The file works with the
+// runtime poller, so reads honor SetReadDeadline. ReadTimerfd returns
+// the number of expirations since the previous read. A negative
+// duration is an error wrapping ErrInvalid.
+//
+// Timerfd is only supported on Linux. On other systems it returns an
+// error wrapping ErrUnsupported.
+func Timerfd(interval, initial time.Duration) (*File, error) {
+	if interval < 0 || initial < 0 {
+		return nil, NewSyscallError("timerfd_settime", ErrInvalid)
+	}
+	return timerfd(interval, initial)
+}
+
+// ResetTimerfd rearms the timer f, which must have been returned by
+// Timerfd, as if it had just been created with the given durations.
+// Expirations that have not been read are discarded.
+func (f *File) ResetTimerfd(interval, initial time.Duration) error {
+	if err := f.checkValid("timerfd_settime"); err != nil {
+		return err
+	}
+	if interval < 0 || initial < 0 {
+		return f.wrapErr("timerfd_settime", ErrInvalid)
+	}
+	return f.resetTimerfd(interval, initial)
+}
+
+// ReadTimerfd waits for the timer f, which must have been returned by
+// Timerfd, to expire, and returns the number of times it has expired
+// since it was last read or reset.
+func (f *File) ReadTimerfd() (expirations uint64, err error) {
+	if err := f.checkValid("read"); err != nil {
+		return 0, err
+	}
+	return f.readCounter()
+}
@@ -0,0 +1,62 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+import (
+	"internal/syscall/unix"
+	"runtime"
+	"syscall"
+	"time"
+	"unsafe"
+)
+
+func timerfd(interval, initial time.Duration) (*File, error) {
+	fd, err := unix.TimerfdCreate(unix.CLOCK_MONOTONIC, syscall.O_NONBLOCK|syscall.O_CLOEXEC)
+	if err != nil {
+		return nil, NewSyscallError("timerfd_create", err)
+	}
+	f := newFile(uintptr(fd), "timerfd", kindNonBlock)
+	if err := f.resetTimerfd(interval, initial); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return f, nil
+}
+
+func (f *File) resetTimerfd(interval, initial time.Duration) error {
+	if initial == 0 {
+		initial = interval
+	}
+	spec := unix.Itimerspec{
+		Interval: syscall.NsecToTimespec(int64(interval)),
+		Value:    syscall.NsecToTimespec(int64(initial)),
+	}
+	var e error
+	if err := f.pfd.RawControl(func(fd uintptr) {
+		e = unix.TimerfdSettime(int(fd), 0, &spec)
+	}); err != nil {
+		return f.wrapErr("timerfd_settime", err)
+	}
+	runtime.KeepAlive(f)
+	if e != nil {
+		return f.wrapErr("timerfd_settime", e)
+	}
+	return nil
+}
+
+// readCounter reads the 8-byte counter that timerfd and eventfd files
+// return from a read.
+func (f *File) readCounter() (uint64, error) {
+	var v uint64
+	b := (*[8]byte)(unsafe.Pointer(&v))[:]
+	n, err := f.read(b)
+	if err != nil {
+		return 0, f.wrapErr("read", err)
+	}
+	if n != len(b) {
+		return 0, f.wrapErr("read", syscall.EINVAL)
+	}
+	return v, nil
+}
@@ -0,0 +1,22 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !linux
+// +build !linux
+
+package os
+
+import "time"
+
+func timerfd(interval, initial time.Duration) (*File, error) {
+	return nil, NewSyscallError("timerfd_create", ErrUnsupported)
+}
+
+func (f *File) resetTimerfd(interval, initial time.Duration) error {
+	return f.wrapErr("timerfd_settime", ErrUnsupported)
+}
+
+func (f *File) readCounter() (uint64, error) {
+	return 0, f.wrapErr("read", ErrUnsupported)
+}
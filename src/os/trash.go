@@ -0,0 +1,29 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+import "errors"
+
+// Trash moves the named file or directory to the user's trash, from
+// which it can be restored with the desktop's file manager.
+//
+// On Unix systems other than macOS, Trash follows the freedesktop.org
+// trash specification. It uses the trash in $XDG_DATA_HOME (by default
+// $HOME/.local/share/Trash) for files on the same file system, and
+// otherwise a .Trash/$uid or .Trash-$uid directory at the top of the
+// file's file system. On macOS, Trash moves the file to $HOME/.Trash,
+// or to the .Trashes/$uid directory of another volume, without the
+// metadata needed for the Finder's Put Back. On Windows, Trash uses the
+// Recycle Bin of fixed drives, and Windows may ask the user before
+// deleting a file that is too large to recycle.
+//
+// Trash never deletes the file if it cannot be moved to a trash. If
+// there is no trash available for the file, Trash returns an error of
+// type *PathError; on systems with no trash it wraps ErrUnsupported.
+func Trash(name string) error {
+	return trash(name)
+}
+
+var errNoTrash = errors.New("no trash directory available")
@@ -0,0 +1,46 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+import "internal/itoa"
+
+func trash(name string) error {
+	abs, dev, top, err := trashTarget(name)
+	if err != nil {
+		return err
+	}
+	if abs == top {
+		return &PathError{Op: "trash", Path: name, Err: ErrInvalid}
+	}
+	dir := ""
+	if home := Getenv("HOME"); home != "" && onVolume(home+"/.Trash", dev) {
+		dir = home + "/.Trash"
+	} else if d := joinPath(top, ".Trashes/"+itoa.Itoa(Getuid())); onVolume(d, dev) {
+		dir = d
+	} else {
+		return &PathError{Op: "trash", Path: name, Err: errNoTrash}
+	}
+
+	// Name clashes are resolved as the Finder does, by appending
+	// a number before the extension.
+	base := basename(abs)
+	stem, ext := base, ""
+	if i := lastIndex(base, '.'); i > 0 {
+		stem, ext = base[:i], base[i:]
+	}
+	for i := 1; ; i++ {
+		target := dir + "/" + base
+		if i > 1 {
+			target = dir + "/" + stem + " " + itoa.Itoa(i) + ext
+		}
+		if _, err := Lstat(target); err == nil {
+			continue
+		}
+		if err := Rename(abs, target); err != nil {
+			return &PathError{Op: "trash", Path: name, Err: underlyingError(err)}
+		}
+		return nil
+	}
+}
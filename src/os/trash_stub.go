@@ -0,0 +1,12 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build android || (js && wasm) || plan9
+// +build android js,wasm plan9
+
+package os
+
+func trash(name string) error {
+	return &PathError{Op: "trash", Path: name, Err: ErrUnsupported}
+}
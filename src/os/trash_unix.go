@@ -0,0 +1,54 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build aix || darwin || dragonfly || freebsd || (linux && !android) || netbsd || openbsd || solaris
+// +build aix darwin dragonfly freebsd linux,!android netbsd openbsd solaris
+
+package os
+
+// trashTarget returns the absolute path of name, the file system
+// holding it, and the top directory of that file system.
+func trashTarget(name string) (abs string, dev uint64, top string, err error) {
+	abs = name
+	if len(abs) == 0 || abs[0] != '/' {
+		wd, err := Getwd()
+		if err != nil {
+			return "", 0, "", err
+		}
+		abs = joinPath(wd, name)
+	}
+	fi, err := Lstat(abs)
+	if err != nil {
+		return "", 0, "", err
+	}
+	dev, ok := volumeID(fi)
+	if !ok {
+		return "", 0, "", &PathError{Op: "trash", Path: name, Err: errNoTrash}
+	}
+	// Climb until the parent is on another file system.
+	top = abs
+	for top != "/" {
+		parent := dirname(top)
+		pfi, err := Stat(parent)
+		if err != nil {
+			return "", 0, "", err
+		}
+		if pdev, _ := volumeID(pfi); pdev != dev {
+			break
+		}
+		top = parent
+	}
+	return abs, dev, top, nil
+}
+
+// onVolume reports whether the directory dir exists on the file
+// system dev.
+func onVolume(dir string, dev uint64) bool {
+	fi, err := Stat(dir)
+	if err != nil || !fi.IsDir() {
+		return false
+	}
+	d, ok := volumeID(fi)
+	return ok && d == dev
+}
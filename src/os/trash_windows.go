@@ -0,0 +1,46 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+import (
+	"internal/syscall/windows"
+	"syscall"
+)
+
+func trash(name string) error {
+	abs, err := syscall.FullPath(name)
+	if err != nil {
+		return &PathError{Op: "trash", Path: name, Err: err}
+	}
+	if _, err := Lstat(abs); err != nil {
+		return err
+	}
+	// Only fixed drives have a Recycle Bin. Elsewhere SHFileOperation
+	// would delete the file outright.
+	root, err := syscall.UTF16PtrFromString(volumeName(abs) + `\`)
+	if err != nil {
+		return &PathError{Op: "trash", Path: name, Err: err}
+	}
+	if windows.GetDriveType(root) != windows.DRIVE_FIXED {
+		return &PathError{Op: "trash", Path: name, Err: errNoTrash}
+	}
+	// The list of files is terminated by an extra NUL.
+	from, err := syscall.UTF16FromString(abs)
+	if err != nil {
+		return &PathError{Op: "trash", Path: name, Err: err}
+	}
+	from = append(from, 0)
+	const flags = windows.FOF_ALLOWUNDO | windows.FOF_NOCONFIRMATION | windows.FOF_SILENT |
+		windows.FOF_NOERRORUI | windows.FOF_WANTNUKEWARNING
+	op := windows.SHFILEOPSTRUCT{
+		Func:  windows.FO_DELETE,
+		From:  &from[0],
+		Flags: flags,
+	}
+	if ret := windows.SHFileOperation(&op); ret != 0 {
+		return &PathError{Op: "trash", Path: name, Err: syscall.Errno(ret)}
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build aix || dragonfly || freebsd || (linux && !android) || netbsd || openbsd || solaris
+// +build aix dragonfly freebsd linux,!android netbsd openbsd solaris
+
+package os
+
+import (
+	"internal/itoa"
+	"time"
+)
+
+// trash implements the freedesktop.org trash specification,
+// https://specifications.freedesktop.org/trash-spec/trashspec-latest.html.
+func trash(name string) error {
+	abs, dev, top, err := trashTarget(name)
+	if err != nil {
+		return err
+	}
+	if abs == top {
+		return &PathError{Op: "trash", Path: name, Err: ErrInvalid}
+	}
+
+	// Files on the same file system as the home trash go there,
+	// recorded by absolute path.
+	if home := homeTrash(); home != "" {
+		if err := makeTrashDir(home, false); err == nil && onVolume(home, dev) {
+			return moveToTrash(name, abs, home, abs)
+		}
+	}
+
+	// Otherwise use the trash at the top of the file system,
+	// recorded relative to the top.
+	uid := itoa.Itoa(Getuid())
+	rel := abs[len(top):]
+	for len(rel) > 0 && rel[0] == '/' {
+		rel = rel[1:]
+	}
+	if fi, err := Lstat(joinPath(top, ".Trash")); err == nil && fi.IsDir() && fi.Mode()&ModeSticky != 0 {
+		dir := joinPath(top, ".Trash/"+uid)
+		if err := makeTrashDir(dir, true); err == nil {
+			return moveToTrash(name, abs, dir, rel)
+		}
+	}
+	dir := joinPath(top, ".Trash-"+uid)
+	if err := makeTrashDir(dir, true); err != nil {
+		return &PathError{Op: "trash", Path: name, Err: errNoTrash}
+	}
+	return moveToTrash(name, abs, dir, rel)
+}
+
+// homeTrash returns the user's home trash directory, or "" if there is
+// no home directory.
+func homeTrash() string {
+	data := Getenv("XDG_DATA_HOME")
+	if data == "" || data[0] != '/' {
+		home := Getenv("HOME")
+		if home == "" {
+			return ""
+		}
+		data = home + "/.local/share"
+	}
+	return data + "/Trash"
+}
+
+// makeTrashDir creates the trash directory dir with its files and info
+// subdirectories. If strict is set, dir itself must not be a symbolic
+// link and must belong to the user, as required for trash directories
+// at the top of shared file systems.
+func makeTrashDir(dir string, strict bool) error {
+	if strict {
+		if err := Mkdir(dir, 0700); err != nil && !IsExist(err) {
+			return err
+		}
+		fi, err := Lstat(dir)
+		if err != nil {
+			return err
+		}
+		if uid, _, ok := owner(fi); !fi.IsDir() || !ok || uid != Getuid() {
+			return &PathError{Op: "trash", Path: dir, Err: errNoTrash}
+		}
+	}
+	if err := MkdirAll(dir+"/files", 0700); err != nil {
+		return err
+	}
+	return MkdirAll(dir+"/info", 0700)
+}
+
+// moveToTrash moves abs into the trash directory dir, recording
+// infoPath as its original location.
+func moveToTrash(name, abs, dir, infoPath string) error {
+	base := basename(abs)
+	info := trashInfo(infoPath, time.Now())
+	for i := 1; ; i++ {
+		entry := base
+		if i > 1 {
+			entry += "." + itoa.Itoa(i)
+		}
+		// Creating the info file claims the name.
+		infoName := dir + "/info/" + entry + ".trashinfo"
+		f, err := OpenFile(infoName, O_WRONLY|O_CREATE|O_EXCL, 0600)
+		if IsExist(err) {
+			continue
+		}
+		if err != nil {
+			return &PathError{Op: "trash", Path: name, Err: underlyingError(err)}
+		}
+		_, err = f.WriteString(info)
+		if err1 := f.Close(); err == nil {
+			err = err1
+		}
+		if err != nil {
+			Remove(infoName)
+			return &PathError{Op: "trash", Path: name, Err: underlyingError(err)}
+		}
+		// Never overwrite a trashed file whose info file was lost.
+		target := dir + "/files/" + entry
+		if _, err := Lstat(target); err == nil {
+			Remove(infoName)
+			continue
+		}
+		if err := Rename(abs, target); err != nil {
+			Remove(infoName)
+			return &PathError{Op: "trash", Path: name, Err: underlyingError(err)}
+		}
+		return nil
+	}
+}
+
+// trashInfo returns the contents of a .trashinfo file.
+func trashInfo(path string, t time.Time) string {
+	const hex = "0123456789ABCDEF"
+	b := []byte("[Trash Info]\nPath=")
+	for i := 0; i < len(path); i++ {
+		c := path[i]
+		switch {
+		case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9',
+			c == '-', c == '_', c == '.', c == '~', c == '/':
+			b = append(b, c)
+		default:
+			b = append(b, '%', hex[c>>4], hex[c&0xF])
+		}
+	}
+	b = append(b, "\nDeletionDate="...)
+	b = t.AppendFormat(b, "2006-01-02T15:04:05")
+	b = append(b, '\n')
+	return string(b)
+}
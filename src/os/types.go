@@ -7,6 +7,7 @@ package os
 import (
 	"io/fs"
 	"syscall"
+	"time"
 )
 
 // Getpagesize returns the underlying system's memory page size.
@@ -72,3 +73,46 @@ func SameFile(fi1, fi2 FileInfo) bool {
 	}
 	return sameFile(fs1, fs2)
 }
+
+// SameContents reports whether fi1 and fi2 appear to describe files
+// with the same contents: they have the same type, the same size and
+// the same modification time. It is a cheap heuristic for detecting
+// changed files, as used by backup and synchronization tools; it does
+// not read or hash the contents, so files that differ but have equal
+// sizes and times are reported as the same.
+//
+// File systems record modification times with different precision.
+// If either time has no sub-second part, SameContents compares the
+// times to the second, so that a copy on a file system with one-second
+// resolution matches its original. Coarser resolutions, such as the
+// two seconds used by FAT, require SameContentsWithin.
+func SameContents(fi1, fi2 FileInfo) bool {
+	return SameContentsWithin(fi1, fi2, 0)
+}
+
+// SameContentsWithin is like SameContents, but also treats the
+// modification times as equal if they differ by no more than skew.
+func SameContentsWithin(fi1, fi2 FileInfo, skew time.Duration) bool {
+	if fi1.Mode().Type() != fi2.Mode().Type() || fi1.Size() != fi2.Size() {
+		return false
+	}
+	d := modTimeDiff(fi1, fi2)
+	return -skew <= d && d <= skew
+}
+
+// NewerThan reports whether fi1 was modified after fi2, comparing
+// modification times with the same allowance for file system
+// precision as SameContents.
+func NewerThan(fi1, fi2 FileInfo) bool {
+	return modTimeDiff(fi1, fi2) > 0
+}
+
+// modTimeDiff returns the modification time of fi1 minus that of fi2,
+// comparing whole seconds if either time has no sub-second part.
+func modTimeDiff(fi1, fi2 FileInfo) time.Duration {
+	t1, t2 := fi1.ModTime(), fi2.ModTime()
+	if t1.Nanosecond() == 0 || t2.Nanosecond() == 0 {
+		t1, t2 = t1.Truncate(time.Second), t2.Truncate(time.Second)
+	}
+	return t1.Sub(t2)
+}
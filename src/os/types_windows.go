@@ -162,7 +162,7 @@ func (fs *fileStat) loadFileId() error {
 	} else {
 		path = fs.path
 	}
-	pathp, err := syscall.UTF16PtrFromString(path)
+	pathp, err := syscall.UTF16PtrFromString(fixLongPath(path))
 	if err != nil {
 		return err
 	}
@@ -225,7 +225,7 @@ func sameFile(fs1, fs2 *fileStat) bool {
 	return fs1.vol == fs2.vol && fs1.idxhi == fs2.idxhi && fs1.idxlo == fs2.idxlo
 }
 
-// For testing.
+// atime returns the access time recorded in fi.
 func atime(fi FileInfo) time.Time {
 	return time.Unix(0, fi.Sys().(*syscall.Win32FileAttributeData).LastAccessTime.Nanoseconds())
 }
@@ -0,0 +1,166 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+import (
+	"io/fs"
+	"sync"
+)
+
+// WalkDirParallel walks the file tree rooted at root, calling fn for
+// each file or directory in the tree, including root, as
+// filepath.WalkDir does. Unlike filepath.WalkDir, WalkDirParallel
+// reads up to concurrency directories at a time, so fn must be safe
+// for concurrent use. A concurrency less than 1 is treated as 1.
+//
+// The order of the calls to fn is not defined, except that a directory
+// is visited before its contents, and the entries of a single
+// directory are visited one at a time in lexical order, so SkipDir has
+// its usual meaning. SkipAll stops the walk. Any other error returned
+// by fn stops the walk as soon as possible, and the first such error
+// is returned; calls to fn already in progress on other goroutines are
+// allowed to finish.
+//
+// Paths passed to fn are formed by joining root and the names of the
+// entries with the path separator. WalkDirParallel does not follow
+// symbolic links.
+func WalkDirParallel(root string, concurrency int, fn fs.WalkDirFunc) error {
+	info, err := Lstat(root)
+	if err == nil {
+		err = fn(root, fs.FileInfoToDirEntry(info), nil)
+	} else {
+		err = fn(root, nil, err)
+	}
+	if err != nil || info == nil || !info.IsDir() {
+		if err == fs.SkipDir || err == fs.SkipAll {
+			err = nil
+		}
+		return err
+	}
+
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	w := &parallelWalker{fn: fn}
+	w.cond.L = &w.mu
+	w.push(root, fs.FileInfoToDirEntry(info))
+	var wg sync.WaitGroup
+	wg.Add(concurrency)
+	for i := 0; i < concurrency; i++ {
+		go func() {
+			defer wg.Done()
+			w.work()
+		}()
+	}
+	wg.Wait()
+	return w.err
+}
+
+type parallelWalker struct {
+	fn fs.WalkDirFunc
+
+	mu      sync.Mutex
+	cond    sync.Cond
+	queue   []walkTask // directories waiting to be read
+	pending int        // directories queued or being read
+	stopped bool
+	err     error // first error; nil for SkipAll
+}
+
+// A walkTask is a directory whose entries are to be visited.
+type walkTask struct {
+	path string
+	d    fs.DirEntry
+}
+
+func (w *parallelWalker) push(path string, d fs.DirEntry) {
+	w.mu.Lock()
+	w.queue = append(w.queue, walkTask{path, d})
+	w.pending++
+	w.mu.Unlock()
+	w.cond.Signal()
+}
+
+// stop ends the walk, recording err unless it is SkipAll.
+func (w *parallelWalker) stop(err error) {
+	w.mu.Lock()
+	if !w.stopped {
+		w.stopped = true
+		if err != fs.SkipAll {
+			w.err = err
+		}
+	}
+	w.mu.Unlock()
+	w.cond.Broadcast()
+}
+
+func (w *parallelWalker) isStopped() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.stopped
+}
+
+// work reads queued directories until the walk is done.
+func (w *parallelWalker) work() {
+	for {
+		w.mu.Lock()
+		for len(w.queue) == 0 && w.pending > 0 && !w.stopped {
+			w.cond.Wait()
+		}
+		if w.stopped || w.pending == 0 {
+			w.mu.Unlock()
+			return
+		}
+		t := w.queue[len(w.queue)-1]
+		w.queue = w.queue[:len(w.queue)-1]
+		w.mu.Unlock()
+
+		w.walkDir(t.path, t.d)
+
+		w.mu.Lock()
+		w.pending--
+		done := w.pending == 0
+		w.mu.Unlock()
+		if done {
+			w.cond.Broadcast()
+		}
+	}
+}
+
+// walkDir visits the entries of the directory path, which fn has
+// already visited, and queues its subdirectories.
+func (w *parallelWalker) walkDir(path string, d fs.DirEntry) {
+	entries, err := ReadDir(path)
+	if err != nil {
+		// Second call, to report the ReadDir error. The entries
+		// that were read are still visited, as in filepath.WalkDir.
+		if err := w.fn(path, d, err); err != nil {
+			if err != fs.SkipDir {
+				w.stop(err)
+			}
+			return
+		}
+	}
+	for _, e := range entries {
+		if w.isStopped() {
+			return
+		}
+		name := joinPath(path, e.Name())
+		if err := w.fn(name, e, nil); err != nil {
+			if err == fs.SkipDir {
+				if e.IsDir() {
+					continue
+				}
+				// Skip the rest of this directory.
+				return
+			}
+			w.stop(err)
+			return
+		}
+		if e.IsDir() {
+			w.push(name, e)
+		}
+	}
+}
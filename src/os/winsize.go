@@ -0,0 +1,36 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+// SetWinsize sets the size of the terminal f refers to, in character
+// rows and columns and, where meaningful, in pixels. It is typically
+// called on the master side of a pseudo-terminal opened with OpenPty,
+// both when starting a program on it and whenever the controlling
+// terminal of the calling process is resized, as reported by SIGWINCH.
+// A change in size sends SIGWINCH to the foreground process group of
+// the terminal, so that full-screen programs can redraw.
+//
+// On Windows, Plan 9, AIX and js/wasm, SetWinsize returns an error
+// wrapping ErrUnsupported. Any other error is of type *PathError.
+func (f *File) SetWinsize(rows, cols, xpixel, ypixel uint16) error {
+	if err := f.checkValid("ioctl"); err != nil {
+		return err
+	}
+	return f.setWinsize(rows, cols, xpixel, ypixel)
+}
+
+// GetWinsize returns the size of the terminal f refers to, as set by
+// SetWinsize. Calling it on Stdin, for instance, returns the size of
+// the terminal the program is running in, which can be copied to a
+// pseudo-terminal with SetWinsize.
+//
+// On Windows, Plan 9, AIX and js/wasm, GetWinsize returns an error
+// wrapping ErrUnsupported. Any other error is of type *PathError.
+func (f *File) GetWinsize() (rows, cols, xpixel, ypixel uint16, err error) {
+	if err := f.checkValid("ioctl"); err != nil {
+		return 0, 0, 0, 0, err
+	}
+	return f.getWinsize()
+}
@@ -0,0 +1,16 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build aix || js || plan9 || windows
+// +build aix js plan9 windows
+
+package os
+
+func (f *File) setWinsize(rows, cols, xpixel, ypixel uint16) error {
+	return f.wrapErr("ioctl", ErrUnsupported)
+}
+
+func (f *File) getWinsize() (rows, cols, xpixel, ypixel uint16, err error) {
+	return 0, 0, 0, 0, f.wrapErr("ioctl", ErrUnsupported)
+}
@@ -0,0 +1,51 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd || solaris
+// +build darwin dragonfly freebsd linux netbsd openbsd solaris
+
+package os
+
+import (
+	"internal/syscall/unix"
+	"runtime"
+	"syscall"
+	"unsafe"
+)
+
+// winsize is struct winsize, which has the same layout on all
+// supported systems.
+type winsize struct {
+	row    uint16
+	col    uint16
+	xpixel uint16
+	ypixel uint16
+}
+
+func (f *File) setWinsize(rows, cols, xpixel, ypixel uint16) error {
+	ws := winsize{row: rows, col: cols, xpixel: xpixel, ypixel: ypixel}
+	return f.winsizeIoctl(syscall.TIOCSWINSZ, &ws)
+}
+
+func (f *File) getWinsize() (rows, cols, xpixel, ypixel uint16, err error) {
+	var ws winsize
+	if err := f.winsizeIoctl(syscall.TIOCGWINSZ, &ws); err != nil {
+		return 0, 0, 0, 0, err
+	}
+	return ws.row, ws.col, ws.xpixel, ws.ypixel, nil
+}
+
+func (f *File) winsizeIoctl(req uint, ws *winsize) error {
+	var e error
+	if err := f.pfd.RawControl(func(fd uintptr) {
+		e = unix.IoctlPtr(int(fd), req, unsafe.Pointer(ws))
+	}); err != nil {
+		return f.wrapErr("ioctl", err)
+	}
+	runtime.KeepAlive(f)
+	if e != nil {
+		return f.wrapErr("ioctl", e)
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+import "sync"
+
+// WithWd calls fn with dir as its current working directory, and
+// returns the error fn returns. If fn panics, WithWd panics with the
+// same value.
+//
+// On Linux, WithWd runs fn on a dedicated operating system thread that
+// has its own working directory, so other goroutines, and other calls
+// to WithWd, are unaffected and may run at the same time. Only the
+// goroutine running fn sees dir: goroutines that fn starts use the
+// process's working directory. A call to Chdir or Fchdir within fn
+// changes the directory of that thread alone. The thread is discarded
+// when fn returns.
+//
+// Elsewhere, and on Linux if the thread cannot be given its own
+// working directory, WithWd changes the working directory of the whole
+// process for the duration of fn and restores it afterwards. Calls to
+// WithWd are then serialized, but goroutines that are not running
+// within WithWd see dir as the working directory while fn runs.
+func WithWd(dir string, fn func() error) error {
+	if ok, err := withWdThread(dir, fn); ok {
+		return err
+	}
+	return withWdProcess(dir, fn)
+}
+
+// wdMu serializes calls to withWdProcess.
+var wdMu sync.Mutex
+
+// withWdProcess implements WithWd by changing the working
+// directory of the process.
+func withWdProcess(dir string, fn func() error) (err error) {
+	wdMu.Lock()
+	defer wdMu.Unlock()
+
+	old, err := Getwd()
+	if err != nil {
+		return err
+	}
+	if err := Chdir(dir); err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := Chdir(old); err == nil {
+			err = cerr
+		}
+	}()
+	return fn()
+}
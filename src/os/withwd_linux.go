@@ -0,0 +1,50 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+import (
+	"runtime"
+	"syscall"
+)
+
+// withWdThread implements WithWd by running fn on a locked thread
+// whose file system attributes, including the working directory, are
+// unshared from the rest of the process. It reports false if the
+// thread could not be unshared, in which case fn has not been called.
+func withWdThread(dir string, fn func() error) (ok bool, err error) {
+	type result struct {
+		err   error
+		ok    bool
+		panic interface{}
+	}
+	c := make(chan result, 1)
+	go func() {
+		runtime.LockOSThread()
+		if err := syscall.Unshare(syscall.CLONE_FS); err != nil {
+			runtime.UnlockOSThread()
+			c <- result{}
+			return
+		}
+		// The thread no longer shares the process's working
+		// directory, so it must not run other goroutines. It is
+		// never unlocked, and the runtime terminates it when this
+		// goroutine exits.
+		if err := syscall.Chdir(dir); err != nil {
+			c <- result{err: &PathError{Op: "chdir", Path: dir, Err: err}, ok: true}
+			return
+		}
+		r := result{ok: true}
+		defer func() {
+			r.panic = recover()
+			c <- r
+		}()
+		r.err = fn()
+	}()
+	r := <-c
+	if r.panic != nil {
+		panic(r.panic)
+	}
+	return r.ok, r.err
+}
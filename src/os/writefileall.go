@@ -0,0 +1,22 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+// WriteFileAll is like WriteFile, but first creates any missing parent
+// directories of name, with mode 0777 (before umask), as MkdirAll does.
+// If a parent exists but is not a directory, the error is a *PathError
+// wrapping syscall.ENOTDIR.
+func WriteFileAll(name string, data []byte, perm FileMode) error {
+	err := WriteFile(name, data, perm)
+	if !IsNotExist(err) {
+		return err
+	}
+	if dir := parentDir(name); dir != "" {
+		if err := MkdirAll(dir, 0777); err != nil {
+			return err
+		}
+	}
+	return WriteFile(name, data, perm)
+}
@@ -0,0 +1,22 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+// WriteFileVectored is like WriteFile, but writes the concatenation of
+// bufs, without first copying them into a single slice. Where the system
+// supports it, the buffers are written with a single writev(2) system
+// call, or a few if there are more than 1024 of them; elsewhere, such as
+// on Windows, they are written one after another.
+func WriteFileVectored(name string, bufs [][]byte, perm FileMode) error {
+	f, err := OpenFile(name, O_WRONLY|O_CREATE|O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	err = f.writeBuffers(bufs)
+	if err1 := f.Close(); err1 != nil && err == nil {
+		err = err1
+	}
+	return err
+}
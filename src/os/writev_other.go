@@ -0,0 +1,19 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !darwin && !dragonfly && !freebsd && !illumos && !linux && !netbsd && !openbsd
+// +build !darwin,!dragonfly,!freebsd,!illumos,!linux,!netbsd,!openbsd
+
+package os
+
+// writeBuffers writes the concatenation of bufs to f, one buffer at a
+// time.
+func (f *File) writeBuffers(bufs [][]byte) error {
+	for _, b := range bufs {
+		if _, err := f.Write(b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
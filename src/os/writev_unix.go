@@ -0,0 +1,16 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build darwin || dragonfly || freebsd || illumos || linux || netbsd || openbsd
+// +build darwin dragonfly freebsd illumos linux netbsd openbsd
+
+package os
+
+// writeBuffers writes the concatenation of bufs to f with writev.
+func (f *File) writeBuffers(bufs [][]byte) error {
+	// Writev consumes the buffers it writes, so give it a copy.
+	v := append([][]byte(nil), bufs...)
+	_, err := f.pfd.Writev(&v)
+	return f.wrapErr("write", err)
+}
@@ -82,6 +82,11 @@ func msigrestore(sigmask sigset) {
 func clearSignalHandlers() {
 }
 
+//go:nosplit
+//go:nowritebarrierrec
+func sigfdUnblock() {
+}
+
 //go:nosplit
 func sigblock(exiting bool) {
 }
@@ -507,3 +507,50 @@ func tgkill(tgid, tid, sig int)
 func signalM(mp *m, sig int) {
 	tgkill(getpid(), int(mp.procid), sig)
 }
+
+// sigfdBlocker blocks signals in each thread for os.Signalfd.
+type sigfdBlocker struct {
+	sigs []uint32
+	set  sigset
+}
+
+// block is called on every m by syscall_runtime_doAllThreadsSyscall,
+// first with initial set on the calling m while the world is stopped.
+//go:nosplit
+//go:norace
+func (b *sigfdBlocker) block(initial bool) bool {
+	mp := getg().m
+	for _, sig := range b.sigs {
+		sigaddset(&mp.sigmask, int(sig))
+	}
+	if initial {
+		for _, sig := range b.sigs {
+			sigfdBlocked[sig] = true
+			sigaddset(&sigfdMask, int(sig))
+			sigaddset(&initSigmask, int(sig))
+		}
+		sigfdInUse = true
+	}
+	sigprocmask(_SIG_BLOCK, &b.set, nil)
+	return true
+}
+
+// os_runtime_sigblock blocks sigs in every thread, including threads
+// created later, so that they can be read from a signalfd. It reports
+// false, without blocking anything, if cgo is in use or if the runtime
+// needs to receive one of the signals itself.
+//go:linkname os_runtime_sigblock os.runtime_sigblock
+func os_runtime_sigblock(sigs []uint32) bool {
+	if iscgo {
+		return false
+	}
+	b := &sigfdBlocker{sigs: sigs}
+	for _, sig := range sigs {
+		if !sigfdBlockable(sig) {
+			return false
+		}
+		sigaddset(&b.set, int(sig))
+	}
+	syscall_runtime_doAllThreadsSyscall(b.block)
+	return true
+}
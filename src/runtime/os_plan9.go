@@ -195,6 +195,11 @@ func msigrestore(sigmask sigset) {
 func clearSignalHandlers() {
 }
 
+//go:nosplit
+//go:nowritebarrierrec
+func sigfdUnblock() {
+}
+
 func sigblock(exiting bool) {
 }
 
@@ -3959,6 +3959,7 @@ func syscall_runtime_AfterForkInChild() {
 	// When we are the child we are the only thread running,
 	// so we know that nothing else has changed gp.m.sigmask.
 	msigrestore(getg().m.sigmask)
+	sigfdUnblock()
 
 	inForkedChild = false
 }
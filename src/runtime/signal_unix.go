@@ -897,7 +897,18 @@ func ensureSigM() {
 					sigaddset(&sigBlocked, int(sig))
 				}
 			}
-			sigprocmask(_SIG_SETMASK, &sigBlocked, nil)
+			// Signals blocked for os.Signalfd stay blocked even
+			// when enabled for os/signal, so that they are only
+			// received from the signalfd.
+			mask := sigBlocked
+			if sigfdInUse {
+				for i := range sigfdBlocked {
+					if sigfdBlocked[i] {
+						sigaddset(&mask, i)
+					}
+				}
+			}
+			sigprocmask(_SIG_SETMASK, &mask, nil)
 			maskUpdatedChan <- struct{}{}
 		}
 	}()
@@ -1047,6 +1058,40 @@ func msigrestore(sigmask sigset) {
 	sigprocmask(_SIG_SETMASK, &sigmask, nil)
 }
 
+// sigfdBlocked records the signals that os.Signalfd has blocked in
+// every thread, so that they can only be received by reading a
+// signalfd. New threads block them as well, although the runtime does
+// not block them by default, and child processes unblock them.
+var sigfdBlocked [_NSIG]bool
+
+// sigfdMask is the set of signals in sigfdBlocked.
+var sigfdMask sigset
+
+// sigfdInUse reports whether any signals have been blocked for os.Signalfd.
+var sigfdInUse bool
+
+// sigfdBlockable reports whether os.Signalfd may block sig: it must be
+// a signal that programs can handle and that the runtime does not
+// itself rely on receiving.
+func sigfdBlockable(sig uint32) bool {
+	if sig >= uint32(len(sigtable)) || sig == sigPreempt {
+		return false
+	}
+	flags := sigtable[sig].flags
+	return flags&_SigNotify != 0 && flags&(_SigUnblock|_SigThrow|_SigPanic) == 0
+}
+
+// sigfdUnblock unblocks the signals blocked for os.Signalfd. It is
+// called by the child after a fork, so that the new program does not
+// inherit them.
+//go:nosplit
+//go:nowritebarrierrec
+func sigfdUnblock() {
+	if sigfdInUse {
+		sigprocmask(_SIG_UNBLOCK, &sigfdMask, nil)
+	}
+}
+
 // sigsetAllExiting is used by sigblock(true) when a thread is
 // exiting. sigset_all is defined in OS specific code, and per GOOS
 // behavior may override this default for sigsetAllExiting: see
@@ -1123,7 +1168,7 @@ func minitSignalStack() {
 func minitSignalMask() {
 	nmask := getg().m.sigmask
 	for i := range sigtable {
-		if !blockableSig(uint32(i)) {
+		if !blockableSig(uint32(i)) && !sigfdBlocked[i] {
 			sigdelset(&nmask, i)
 		}
 	}
@@ -35,7 +35,7 @@ package runtime
 
 import (
 	"runtime/internal/atomic"
-	_ "unsafe" // for go:linkname
+	"unsafe"
 )
 
 // sig handles communication between the signal handler and os/signal.
@@ -84,6 +84,8 @@ func sigsend(s uint32) bool {
 		return false
 	}
 
+	sigPipeSend(s)
+
 	// Add signal to outgoing queue.
 	for {
 		mask := sig.mask[s/32]
@@ -128,6 +130,80 @@ Send:
 	return true
 }
 
+// sigPipes holds the pipes registered by os/signal.SignalPipe. sigsend
+// writes the number of each signal that a pipe wants, as a single byte,
+// directly to the write end of the pipe, which must not block.
+//
+// The fields are accessed atomically. Pipes are only added and removed
+// by one goroutine at a time; access is controlled by the handlers
+// Mutex in os/signal.
+var sigPipes [maxSigPipes]struct {
+	fd     uint32 // write end of the pipe plus one, or 0 if the slot is free
+	wanted [(_NSIG + 31) / 32]uint32
+}
+
+// maxSigPipes is the number of pipes that can be registered at once.
+const maxSigPipes = 16
+
+// sigPipeSend writes s to the pipes that want it.
+// It runs from the signal handler, so it's limited in what it can do.
+func sigPipeSend(s uint32) {
+	bit := uint32(1) << uint(s&31)
+	for i := range sigPipes {
+		p := &sigPipes[i]
+		if atomic.Load(&p.wanted[s/32])&bit == 0 {
+			continue
+		}
+		if fd := atomic.Load(&p.fd); fd != 0 {
+			// If the pipe is full, the signal is dropped.
+			b := uint8(s)
+			write(uintptr(fd-1), unsafe.Pointer(&b), 1)
+		}
+	}
+}
+
+// Registers the write end of a pipe, fd, with sigsend and returns its
+// slot, or -1 if all slots are in use.
+//go:linkname signal_pipe_add os/signal.signal_pipe_add
+func signal_pipe_add(fd uintptr) int {
+	for i := range sigPipes {
+		if atomic.Load(&sigPipes[i].fd) == 0 {
+			atomic.Store(&sigPipes[i].fd, uint32(fd)+1)
+			return i
+		}
+	}
+	return -1
+}
+
+// Arranges for s to be written to the pipe registered in slot.
+//go:linkname signal_pipe_enable os/signal.signal_pipe_enable
+func signal_pipe_enable(slot int, s uint32) {
+	if s >= uint32(len(sig.wanted)*32) {
+		return
+	}
+	p := &sigPipes[slot]
+	w := p.wanted[s/32]
+	w |= 1 << (s & 31)
+	atomic.Store(&p.wanted[s/32], w)
+}
+
+// Unregisters the pipe in slot. When it returns, no signal handler is
+// writing to the pipe, so it can be closed.
+//go:linkname signal_pipe_remove os/signal.signal_pipe_remove
+func signal_pipe_remove(slot int) {
+	p := &sigPipes[slot]
+	for i := range p.wanted {
+		atomic.Store(&p.wanted[i], 0)
+	}
+	atomic.Store(&p.fd, 0)
+
+	// A signal handler that read the descriptor before it was cleared
+	// is counted in sig.delivering until it has written to it.
+	for atomic.Load(&sig.delivering) != 0 {
+		Gosched()
+	}
+}
+
 // sigRecvPrepareForFixup is used to temporarily wake up the
 // signal_recv() running thread while it is blocked waiting for the
 // arrival of a signal. If it causes the thread to wake up, the
@@ -42,6 +42,8 @@ func (e ErrorString) Is(target error) bool {
 	case oserror.ErrNotExist:
 		return checkErrMessageContent(e, "does not exist", "not found",
 			"has been removed", "no parent")
+	case oserror.ErrUnsupported:
+		return checkErrMessageContent(e, "not supported")
 	}
 	return false
 }
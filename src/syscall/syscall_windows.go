@@ -142,7 +142,11 @@ func (e Errno) Error() string {
 	return string(utf16.Decode(b[:n]))
 }
 
-const _ERROR_BAD_NETPATH = Errno(53)
+const (
+	_ERROR_NOT_SUPPORTED        = Errno(50)
+	_ERROR_BAD_NETPATH          = Errno(53)
+	_ERROR_CALL_NOT_IMPLEMENTED = Errno(120)
+)
 
 func (e Errno) Is(target error) bool {
 	switch target {
@@ -156,6 +160,10 @@ func (e Errno) Is(target error) bool {
 		return e == ERROR_FILE_NOT_FOUND ||
 			e == _ERROR_BAD_NETPATH ||
 			e == ERROR_PATH_NOT_FOUND
+	case oserror.ErrUnsupported:
+		return e == _ERROR_NOT_SUPPORTED ||
+			e == _ERROR_CALL_NOT_IMPLEMENTED ||
+			e == EWINDOWS
 	}
 	return false
 }